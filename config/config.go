@@ -0,0 +1,333 @@
+// Package config loads the application settings from a YAML file, applies
+// environment-variable overrides and validates the result once at startup.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the config file read when CONFIG_FILE is not set.
+const DefaultPath = "config.yaml"
+
+// developmentSessionSecret signs cookies outside production when no secret is configured.
+// Production refuses to start with it.
+const developmentSessionSecret = "development-only-session-secret" // #nosec G101
+
+// Config holds every setting the application reads at startup.
+type Config struct {
+	Env            string     `yaml:"env"`
+	ApplicationURL string     `yaml:"application_url"`
+	WebsocketURL   string     `yaml:"websocket_url"`
+	Host           string     `yaml:"host"`
+	Port           string     `yaml:"port"`
+	SessionSecret  string     `yaml:"session_secret"`
+	FrameOptions   string     `yaml:"frame_options"`
+	MeetCredsPath  string     `yaml:"meet_creds_path"`
+	MeetsPath      string     `yaml:"meets_path"`
+	DecisionLogDir string     `yaml:"decision_log_dir"`
+	Timers         Timers     `yaml:"timers"`
+	WebSocket      WebSocket  `yaml:"websocket"`
+	State          State      `yaml:"state"`
+	Redis          Redis      `yaml:"redis"`
+	OpenLifter     OpenLifter `yaml:"openlifter"`
+}
+
+// Timers holds the default meet timer durations.
+type Timers struct {
+	ResultsDisplay time.Duration `yaml:"results_display"` // how long decisions stay on the lights
+	PlatformReady  time.Duration `yaml:"platform_ready"`  // platform ready countdown
+	NextAttempt    time.Duration `yaml:"next_attempt"`    // next attempt countdown
+	DecisionChange time.Duration `yaml:"decision_change"` // how long a referee may change a submitted decision
+}
+
+// WebSocket holds the connection tunables and the broadcast backplane settings.
+type WebSocket struct {
+	WriteWait       time.Duration `yaml:"write_wait"`
+	PongWait        time.Duration `yaml:"pong_wait"`
+	PingPeriod      time.Duration `yaml:"ping_period"`
+	MaxMessageSize  int64         `yaml:"max_message_size"`
+	AllowedOrigins  []string      `yaml:"allowed_origins"`
+	AllowAllOrigins bool          `yaml:"allow_all_origins"`
+	Backplane       string        `yaml:"backplane"` // "local" or "redis"
+}
+
+// State selects where runtime state (seats, running timers) is kept.
+type State struct {
+	Store string `yaml:"store"` // "file", "memory" or "redis"
+	File  string `yaml:"file"`
+}
+
+// Redis holds the connection settings shared by the Redis state store and backplane.
+type Redis struct {
+	URL     string `yaml:"url"`
+	Prefix  string `yaml:"prefix"`
+	Channel string `yaml:"channel"`
+}
+
+// OpenLifter holds the OpenLifter integration settings.
+type OpenLifter struct {
+	WebhookURL     string `yaml:"webhook_url"`
+	DeadLetterFile string `yaml:"dead_letter_file"`
+	APIToken       string `yaml:"api_token"`
+}
+
+// Load reads the YAML file at path (a missing file is treated as empty), applies
+// environment overrides, fills in defaults and validates the result.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	cfg.applyDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Default returns the development configuration without reading any file or environment.
+func Default() *Config {
+	cfg := &Config{}
+	cfg.applyDefaults()
+	return cfg
+}
+
+// IsProduction reports whether the application runs in production mode.
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// Addr returns the host:port the HTTP server listens on.
+func (c *Config) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// applyEnv overrides file values with any environment variables that are set.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"ENV":                         &c.Env,
+		"APPLICATION_URL":             &c.ApplicationURL,
+		"WEBSOCKET_URL":               &c.WebsocketURL,
+		"APP_HOST":                    &c.Host,
+		"APP_PORT":                    &c.Port,
+		"SESSION_SECRET":              &c.SessionSecret,
+		"X_FRAME_OPTIONS":             &c.FrameOptions,
+		"MEET_CREDS_FILE":             &c.MeetCredsPath,
+		"MEETS_FILE":                  &c.MeetsPath,
+		"DECISION_LOG_DIR":            &c.DecisionLogDir,
+		"BACKPLANE":                   &c.WebSocket.Backplane,
+		"STATE_STORE":                 &c.State.Store,
+		"STATE_FILE":                  &c.State.File,
+		"REDIS_URL":                   &c.Redis.URL,
+		"REDIS_PREFIX":                &c.Redis.Prefix,
+		"REDIS_CHANNEL":               &c.Redis.Channel,
+		"OPENLIFTER_WEBHOOK_URL":      &c.OpenLifter.WebhookURL,
+		"OPENLIFTER_DEAD_LETTER_FILE": &c.OpenLifter.DeadLetterFile,
+		"OPENLIFTER_API_TOKEN":        &c.OpenLifter.APIToken,
+	}
+	for name, field := range strs {
+		if v, ok := lookup(name); ok && v != "" {
+			*field = v
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"RESULTS_DISPLAY_DURATION": &c.Timers.ResultsDisplay,
+		"PLATFORM_READY_DURATION":  &c.Timers.PlatformReady,
+		"NEXT_ATTEMPT_DURATION":    &c.Timers.NextAttempt,
+		"DECISION_CHANGE_WINDOW":   &c.Timers.DecisionChange,
+		"WS_WRITE_WAIT":            &c.WebSocket.WriteWait,
+		"WS_PONG_WAIT":             &c.WebSocket.PongWait,
+		"WS_PING_PERIOD":           &c.WebSocket.PingPeriod,
+	}
+	for name, field := range durations {
+		v, ok := lookup(name)
+		if !ok || v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*field = d
+	}
+
+	if v, ok := lookup("WS_MAX_MESSAGE_SIZE"); ok && v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("WS_MAX_MESSAGE_SIZE: %w", err)
+		}
+		c.WebSocket.MaxMessageSize = n
+	}
+	if v, ok := lookup("ALLOWED_ORIGINS"); ok && v != "" {
+		c.WebSocket.AllowedOrigins = strings.Split(v, ",")
+	}
+	if v, ok := lookup("WS_ALLOW_ALL_ORIGINS"); ok && v != "" {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("WS_ALLOW_ALL_ORIGINS: %w", err)
+		}
+		c.WebSocket.AllowAllOrigins = allow
+	}
+	return nil
+}
+
+// applyDefaults fills every unset field. URL defaults depend on the environment,
+// so Env is resolved first.
+func (c *Config) applyDefaults() {
+	setDefault(&c.Env, "development")
+	if c.IsProduction() {
+		setDefault(&c.Host, "0.0.0.0")
+	} else {
+		setDefault(&c.Host, "localhost")
+		setDefault(&c.ApplicationURL, "http://0.0.0.0:8080")
+		setDefault(&c.SessionSecret, developmentSessionSecret)
+	}
+	setDefault(&c.Port, "8080")
+	if c.WebsocketURL == "" && c.ApplicationURL != "" {
+		c.WebsocketURL = websocketURLFor(c.ApplicationURL)
+	}
+	if c.FrameOptions == "" && c.ApplicationURL != "" {
+		c.FrameOptions = "ALLOW-FROM " + c.ApplicationURL
+	}
+	setDefault(&c.MeetCredsPath, "./config/meet_creds.json")
+	setDefault(&c.MeetsPath, "./config/meets.json")
+	setDefault(&c.DecisionLogDir, "./data/decisions")
+
+	setDurationDefault(&c.Timers.ResultsDisplay, 15*time.Second)
+	setDurationDefault(&c.Timers.PlatformReady, 60*time.Second)
+	setDurationDefault(&c.Timers.NextAttempt, 60*time.Second)
+	setDurationDefault(&c.Timers.DecisionChange, 2*time.Second)
+
+	setDurationDefault(&c.WebSocket.WriteWait, 4*time.Hour)
+	setDurationDefault(&c.WebSocket.PongWait, 4*time.Hour)
+	setDurationDefault(&c.WebSocket.PingPeriod, (c.WebSocket.PongWait*9)/10)
+	if c.WebSocket.MaxMessageSize == 0 {
+		c.WebSocket.MaxMessageSize = 2048
+	}
+	setDefault(&c.WebSocket.Backplane, "local")
+
+	setDefault(&c.State.Store, "file")
+	setDefault(&c.State.File, "./data/state.json")
+	setDefault(&c.OpenLifter.DeadLetterFile, "./data/openlifter_dead_letter.jsonl")
+}
+
+// Validate reports the first setting that would stop the application from working.
+func (c *Config) Validate() error {
+	if err := checkURL("application_url", c.ApplicationURL, "http", "https"); err != nil {
+		return err
+	}
+	if err := checkURL("websocket_url", c.WebsocketURL, "ws", "wss"); err != nil {
+		return err
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("port %q must be a number between 1 and 65535", c.Port)
+	}
+	if c.SessionSecret == "" {
+		return errors.New("session_secret is required")
+	}
+	if c.IsProduction() && c.SessionSecret == developmentSessionSecret {
+		return errors.New("session_secret must be set in production")
+	}
+	if c.MeetCredsPath == "" || c.MeetsPath == "" {
+		return errors.New("meet_creds_path and meets_path are required")
+	}
+
+	for name, d := range map[string]time.Duration{
+		"timers.results_display": c.Timers.ResultsDisplay,
+		"timers.platform_ready":  c.Timers.PlatformReady,
+		"timers.next_attempt":    c.Timers.NextAttempt,
+		"timers.decision_change": c.Timers.DecisionChange,
+		"websocket.write_wait":   c.WebSocket.WriteWait,
+		"websocket.pong_wait":    c.WebSocket.PongWait,
+		"websocket.ping_period":  c.WebSocket.PingPeriod,
+	} {
+		if d <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", name, d)
+		}
+	}
+	if c.Timers.PlatformReady%time.Second != 0 || c.Timers.NextAttempt%time.Second != 0 {
+		return errors.New("timers.platform_ready and timers.next_attempt must be whole seconds")
+	}
+	if c.WebSocket.PingPeriod >= c.WebSocket.PongWait {
+		return fmt.Errorf("websocket.ping_period (%s) must be shorter than websocket.pong_wait (%s)",
+			c.WebSocket.PingPeriod, c.WebSocket.PongWait)
+	}
+	if c.WebSocket.MaxMessageSize <= 0 {
+		return fmt.Errorf("websocket.max_message_size must be positive, got %d", c.WebSocket.MaxMessageSize)
+	}
+
+	switch c.State.Store {
+	case "file", "memory", "redis":
+	default:
+		return fmt.Errorf("state.store %q must be file, memory or redis", c.State.Store)
+	}
+	switch c.WebSocket.Backplane {
+	case "local", "redis":
+	default:
+		return fmt.Errorf("websocket.backplane %q must be local or redis", c.WebSocket.Backplane)
+	}
+	if (c.State.Store == "redis" || c.WebSocket.Backplane == "redis") && c.Redis.URL == "" {
+		return errors.New("redis.url is required when state.store or websocket.backplane is redis")
+	}
+	return nil
+}
+
+// websocketURLFor derives the live-updates endpoint from the application URL.
+func websocketURLFor(applicationURL string) string {
+	ws := strings.TrimSuffix(applicationURL, "/") + "/referee-updates"
+	if strings.HasPrefix(ws, "https://") {
+		return "wss://" + strings.TrimPrefix(ws, "https://")
+	}
+	return "ws://" + strings.TrimPrefix(ws, "http://")
+}
+
+func checkURL(name, raw string, schemes ...string) error {
+	if raw == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, s := range schemes {
+		if u.Scheme == s && u.Host != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %q must be an absolute %s URL", name, raw, strings.Join(schemes, "/"))
+}
+
+func setDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+func setDurationDefault(field *time.Duration, value time.Duration) {
+	if *field == 0 {
+		*field = value
+	}
+}
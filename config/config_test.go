@@ -0,0 +1,146 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets every variable Load reads so the host environment cannot leak in.
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		"ENV", "APPLICATION_URL", "WEBSOCKET_URL", "APP_HOST", "APP_PORT", "SESSION_SECRET",
+		"X_FRAME_OPTIONS", "MEET_CREDS_FILE", "MEETS_FILE", "DECISION_LOG_DIR", "BACKPLANE",
+		"STATE_STORE", "STATE_FILE", "REDIS_URL", "REDIS_PREFIX", "REDIS_CHANNEL",
+		"OPENLIFTER_WEBHOOK_URL", "OPENLIFTER_DEAD_LETTER_FILE", "OPENLIFTER_API_TOKEN",
+		"RESULTS_DISPLAY_DURATION", "PLATFORM_READY_DURATION", "NEXT_ATTEMPT_DURATION", "DECISION_CHANGE_WINDOW",
+		"WS_WRITE_WAIT", "WS_PONG_WAIT", "WS_PING_PERIOD", "WS_MAX_MESSAGE_SIZE",
+		"ALLOWED_ORIGINS", "WS_ALLOW_ALL_ORIGINS",
+	} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad_MissingFileUsesDevelopmentDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "development", cfg.Env)
+	assert.Equal(t, "http://0.0.0.0:8080", cfg.ApplicationURL)
+	assert.Equal(t, "ws://0.0.0.0:8080/referee-updates", cfg.WebsocketURL)
+	assert.Equal(t, "localhost:8080", cfg.Addr())
+	assert.Equal(t, "./config/meet_creds.json", cfg.MeetCredsPath)
+	assert.Equal(t, "./config/meets.json", cfg.MeetsPath)
+	assert.Equal(t, 15*time.Second, cfg.Timers.ResultsDisplay)
+	assert.Equal(t, 60*time.Second, cfg.Timers.PlatformReady)
+	assert.Equal(t, 60*time.Second, cfg.Timers.NextAttempt)
+	assert.Equal(t, 2*time.Second, cfg.Timers.DecisionChange)
+	assert.Equal(t, (4*time.Hour*9)/10, cfg.WebSocket.PingPeriod)
+	assert.Equal(t, int64(2048), cfg.WebSocket.MaxMessageSize)
+	assert.Equal(t, "file", cfg.State.Store)
+	assert.NotEmpty(t, cfg.SessionSecret)
+}
+
+func TestLoad_FileThenEnvironment(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `
+application_url: https://lights.example.com
+meets_path: /etc/lights/meets.json
+timers:
+  results_display: 5s
+  platform_ready: 90s
+  decision_change: 1500ms
+websocket:
+  pong_wait: 1m
+  ping_period: 50s
+  allowed_origins: ["openlifter.example.com"]
+`)
+	t.Setenv("PLATFORM_READY_DURATION", "2m")
+	t.Setenv("APP_PORT", "9090")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "wss://lights.example.com/referee-updates", cfg.WebsocketURL)
+	assert.Equal(t, "ALLOW-FROM https://lights.example.com", cfg.FrameOptions)
+	assert.Equal(t, "/etc/lights/meets.json", cfg.MeetsPath)
+	assert.Equal(t, 5*time.Second, cfg.Timers.ResultsDisplay)
+	assert.Equal(t, 2*time.Minute, cfg.Timers.PlatformReady, "environment overrides the file")
+	assert.Equal(t, 1500*time.Millisecond, cfg.Timers.DecisionChange)
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, time.Minute, cfg.WebSocket.PongWait)
+	assert.Equal(t, []string{"openlifter.example.com"}, cfg.WebSocket.AllowedOrigins)
+}
+
+func TestLoad_ProductionRequiresURLAndSecret(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ENV", "production")
+
+	_, err := Load("")
+	assert.ErrorContains(t, err, "application_url")
+
+	t.Setenv("APPLICATION_URL", "https://lights.example.com")
+	_, err = Load("")
+	assert.ErrorContains(t, err, "session_secret")
+
+	t.Setenv("SESSION_SECRET", "a-real-secret")
+	cfg, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:8080", cfg.Addr())
+}
+
+func TestLoad_RejectsBadValues(t *testing.T) {
+	cases := map[string]struct {
+		env  map[string]string
+		file string
+		want string
+	}{
+		"unparseable duration":    {env: map[string]string{"NEXT_ATTEMPT_DURATION": "soon"}, want: "NEXT_ATTEMPT_DURATION"},
+		"bad port":                {env: map[string]string{"APP_PORT": "http"}, want: "port"},
+		"ping not before pong":    {env: map[string]string{"WS_PONG_WAIT": "10s", "WS_PING_PERIOD": "10s"}, want: "ping_period"},
+		"fractional timer":        {env: map[string]string{"PLATFORM_READY_DURATION": "1500ms"}, want: "whole seconds"},
+		"unknown store":           {env: map[string]string{"STATE_STORE": "s3"}, want: "state.store"},
+		"redis without url":       {env: map[string]string{"BACKPLANE": "redis"}, want: "redis.url"},
+		"websocket url scheme":    {env: map[string]string{"WEBSOCKET_URL": "http://x/referee-updates"}, want: "websocket_url"},
+		"negative message size":   {file: "websocket:\n  max_message_size: -1\n", want: "max_message_size"},
+		"malformed yaml":          {file: "timers: [", want: "failed to parse"},
+		"yaml duration not valid": {file: "timers:\n  results_display: forever\n", want: "failed to parse"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			path := ""
+			if tc.file != "" {
+				path = writeConfig(t, tc.file)
+			}
+			_, err := Load(path)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestLoad_RepositoryConfigIsValid(t *testing.T) {
+	clearEnv(t)
+	cfg, err := Load("../config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "development", cfg.Env)
+}
@@ -4,25 +4,49 @@ package controllers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
 	"go-ref-lights/services"
 )
 
+// platformOccupancy is one platform's seats, as the admin and sudo panels list them.
+type platformOccupancy struct {
+	Platform models.Platform
+	Seats    []seatView
+}
+
+// meetOccupancy returns the seats of every platform of a meet, in platform order.
+func meetOccupancy(service services.OccupancyServiceInterface, meet *models.Meet) []platformOccupancy {
+	var out []platformOccupancy
+	for _, platform := range meet.PlatformList() {
+		occ := service.GetOccupancy(meet.ID, platform.ID)
+		out = append(out, platformOccupancy{Platform: platform, Seats: panelSeats(meet.PositionList(), occ)})
+	}
+	return out
+}
+
 // AdminController provides admin operations for managing meets, referees, and users.
 type AdminController struct {
 	OccupancyService   services.OccupancyServiceInterface
 	PositionController *PositionController
+	DefaultTimers      config.Timers // clocks of meets without a timer profile
+	MeetCredsPath      string        // credentials file the timer profile editor saves
 }
 
 // NewAdminController initializes a new instance of AdminController.
-func NewAdminController(service services.OccupancyServiceInterface, posController *PositionController) *AdminController {
+func NewAdminController(service services.OccupancyServiceInterface, posController *PositionController, cfg *config.Config) *AdminController {
 	return &AdminController{
 		OccupancyService:   service,
 		PositionController: posController,
+		DefaultTimers:      cfg.Timers,
+		MeetCredsPath:      cfg.MeetCredsPath,
 	}
 }
 
@@ -30,35 +54,50 @@ func NewAdminController(service services.OccupancyServiceInterface, posControlle
 
 // AdminPanel renders the admin panel page, ensuring the user has admin privileges.
 // If the user is not an admin, they receive an HTTP 401 Unauthorized response.
-// Requires a meet name to be specified via query parameters or session.
+// Requires a meet ID to be specified via the `meet` query parameter or session.
 func (ac *AdminController) AdminPanel(c *gin.Context) {
 	session := sessions.Default(c)
-	adminVal := session.Get("isAdmin")
+	role := middleware.SessionRole(c)
 
 	// Moved to Debug because it's somewhat verbose
-	logger.Debug.Printf("[AdminPanel] isAdmin from session: %v", adminVal)
+	logger.Debug.Printf("[AdminPanel] role from session: %q", role)
 
-	isAdmin, ok := adminVal.(bool)
-	if !ok || !isAdmin {
+	if !role.Can(models.PermViewAdminPanel) {
 		c.String(http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	// retrieve meet name from query parameters or session
-	meetName := c.Query("meet")
-	if meetName == "" {
-		meetName, _ = session.Get("meetName").(string)
+	// retrieve meet ID from query parameters or session
+	meetID := c.Query("meet")
+	if meetID == "" {
+		meetID, _ = session.Get("meetID").(string)
 	}
-	if meetName == "" {
+	if meetID == "" {
 		c.String(http.StatusBadRequest, "Meet not specified")
 		return
 	}
 
-	occupancy := ac.OccupancyService.GetOccupancy(meetName)
 	data := gin.H{
-		"meetName":  meetName,
-		"occupancy": occupancy,
-	}
+		"meetID":   meetID,
+		"meetName": meetDisplayName(meetID),
+		"role":     role,
+		"defaultTimers": gin.H{
+			"platformReady":  int(ac.DefaultTimers.PlatformReady / time.Second),
+			"nextAttempt":    int(ac.DefaultTimers.NextAttempt / time.Second),
+			"resultsDisplay": int(ac.DefaultTimers.ResultsDisplay / time.Second),
+		},
+	}
+
+	// platforms and timer profiles live with the meet; a missing file just means one
+	// platform and no profiles yet
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		logger.Warn.Printf("[AdminPanel] Could not load meet=%s: %v", meetID, err)
+		meet = &models.Meet{ID: meetID}
+	}
+	data["platforms"] = meetOccupancy(ac.OccupancyService, meet)
+	data["timerProfiles"] = meet.TimerProfiles
+	data["activeTimerProfile"] = meet.ActiveTimerProfile
 
 	c.HTML(http.StatusOK, "admin.html", data)
 }
@@ -67,50 +106,32 @@ func (ac *AdminController) AdminPanel(c *gin.Context) {
 
 // ForceVacate allows an admin to forcibly vacate a referee from their assigned position.
 // Requires:
-// - `meetName` and `position` from the POST request body.
+// - `meetID`, `position` and, off the main platform, `platformID` from the POST request body.
 // - The user to have admin privileges.
 func (ac *AdminController) ForceVacate(c *gin.Context) {
-	session := sessions.Default(c)
-
-	// ensure user is an admin
-	isAdmin, ok := session.Get("isAdmin").(bool)
-	if !ok || !isAdmin {
+	// ensure the user may vacate seats
+	if !middleware.HasPermission(c, models.PermVacateSeats) {
 		logger.Warn.Println("[ForceVacate] Unauthorized attempt")
 		c.String(http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	meetName := c.PostForm("meetName")
+	meetID := c.PostForm("meetID")
+	platformID := c.DefaultPostForm("platformID", models.DefaultPlatformID)
 	position := c.PostForm("position")
 
 	// validate input parameters
-	if meetName == "" || position == "" {
+	if meetID == "" || position == "" {
 		c.String(http.StatusBadRequest, "Missing parameters")
 		return
 	}
 
-	// ensure `GetOccupancy` returns a valid object
-	occupancy := ac.OccupancyService.GetOccupancy(meetName)
-	if occupancy == (services.Occupancy{}) { // Check if meet exists
-		c.String(http.StatusNotFound, "Meet not found")
-		return
-	}
-
-	var occupant string
-	switch position {
-	case "left":
-		occupant = occupancy.LeftUser
-		occupancy.LeftUser = ""
-	case "center":
-		occupant = occupancy.CenterUser
-		occupancy.CenterUser = ""
-	case "right":
-		occupant = occupancy.RightUser
-		occupancy.RightUser = ""
-	default:
+	// the position must be a seat on this meet's panel
+	if !hasPosition(meetID, position) {
 		c.String(http.StatusBadRequest, "Invalid position")
 		return
 	}
+	occupant := ac.OccupancyService.GetOccupancy(meetID, platformID).UserAt(position)
 
 	// ensure there is an occupant before vacating
 	if occupant == "" {
@@ -122,59 +143,64 @@ func (ac *AdminController) ForceVacate(c *gin.Context) {
 	delete(ActiveUsers, occupant)
 
 	// update occupancy state
-	if err := ac.OccupancyService.UnsetPosition(meetName, position, occupant); err != nil {
+	if err := ac.OccupancyService.UnsetPosition(meetID, platformID, position, occupant); err != nil {
 		c.String(http.StatusInternalServerError, "Error vacating position: "+err.Error())
 		return
 	}
 
 	// ensure WebSocket Broadcast function is called
-	ac.PositionController.BroadcastOccupancy(meetName)
+	ac.PositionController.BroadcastOccupancy(meetID, platformID)
 
-	logger.Info.Printf("[ForceVacate] Admin forcibly removed %s from %s position in %s",
-		occupant, position, meetName)
+	logger.Info.Printf("[ForceVacate] Admin forcibly removed %s from %s position in %s (platform %s)",
+		occupant, position, meetID, platformID)
 
 	// Redirect back to the admin panel
-	c.Redirect(http.StatusFound, "/admin?meet="+meetName)
+	c.Redirect(http.StatusFound, "/admin?meet="+meetID)
 }
 
 // ---------------- meet management ----------------
 
 // ResetInstance performs a full reset of the meet instance.
-// This clears active users and resets all referee positions.
+// This clears active users and resets all referee positions on every platform.
 func (ac *AdminController) ResetInstance(c *gin.Context) {
 	session := sessions.Default(c)
 
-	// ensure user is an admin
-	isAdmin, ok := session.Get("isAdmin").(bool)
-	if !ok || !isAdmin {
+	// ensure the user may reset the meet
+	if !middleware.HasPermission(c, models.PermResetMeet) {
 		logger.Warn.Println("[ResetInstance] Unauthorized attempt")
 		c.String(http.StatusUnauthorized, "Unauthorized")
 		return
 	}
 
-	meetName := c.PostForm("meetName")
-	if meetName == "" {
-		meetName, _ = session.Get("meetName").(string)
+	meetID := c.PostForm("meetID")
+	if meetID == "" {
+		meetID, _ = session.Get("meetID").(string)
 	}
-	if meetName == "" {
+	if meetID == "" {
 		logger.Warn.Println("[ResetInstance] No meet specified")
 		c.String(http.StatusBadRequest, "Meet not specified")
 		return
 	}
 
-	logger.Info.Printf("[ResetInstance] Resetting meet '%s'", meetName)
+	logger.Info.Printf("[ResetInstance] Resetting meet '%s'", meetID)
 
 	// clear active users
 	ActiveUsers = make(map[string]bool)
 
 	// reset occupancy
-	ac.OccupancyService.ResetOccupancyForMeet(meetName)
-	ac.PositionController.BroadcastOccupancy(meetName)
+	ac.OccupancyService.ResetOccupancyForMeet(meetID)
+	platforms := []models.Platform{{ID: models.DefaultPlatformID}}
+	if meet, err := lookupMeet(meetID); err == nil {
+		platforms = meet.PlatformList()
+	}
+	for _, platform := range platforms {
+		ac.PositionController.BroadcastOccupancy(meetID, platform.ID)
+	}
 
-	logger.Info.Printf("[ResetInstance] Meet '%s' reset successfully", meetName)
+	logger.Info.Printf("[ResetInstance] Meet '%s' reset successfully", meetID)
 
 	// redirect back to admin panel
-	c.Redirect(http.StatusFound, "/admin?meet="+meetName)
+	c.Redirect(http.StatusFound, "/admin?meet="+meetID)
 }
 
 // ---------------- user management ----------------
@@ -184,12 +210,9 @@ func (ac *AdminController) ResetInstance(c *gin.Context) {
 // - `username` from the POST request body.
 // - the user to have admin privileges.
 func (ac *AdminController) ForceLogout(c *gin.Context) {
-	session := sessions.Default(c)
-
-	// ensure user is an admin
-	isAdmin := session.Get("isAdmin")
-	if isAdmin == nil || isAdmin != true {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin privileges required"})
+	// ensure the user may log others out
+	if !middleware.HasPermission(c, models.PermForceLogout) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Permission required: " + string(models.PermForceLogout)})
 		return
 	}
 
@@ -13,13 +13,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"go-ref-lights/config"
+	"go-ref-lights/models"
 	"go-ref-lights/services"
 )
 
 func TestAdminPanel_Unauthorized(t *testing.T) {
 	mockOccupancyService := new(MockOccupancyService)
 	mockPositionController := &PositionController{OccupancyService: mockOccupancyService}
-	adminController := NewAdminController(mockOccupancyService, mockPositionController)
+	adminController := NewAdminController(mockOccupancyService, mockPositionController, config.Default())
 
 	router := setupTestRouter(t)
 	router.GET("/admin", adminController.AdminPanel)
@@ -34,15 +36,15 @@ func TestAdminPanel_Unauthorized(t *testing.T) {
 func TestAdminPanel_MissingMeetName(t *testing.T) {
 	mockOccupancyService := new(MockOccupancyService)
 	mockPositionController := &PositionController{OccupancyService: mockOccupancyService}
-	adminController := NewAdminController(mockOccupancyService, mockPositionController)
+	adminController := NewAdminController(mockOccupancyService, mockPositionController, config.Default())
 
 	router := setupTestRouter(t)
 	router.GET("/admin", adminController.AdminPanel)
 
-	// set session with isAdmin true but an empty meetName.
+	// set session with a meet director role but an empty meetName.
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"isAdmin":  true,
-		"meetName": "",
+		"role":   "meet_director",
+		"meetID": "",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
@@ -59,25 +61,25 @@ func TestAdminPanel_MissingMeetName(t *testing.T) {
 func TestResetInstance_Success(t *testing.T) {
 	mockOccupancyService := new(MockOccupancyService)
 	mockPositionController := &PositionController{OccupancyService: mockOccupancyService}
-	adminController := NewAdminController(mockOccupancyService, mockPositionController)
+	adminController := NewAdminController(mockOccupancyService, mockPositionController, config.Default())
 
 	router := setupTestRouter(t)
 	router.POST("/reset-instance", adminController.ResetInstance)
 
 	// set expectations on the mock.
 	mockOccupancyService.
-		On("ResetOccupancyForMeet", "TestMeet").
+		On("ResetOccupancyForMeet", "testmeet").
 		Return().
 		Once()
 	mockOccupancyService.
-		On("GetOccupancy", "TestMeet").
+		On("GetOccupancy", "testmeet", models.DefaultPlatformID).
 		Return(services.Occupancy{}).
 		Once()
 
-	// set session for admin with meetName "TestMeet".
+	// set session for admin with meetName "testmeet".
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"isAdmin":  true,
-		"meetName": "TestMeet",
+		"role":   "meet_director",
+		"meetID": "testmeet",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
@@ -97,30 +99,30 @@ func TestResetInstanceHandler(t *testing.T) {
 
 	mockOccupancyService := new(MockOccupancyService)
 	mockPositionController := &PositionController{OccupancyService: mockOccupancyService}
-	adminController := NewAdminController(mockOccupancyService, mockPositionController)
+	adminController := NewAdminController(mockOccupancyService, mockPositionController, config.Default())
 
 	router := setupTestRouter(t)
 	router.POST("/admin/reset-instance", adminController.ResetInstance)
 	// set expectation for both ResetOccupancyForMeet and GetOccupancy.
 	mockOccupancyService.
-		On("ResetOccupancyForMeet", "TestMeet").
+		On("ResetOccupancyForMeet", "testmeet").
 		Return().
 		Once()
 	mockOccupancyService.
-		On("GetOccupancy", "TestMeet").
+		On("GetOccupancy", "testmeet", models.DefaultPlatformID).
 		Return(services.Occupancy{}).
 		Once()
 
 	t.Run("Admin can reset instance", func(t *testing.T) {
 		sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-			"isAdmin":  true,
-			"meetName": "TestMeet",
+			"role":   "meet_director",
+			"meetID": "testmeet",
 		})
 		if sessionCookie == nil {
 			t.Fatal("Session cookie not found")
 		}
 
-		req, _ := http.NewRequest("POST", "/admin/reset-instance", strings.NewReader("meetName=TestMeet"))
+		req, _ := http.NewRequest("POST", "/admin/reset-instance", strings.NewReader("meetID=testmeet"))
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		req.AddCookie(sessionCookie)
 		w := httptest.NewRecorder()
@@ -131,12 +133,25 @@ func TestResetInstanceHandler(t *testing.T) {
 	})
 
 	t.Run("Non-admin cannot reset instance", func(t *testing.T) {
-		req, _ := http.NewRequest("POST", "/admin/reset-instance", strings.NewReader("meetName=TestMeet"))
+		req, _ := http.NewRequest("POST", "/admin/reset-instance", strings.NewReader("meetID=testmeet"))
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusUnauthorized, w.Code, "Should return 401 Unauthorized")
 	})
+
+	t.Run("Technical controller cannot reset instance", func(t *testing.T) {
+		sessionCookie := SetSession(router, "/set-session-technical-controller", map[string]interface{}{
+			"role":   "technical_controller",
+			"meetID": "testmeet",
+		})
+		req, _ := http.NewRequest("POST", "/admin/reset-instance", strings.NewReader("meetID=testmeet"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(sessionCookie)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, "reset_meet is not granted to technical controllers")
+	})
 }
 
 func TestActiveUsersHandler_AdminCanSeeActiveUsers(t *testing.T) {
@@ -150,7 +165,7 @@ func TestActiveUsersHandler_AdminCanSeeActiveUsers(t *testing.T) {
 	ActiveUsers["referee2"] = true
 
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"isAdmin": true,
+		"role": "meet_director",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
@@ -176,7 +191,7 @@ func TestForceVacate(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockOccupancyService := new(MockOccupancyService)
 	mockPositionController := &PositionController{OccupancyService: mockOccupancyService}
-	adminController := NewAdminController(mockOccupancyService, mockPositionController)
+	adminController := NewAdminController(mockOccupancyService, mockPositionController, config.Default())
 
 	// 2) Fix the route by adding a leading slash:
 	//    previously: router.POST("force-vacate", ...)
@@ -184,30 +199,30 @@ func TestForceVacate(t *testing.T) {
 	router.POST("/force-vacate", adminController.ForceVacate)
 
 	// 3) Prepare formData once, above the request creation:
-	formData := "meetName=TestMeet&position=left"
+	formData := "meetID=testmeet&position=left"
 
-	// 4) Set up session so we have isAdmin=true and meetName="TestMeet"
+	// 4) Set up session so we have role=meet_director and meetName="testmeet"
 	sessionCookie := SetSession(router, "/set-session-force-vacate", map[string]interface{}{
-		"isAdmin":  true,
-		"meetName": "TestMeet",
+		"role":   "meet_director",
+		"meetID": "testmeet",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
 	}
 
 	// 5) Because ForceVacate calls `BroadcastOccupancy` afterwards,
-	//    `GetOccupancy("TestMeet")` will happen TWICE:
+	//    `GetOccupancy("testmeet")` will happen TWICE:
 	//    - First in ForceVacate to figure out who is occupant
 	//    - Second in BroadcastOccupancy to refresh the occupancy
 	// So we set two expectations:
-	mockOccupancyService.On("GetOccupancy", "TestMeet").Return(services.Occupancy{
-		LeftUser: "referee1",
+	mockOccupancyService.On("GetOccupancy", "testmeet", models.DefaultPlatformID).Return(services.Occupancy{
+		Seats: map[string]string{"left": "referee1"},
 	}).Once()
 	// the second call can return an empty occupancy
-	mockOccupancyService.On("GetOccupancy", "TestMeet").Return(services.Occupancy{}).Once()
+	mockOccupancyService.On("GetOccupancy", "testmeet", models.DefaultPlatformID).Return(services.Occupancy{}).Once()
 
 	// 6) We also expect "UnsetPosition" to be called exactly once.
-	mockOccupancyService.On("UnsetPosition", "TestMeet", "left", "referee1").
+	mockOccupancyService.On("UnsetPosition", "testmeet", models.DefaultPlatformID, "left", "referee1").
 		Return(nil).
 		Once()
 
@@ -222,7 +237,7 @@ func TestForceVacate(t *testing.T) {
 
 	// 9) Check response:
 	assert.Equal(t, http.StatusFound, w.Code, "ForceVacate should redirect on success")
-	assert.Contains(t, w.Header().Get("Location"), "/admin?meet=TestMeet",
+	assert.Contains(t, w.Header().Get("Location"), "/admin?meet=testmeet",
 		"Should redirect back to the admin panel for 'TestMeet'")
 
 	// 10) Validate all mock expectations are met
@@ -4,16 +4,19 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"strings"
 	"sync"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
 	"go-ref-lights/models"
+	"go-ref-lights/services"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -23,8 +26,11 @@ import (
 var ActiveUsers = make(map[string]bool)
 var ActiveUsersMu sync.RWMutex
 
-// loadMeetCredsFunc allows dependency injection for testing.
-var loadMeetCredsFunc = LoadMeetCreds // Assign to a variable for easier testing
+// loadMeetCredsFunc reads the default credentials file until SetMeetRegistry serves
+// the configured one; tests replace it.
+var loadMeetCredsFunc = func() (*models.MeetCreds, error) {
+	return LoadMeetCreds(config.Default().MeetCredsPath)
+}
 
 // ----------------------- authentication utilities -----------------------
 
@@ -54,22 +60,38 @@ func ComparePasswords(hashedPassword, plainPassword string) bool {
 }
 
 // SetMeetHandler sets the selected meet in the session and redirects to the meet page.
+// The picker posts the meet ID; a display name posted by an older page is resolved too.
 func SetMeetHandler(c *gin.Context) {
-	meetName := c.PostForm("meetName")
-	if meetName == "" {
+	ref := c.PostForm("meetID")
+	if ref == "" {
+		ref = c.PostForm("meetName")
+	}
+	if ref == "" {
 		c.HTML(http.StatusBadRequest, "choose_meet.html", gin.H{"Error": "Please select a meet."})
 		return
 	}
 
+	meetID := ref
+	if ref != services.SudoMeetName {
+		meet, err := resolveMeet(ref)
+		if err != nil {
+			logger.Warn.Printf("Meet selection %q rejected: %v", ref, err)
+			c.HTML(http.StatusBadRequest, "choose_meet.html", gin.H{"Error": "Please select a meet."})
+			return
+		}
+		meetID = meet.ID
+	}
+
 	session := sessions.Default(c)
-	session.Set("meetName", meetName)
+	session.Set("meetID", meetID)
+	session.Delete("platform") // the new meet starts on its first platform
 	if err := session.Save(); err != nil {
 		logger.Error.Printf("Failed to save meet session: %v", err)
 		c.HTML(http.StatusInternalServerError, "choose_meet.html", gin.H{"Error": "Internal error, please try again."})
 		return
 	}
 
-	logger.Info.Printf("Meet %s selected, redirecting to meet page.", meetName)
+	logger.Info.Printf("Meet %s selected, redirecting to meet page.", meetID)
 	c.Redirect(http.StatusFound, "/login")
 }
 
@@ -78,38 +100,34 @@ func SetMeetHandler(c *gin.Context) {
 // MeetHandler retrieves the meet details from session and renders the home page with the appropriate logo.
 func MeetHandler(c *gin.Context) {
 	session := sessions.Default(c)
-	storedMeet := session.Get("meetName")
+	storedMeet := session.Get("meetID")
 	if storedMeet == nil {
 		c.HTML(http.StatusBadRequest, "choose_meet.html", gin.H{"Error": "No meet selected."})
 		return
 	}
-	meetName := storedMeet.(string)
+	meetID := storedMeet.(string)
 
-	// load meet credentials using the injectable function.
-	creds, err := loadMeetCredsFunc()
+	// look the meet up in the registry (or the credentials file in tests).
+	currentMeet, err := lookupMeet(meetID)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		c.HTML(http.StatusNotFound, "choose_meet.html", gin.H{"Error": "Meet not found."})
+		return
+	}
 	if err != nil {
 		logger.Error.Printf("Failed to load meets: %v", err)
 		c.HTML(http.StatusInternalServerError, "choose_meet.html", gin.H{"Error": "Internal error loading meets."})
 		return
 	}
 
-	// find the meet with the matching name.
-	var currentMeet *models.Meet
-	for _, meet := range creds.Meets {
-		if meet.Name == meetName {
-			currentMeet = &meet
-			break
-		}
-	}
-	if currentMeet == nil {
-		c.HTML(http.StatusNotFound, "choose_meet.html", gin.H{"Error": "Meet not found."})
-		return
-	}
-
 	// prepare data for the template.
+	platform, _ := currentMeet.FindPlatform(sessionPlatform(session))
 	data := gin.H{
-		"meetName": currentMeet.Name,
-		"logo":     currentMeet.Logo,
+		"meetID":       currentMeet.ID,
+		"meetName":     currentMeet.Name,
+		"logo":         currentMeet.Logo,
+		"platformID":   platform.ID,
+		"platformName": platform.Name,
+		"platforms":    currentMeet.PlatformList(),
 	}
 
 	// render the template with the correct logo.
@@ -118,11 +136,8 @@ func MeetHandler(c *gin.Context) {
 
 // ----------------------- credentials management ---------------------------
 
-// LoadMeetCreds loads meet credentials from a JSON file
-func LoadMeetCreds() (*models.MeetCreds, error) {
-	// define the path to the credentials file.
-	credPath := "./config/meet_creds.json" // #nosec G101
-
+// LoadMeetCreds loads meet credentials from the JSON file at credPath
+func LoadMeetCreds(credPath string) (*models.MeetCreds, error) {
 	// read the JSON file
 	data, err := os.ReadFile(credPath)
 	if err != nil {
@@ -132,20 +147,25 @@ func LoadMeetCreds() (*models.MeetCreds, error) {
 	// unmarshal JSON into MeetCreds struct
 	var creds models.MeetCreds
 	if err := json.Unmarshal(data, &creds); err != nil {
-		return nil, fmt.Errorf("failed to parse meet_creds.json: %w", err)
+		return nil, fmt.Errorf("failed to parse %s: %w", credPath, err)
 	}
+	creds.AssignIdentities()
 
 	// validate admin credentials for each meet.
 	for _, meet := range creds.Meets {
 		if meet.Admin.Username == "" {
 			return nil, fmt.Errorf("error: Meet '%s' is missing an admin username", meet.Name)
 		}
-		if meet.Admin.Password == "" || !strings.HasPrefix(meet.Admin.Password, "$2b$12$") {
+		if !services.ValidPasswordHash(meet.Admin.Password) {
 			return nil, fmt.Errorf("error: Meet '%s' is missing a valid hashed password", meet.Name)
 		}
+		// roles are optional, but a typo must not silently demote an account.
+		if err := meet.Validate(); err != nil {
+			return nil, fmt.Errorf("error: %w", err)
+		}
 		// replaced the direct fmt.Printf with a logger call
-		logger.Debug.Printf("Loaded Meet: %s (Admin: %s, IsAdmin: %t)",
-			meet.Name, meet.Admin.Username, meet.Admin.IsAdmin)
+		logger.Debug.Printf("Loaded Meet: %s (Admin: %s, Role: %s)",
+			meet.Name, meet.Admin.Username, meet.Admin.AccountRole())
 	}
 	return &creds, nil
 }
@@ -155,12 +175,9 @@ func LoadMeetCreds() (*models.MeetCreds, error) {
 // ForceLogoutHandler forcibly logs out a user (admin action).
 // Requires: `username` from the POST request body.
 func ForceLogoutHandler(c *gin.Context) {
-	session := sessions.Default(c)
-	isAdmin := session.Get("isAdmin")
-
-	if isAdmin == nil || isAdmin != true {
+	if !middleware.HasPermission(c, models.PermForceLogout) {
 		logger.Warn.Println("Unauthorized attempt to force logout a user.")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin privileges required"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Permission required: " + string(models.PermForceLogout)})
 		return
 	}
 
@@ -189,12 +206,9 @@ func ForceLogoutHandler(c *gin.Context) {
 
 // ActiveUsersHandler returns a list of currently active users (admin action).
 func ActiveUsersHandler(c *gin.Context) {
-	session := sessions.Default(c)
-	isAdmin := session.Get("isAdmin")
-
-	if isAdmin == nil || isAdmin != true {
+	if !middleware.HasPermission(c, models.PermViewActiveUsers) {
 		logger.Warn.Println("Unauthorized attempt to view active users.")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin privileges required"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Permission required: " + string(models.PermViewActiveUsers)})
 		return
 	}
 
@@ -8,9 +8,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go-ref-lights/models"
@@ -37,18 +40,39 @@ func TestComparePasswords(t *testing.T) {
 
 // TestSetMeetHandler tests the SetMeetHandler function.
 func TestSetMeetHandler(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "New South Wales State Championships "}}})
 	router := setupTestRouter(t)
 	router.POST("/set-meet", SetMeetHandler)
+	router.GET("/meet-id", func(c *gin.Context) {
+		c.String(http.StatusOK, "%v", sessions.Default(c).Get("meetID"))
+	})
 
-	reqBody := "meetName=TestMeet"
-	req, _ := http.NewRequest("POST", "/set-meet", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, form := range []string{
+		"meetID=new-south-wales-state-championships",
+		"meetName=New+South+Wales+State+Championships+", // an older picker page
+	} {
+		req, _ := http.NewRequest("POST", "/set-meet", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+		assert.Equal(t, "/login", w.Header().Get("Location"))
+
+		req, _ = http.NewRequest("GET", "/meet-id", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, "new-south-wales-state-championships", w.Body.String(), "the session holds the ID for %s", form)
+	}
 
+	req, _ := http.NewRequest("POST", "/set-meet", strings.NewReader("meetID=no-such-meet"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusFound, w.Code)
-	assert.Equal(t, "/login", w.Header().Get("Location"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
 func TestLoadMeetCreds(t *testing.T) {
@@ -63,6 +87,33 @@ func TestLoadMeetCreds(t *testing.T) {
 	assert.Equal(t, "TestMeet", loaded.Meets[0].Name)
 }
 
+func TestLoadMeetCreds_ValidatesRoles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meet_creds.json")
+	// LoadMeetCreds insists on cost-12 bcrypt hashes, which hashPassword does not produce.
+	const hash = "$2b$12$KFKBzEMcKJhuGh6Q7R/GZOBBS4S6EoAWxrML2jv1Zl9Fwf0P0ylVC"
+	write := func(role models.Role) {
+		creds := models.MeetCreds{Meets: []models.Meet{{
+			Name:            "TestMeet",
+			Admin:           models.Admin{Username: "director", Password: hash},
+			SecondaryAdmins: []models.Admin{{Username: "tc", Password: hash, Role: role}},
+		}}}
+		data, _ := json.Marshal(creds)
+		assert.NoError(t, os.WriteFile(path, data, 0600))
+	}
+
+	write(models.RoleTechnicalController)
+	_, err := LoadMeetCreds(path)
+	assert.NoError(t, err)
+
+	write("director")
+	_, err = LoadMeetCreds(path)
+	assert.ErrorContains(t, err, "invalid role")
+
+	write(models.RoleSuperuser)
+	_, err = LoadMeetCreds(path)
+	assert.ErrorContains(t, err, "invalid role", "meet accounts cannot be superusers")
+}
+
 func TestForceLogoutHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	// use a fresh router with our shared test helpers.
@@ -75,7 +126,7 @@ func TestForceLogoutHandler(t *testing.T) {
 	t.Run("Admin can force logout user", func(t *testing.T) {
 		// Use a unique helper route for this sub-test.
 		sessionCookie := SetSession(router, "/set-session-force-logout-1", map[string]interface{}{
-			"isAdmin": true,
+			"role": "meet_director",
 		})
 		if sessionCookie == nil {
 			t.Fatal("Session cookie not found")
@@ -102,13 +153,13 @@ func TestForceLogoutHandler(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Admin privileges required")
+		assert.Contains(t, w.Body.String(), "Permission required")
 	})
 
 	t.Run("Cannot force logout a non-existent user", func(t *testing.T) {
 		// Use a unique helper route for this sub-test.
 		sessionCookie := SetSession(router, "/set-session-force-logout-2", map[string]interface{}{
-			"isAdmin": true,
+			"role": "meet_director",
 		})
 		if sessionCookie == nil {
 			t.Fatal("Session cookie not found")
@@ -136,7 +187,7 @@ func TestActiveUsersHandler(t *testing.T) {
 
 	t.Run("Admin can see active users", func(t *testing.T) {
 		sessionCookie := SetSession(router, "/set-session-active-1", map[string]interface{}{
-			"isAdmin": true,
+			"role": "meet_director",
 		})
 		if sessionCookie == nil {
 			t.Fatal("Session cookie not found")
@@ -159,13 +210,13 @@ func TestActiveUsersHandler(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		assert.Contains(t, w.Body.String(), "Admin privileges required")
+		assert.Contains(t, w.Body.String(), "Permission required")
 	})
 
 	t.Run("Admin sees empty user list when no users are logged in", func(t *testing.T) {
 		ActiveUsers = make(map[string]bool) // Clear all users.
 		sessionCookie := SetSession(router, "/set-session-active-2", map[string]interface{}{
-			"isAdmin": true,
+			"role": "meet_director",
 		})
 		if sessionCookie == nil {
 			t.Fatal("Session cookie not found")
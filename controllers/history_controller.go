@@ -0,0 +1,160 @@
+// Package controllers exposes the decision history to meet directors.
+// File: controllers/history_controller.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/history"
+	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
+	"go-ref-lights/websocket"
+)
+
+// defaultHistoryPageSize is used when the client does not ask for a page size.
+const defaultHistoryPageSize = 25
+
+// maxHistoryPageSize caps how many records a single request may return.
+const maxHistoryPageSize = 200
+
+// HistoryController serves the decision log for a meet.
+type HistoryController struct {
+	Store history.Store
+}
+
+// NewHistoryController initializes a HistoryController backed by the given store.
+func NewHistoryController(store history.Store) *HistoryController {
+	return &HistoryController{Store: store}
+}
+
+// ---------------- decision history ----------------
+
+// HistoryPage renders the decision history page for the admin's meet.
+func (hc *HistoryController) HistoryPage(c *gin.Context) {
+	meetID := historyMeetID(c)
+	if meetID == "" {
+		c.String(http.StatusBadRequest, "Meet not specified")
+		return
+	}
+	c.HTML(http.StatusOK, "history.html", gin.H{
+		"meetID":    meetID,
+		"meetName":  meetDisplayName(meetID),
+		"platforms": meetPlatforms(meetID),
+		"canAmend":  middleware.HasPermission(c, models.PermAmendDecision),
+	})
+}
+
+// DecisionsAPI returns a page of completed attempts as JSON, newest first.
+// Query parameters:
+// - `page` (1-based, default 1)
+// - `pageSize` (default 25, max 200)
+// - `meet`, a meet ID (superusers only; others always see their own meet)
+func (hc *HistoryController) DecisionsAPI(c *gin.Context) {
+	meetID := historyMeetID(c)
+	if meetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meet not specified"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultHistoryPageSize)))
+	if err != nil || pageSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pageSize"})
+		return
+	}
+	if pageSize > maxHistoryPageSize {
+		pageSize = maxHistoryPageSize
+	}
+
+	if hc.Store == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Decision history is not enabled"})
+		return
+	}
+
+	records, total, err := hc.Store.List(meetID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		logger.Error.Printf("[DecisionsAPI] Failed to read history for meet=%s: %v", meetID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read decision history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"meetID":    meetID,
+		"page":      page,
+		"pageSize":  pageSize,
+		"total":     total,
+		"decisions": records,
+	})
+}
+
+// amendRequest is the jury's ruling on the last attempt of a platform.
+type amendRequest struct {
+	Platform string `json:"platform"` // platform ID; defaults to the session's platform
+	Verdict  string `json:"verdict"`  // "good lift" or "no lift"
+	Reason   string `json:"reason"`
+}
+
+// AmendDecision lets the jury overturn the last completed attempt on a platform. The
+// original verdict is kept in the history and the lights show the corrected result.
+func (hc *HistoryController) AmendDecision(c *gin.Context) {
+	session := sessions.Default(c)
+	meetID := historyMeetID(c)
+	if meetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Meet not specified"})
+		return
+	}
+
+	var req amendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if req.Platform == "" {
+		req.Platform = sessionPlatform(session)
+	}
+	if !hasPlatform(meetID, req.Platform) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Platform not found"})
+		return
+	}
+
+	juryMember, _ := session.Get("user").(string)
+	rec, err := websocket.AmendLastDecision(models.PlatformKey(meetID, req.Platform), req.Verdict, juryMember, req.Reason)
+	switch {
+	case errors.Is(err, websocket.ErrInvalidAmendment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, websocket.ErrNoDecisionToAmend):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, websocket.ErrHistoryDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Decision history is not enabled"})
+		return
+	case err != nil:
+		logger.Error.Printf("[AmendDecision] Failed to amend meet=%s platform=%s: %v", meetID, req.Platform, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to amend decision"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"decision": rec})
+}
+
+// historyMeetID picks the meet to report on: the session meet, or any meet for a superuser.
+func historyMeetID(c *gin.Context) string {
+	session := sessions.Default(c)
+	meetID, _ := session.Get("meetID").(string)
+	if middleware.HasPermission(c, models.PermManageAllMeets) {
+		if q := c.Query("meet"); q != "" {
+			meetID = q
+		}
+	}
+	return meetID
+}
@@ -0,0 +1,155 @@
+// controllers/history_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/history"
+	"go-ref-lights/models"
+	"go-ref-lights/websocket"
+)
+
+func newHistoryStore(t *testing.T, meetName string, n int) history.Store {
+	store, err := history.NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	for i := 0; i < n; i++ {
+		require.NoError(t, store.Append(&history.DecisionRecord{MeetName: meetName, Verdict: "good lift"}))
+	}
+	return store
+}
+
+func TestDecisionsAPI_Paginates(t *testing.T) {
+	hc := NewHistoryController(newHistoryStore(t, "testmeet", 3))
+	router := setupTestRouter(t)
+	router.GET("/admin/decisions", hc.DecisionsAPI)
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"role":   "meet_director",
+		"meetID": "testmeet",
+	})
+	require.NotNil(t, sessionCookie)
+
+	req, _ := http.NewRequest("GET", "/admin/decisions?page=2&pageSize=2", nil)
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Total     int                      `json:"total"`
+		Page      int                      `json:"page"`
+		Decisions []history.DecisionRecord `json:"decisions"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 3, body.Total)
+	assert.Equal(t, 2, body.Page)
+	require.Len(t, body.Decisions, 1)
+	assert.Equal(t, 1, body.Decisions[0].Attempt)
+}
+
+func TestDecisionsAPI_IgnoresMeetQueryForNonSudo(t *testing.T) {
+	hc := NewHistoryController(newHistoryStore(t, "othermeet", 2))
+	router := setupTestRouter(t)
+	router.GET("/admin/decisions", hc.DecisionsAPI)
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"role":   "meet_director",
+		"meetID": "testmeet",
+	})
+
+	req, _ := http.NewRequest("GET", "/admin/decisions?meet=OtherMeet", nil)
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"meetID":"testmeet"`)
+	assert.Contains(t, w.Body.String(), `"total":0`)
+}
+
+func TestDecisionsAPI_InvalidPage(t *testing.T) {
+	hc := NewHistoryController(newHistoryStore(t, "testmeet", 0))
+	router := setupTestRouter(t)
+	router.GET("/admin/decisions", hc.DecisionsAPI)
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"role":   "meet_director",
+		"meetID": "testmeet",
+	})
+
+	req, _ := http.NewRequest("GET", "/admin/decisions?page=0", nil)
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHistoryPage_RendersMeet(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "TestMeet"}}})
+	hc := NewHistoryController(nil)
+	router := setupTestRouter(t)
+	router.GET("/admin/history", hc.HistoryPage)
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"role":   "meet_director",
+		"meetID": "testmeet",
+	})
+
+	req, _ := http.NewRequest("GET", "/admin/history", nil)
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "History for TestMeet")
+}
+
+func TestAmendDecision_JuryOverturnsLastAttempt(t *testing.T) {
+	store := newHistoryStore(t, "testmeet", 2)
+	websocket.SetDecisionHistory(store)
+	defer websocket.SetDecisionHistory(nil)
+
+	hc := NewHistoryController(store)
+	router := setupTestRouter(t)
+	router.POST("/admin/amend-decision", hc.AmendDecision)
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"role":   "jury",
+		"user":   "jury1",
+		"meetID": "testmeet",
+	})
+	require.NotNil(t, sessionCookie)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/admin/amend-decision", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(sessionCookie)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"verdict":"no lift","reason":"soft knees"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Decision history.DecisionRecord `json:"decision"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Decision.Attempt)
+	assert.Equal(t, "no lift", body.Decision.Verdict)
+	assert.Equal(t, "good lift", body.Decision.OriginalVerdict())
+	assert.Equal(t, "jury1", body.Decision.Amendments[0].JuryMember)
+
+	assert.Equal(t, http.StatusBadRequest, post(`{"verdict":"no lift","reason":"again"}`).Code, "already a no lift")
+	assert.Equal(t, http.StatusBadRequest, post(`{"verdict":"good lift"}`).Code, "a reason is required")
+	assert.Equal(t, http.StatusNotFound, post(`{"platform":"b","verdict":"good lift","reason":"x"}`).Code)
+}
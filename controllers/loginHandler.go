@@ -8,6 +8,8 @@ import (
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
 	"go-ref-lights/services"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -21,22 +23,36 @@ func checkPasswordHash(password, hash string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
-// PerformLogin captures meetName & position from query params for the login page
-// Called when user first arrives at /login?meetName=foo&position=left, for example
+// PerformLogin captures the meet & position from query params for the login page.
+// Called when user first arrives at /login?meet=cairns-cup&position=left, for example;
+// the older ?meetName= form with a display name still works, and &platform= picks
+// a platform other than the meet's first.
 func PerformLogin(c *gin.Context) {
 	session := sessions.Default(c)
 
 	// grab from the query string
-	meetNameParam := c.Query("meetName")
+	meetParam := c.Query("meet")
+	if meetParam == "" {
+		meetParam = c.Query("meetName")
+	}
 	posParam := c.Query("position")
+	platformParam := c.Query("platform")
 
 	// if present, store them in session
-	if meetNameParam != "" {
-		session.Set("meetName", meetNameParam)
+	if meetParam != "" {
+		if meet, err := resolveMeet(meetParam); err == nil {
+			session.Set("meetID", meet.ID)
+			session.Delete("platform")
+		} else {
+			logger.Warn.Printf("[PerformLogin] Ignoring unknown meet %q: %v", meetParam, err)
+		}
 	}
 	if posParam != "" {
 		session.Set("desiredPosition", posParam)
 	}
+	if platformParam != "" {
+		session.Set("platform", platformParam)
+	}
 
 	// persist session changes
 	if err := session.Save(); err != nil {
@@ -44,19 +60,13 @@ func PerformLogin(c *gin.Context) {
 	}
 
 	// finally, render the login form
-	meetName := session.Get("meetName")
+	var meetName interface{}
 	logo := "" // default empty
 
-	// Try to load logo from meet config
-	if meetNameStr, ok := meetName.(string); ok && meetNameStr != "" {
-		if creds, err := loadMeetCredsFunc(); err == nil {
-			for _, meet := range creds.Meets {
-				if meet.Name == meetNameStr {
-					logo = meet.Logo
-					break
-				}
-			}
-		}
+	// Try to load the name and logo from meet config
+	if meetID, ok := session.Get("meetID").(string); ok && meetID != "" {
+		meetName = meetDisplayName(meetID)
+		logo = getLogoForMeet(meetID)
 	}
 
 	c.HTML(http.StatusOK, "login.html", gin.H{
@@ -75,14 +85,14 @@ func PerformLogin(c *gin.Context) {
 func LoginHandler(c *gin.Context) {
 	session := sessions.Default(c)
 
-	// retrieve meet name from session
-	meetNameRaw := session.Get("meetName")
-	meetName, ok := meetNameRaw.(string)
-	if !ok || meetName == "" {
+	// retrieve meet from session
+	meetID, ok := session.Get("meetID").(string)
+	if !ok || meetID == "" {
 		logger.Warn.Println("[LoginHandler] No meet selected, redirecting to /choose-meet")
 		c.Redirect(http.StatusFound, "/choose-meet")
 		return
 	}
+	meetName := meetDisplayName(meetID)
 
 	// extract username and password from the POST form
 	username := c.PostForm("username")
@@ -113,8 +123,7 @@ func LoginHandler(c *gin.Context) {
 	if creds.Superuser != nil &&
 		creds.Superuser.Username == username &&
 		checkPasswordHash(password, creds.Superuser.Password) {
-		session.Set("sudo", true)
-		session.Set("isAdmin", true)
+		session.Set(middleware.RoleKey, string(models.RoleSuperuser))
 		session.Set("user", username)
 		_ = session.Save()
 
@@ -124,34 +133,19 @@ func LoginHandler(c *gin.Context) {
 	}
 
 	// validate the provided credentials against the selected meet
-	var isAdmin bool
+	var role models.Role
 	var authenticated bool
-	for _, m := range creds.Meets {
-		if m.Name != meetName {
-			continue
-		}
-
-		// primary admin
-		if m.Admin.Username == username && checkPasswordHash(password, m.Admin.Password) {
-			isAdmin = m.Admin.IsAdmin
+	if m := findMeet(creds, meetID); m != nil {
+		if m.Archived {
+			logger.Warn.Printf("[LoginHandler] Login attempt for archived meet=%s", meetID)
+		} else if account := m.Account(username); account != nil && checkPasswordHash(password, account.Password) {
+			role = account.AccountRole()
 			authenticated = true
-			break
-		}
-
-		// secondary admins
-		for _, sa := range m.SecondaryAdmins {
-			if sa.Username == username && checkPasswordHash(password, sa.Password) {
-				isAdmin = sa.IsAdmin
-				authenticated = true
-				break
-			}
 		}
-
-		break // stop after checking this meet
 	}
 
 	if !authenticated {
-		logger.Warn.Printf("[LoginHandler] Invalid login attempt for user=%s at meet=%s", username, meetName)
+		logger.Warn.Printf("[LoginHandler] Invalid login attempt for user=%s at meet=%s", username, meetID)
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{
 			"MeetName": meetName,
 			"Error":    "Invalid username or password.",
@@ -166,7 +160,7 @@ func LoginHandler(c *gin.Context) {
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{
 			"MeetName": meetName,
 			"Error":    "Invalid username or password.",
-			"Logo":     getLogoForMeet(meetName), // helper function
+			"Logo":     getLogoForMeet(meetID), // helper function
 		})
 		ActiveUsersMu.Unlock()
 		return
@@ -175,8 +169,8 @@ func LoginHandler(c *gin.Context) {
 	ActiveUsersMu.Unlock()
 
 	session.Set("user", username)
-	session.Set("isAdmin", isAdmin)
-	logger.Debug.Printf("[LoginHandler] Setting isAdmin=%v for user=%s", isAdmin, username)
+	session.Set(middleware.RoleKey, string(role))
+	logger.Debug.Printf("[LoginHandler] Setting role=%s for user=%s", role, username)
 
 	if err := session.Save(); err != nil {
 		logger.Error.Printf("[LoginHandler] Failed to save session: %v", err)
@@ -187,34 +181,32 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	logger.Info.Printf("[LoginHandler] User %s authenticated for meet %s (isAdmin=%v)", username, meetName, isAdmin)
+	logger.Info.Printf("[LoginHandler] User %s authenticated for meet %s (role=%s)", username, meetID, role)
 
 	// ------------------ auto-claim desired position ------------------
 	desiredPos := session.Get("desiredPosition")
+	if pos, ok := desiredPos.(string); ok && !role.Can(claimPermission(meetID, pos)) {
+		logger.Info.Printf("[LoginHandler] Role %s cannot sit in %s; ignoring desired position for user=%s", role, pos, username)
+		desiredPos = nil
+	}
 	if desiredPos != nil {
 		logger.Info.Printf("[LoginHandler] Attempting to auto-claim position=%s for user=%s", desiredPos, username)
 		posString := desiredPos.(string)
-		if err := occupancyService.SetPosition(meetName, posString, username); err != nil {
+		platformID := sessionPlatform(session)
+		if err := occupancyService.SetPosition(meetID, platformID, posString, username); err != nil {
 			logger.Warn.Printf("[LoginHandler] Auto-claim failed for user=%s on position=%s: %v", username, posString, err)
 			c.HTML(http.StatusForbidden, "positions.html", gin.H{
 				"Error":    "Position is already taken or invalid. Please choose another.",
+				"meetID":   meetID,
 				"meetName": meetName,
+				"Seats":    panelSeats(PositionsFor(meetID), occupancyService.GetOccupancy(meetID, platformID)),
 			})
 			return
 		}
 		session.Set("refPosition", posString)
 		_ = session.Save()
 
-		switch posString {
-		case "left":
-			c.Redirect(http.StatusFound, "/left")
-		case "center":
-			c.Redirect(http.StatusFound, "/center")
-		case "right":
-			c.Redirect(http.StatusFound, "/right")
-		default:
-			c.Redirect(http.StatusFound, "/positions")
-		}
+		c.Redirect(http.StatusFound, seatPath(posString))
 		return
 	}
 
@@ -223,15 +215,10 @@ func LoginHandler(c *gin.Context) {
 }
 
 // Helper function to retrieve logo for meet
-func getLogoForMeet(meetName string) string {
-	creds, err := loadMeetCredsFunc()
+func getLogoForMeet(meetID string) string {
+	meet, err := lookupMeet(meetID)
 	if err != nil {
 		return ""
 	}
-	for _, meet := range creds.Meets {
-		if meet.Name == meetName {
-			return meet.Logo
-		}
-	}
-	return ""
+	return meet.Logo
 }
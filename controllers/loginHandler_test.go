@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go-ref-lights/models"
 	"go-ref-lights/websocket"
@@ -59,7 +61,7 @@ func TestLoginHandler_Success(t *testing.T) {
 	}()
 
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"meetName": "TestMeet",
+		"meetID": "testmeet",
 	})
 	assert.NotNil(t, sessionCookie, "Session cookie should not be nil")
 
@@ -91,7 +93,7 @@ func TestLoginHandler_InvalidCredentials(t *testing.T) {
 
 	// Use a valid user but a wrong password
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"meetName": "TestMeet",
+		"meetID": "testmeet",
 	})
 
 	reqBody := "username=adminuser&password=invalidpassword"
@@ -236,7 +238,7 @@ func TestLoginHandler_SecondaryAdminSuccess(t *testing.T) {
 
 	// Set meet in session
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"meetName": "TestMeet",
+		"meetID": "testmeet",
 	})
 	assert.NotNil(t, sessionCookie)
 
@@ -253,6 +255,73 @@ func TestLoginHandler_SecondaryAdminSuccess(t *testing.T) {
 	assert.Equal(t, "/index", w.Header().Get("Location"), "Secondary admin should land on /index")
 }
 
+// TestLoginHandler_StoresConfiguredRole checks the account's role from the credentials
+// file ends up in the session, and that non-judging roles skip the seat auto-claim.
+func TestLoginHandler_StoresConfiguredRole(t *testing.T) {
+	router := setupTestRouter(t)
+	router.POST("/login", LoginHandler)
+	router.GET("/role", func(c *gin.Context) {
+		c.String(http.StatusOK, "%v", sessions.Default(c).Get("role"))
+	})
+
+	stubMeetCreds(t, &models.MeetCreds{
+		Meets: []models.Meet{
+			{
+				Name:  "TestMeet",
+				Admin: models.Admin{Username: "director", Password: hashPassword("pw"), IsAdmin: true},
+				SecondaryAdmins: []models.Admin{
+					{Username: "announcer", Password: hashPassword("pw"), Role: models.RoleAnnouncer},
+				},
+			},
+		},
+	})
+	ActiveUsersMu.Lock()
+	delete(ActiveUsers, "announcer")
+	ActiveUsersMu.Unlock()
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
+		"meetID":          "testmeet",
+		"desiredPosition": "left",
+	})
+
+	req, _ := http.NewRequest("POST", "/login", strings.NewReader("username=announcer&password=pw"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/index", w.Header().Get("Location"), "announcers cannot claim a seat")
+
+	req, _ = http.NewRequest("GET", "/role", nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "announcer", w.Body.String())
+}
+
+// TestLoginHandler_ArchivedMeet checks archived meets refuse logins.
+func TestLoginHandler_ArchivedMeet(t *testing.T) {
+	router := setupTestRouter(t)
+	router.POST("/login", LoginHandler)
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		Name:     "TestMeet",
+		Admin:    models.Admin{Username: "director", Password: hashPassword("pw"), IsAdmin: true},
+		Archived: true,
+	}}})
+
+	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{"meetID": "testmeet"})
+	req, _ := http.NewRequest("POST", "/login", strings.NewReader("username=director&password=pw"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(sessionCookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 // extractSessionCookie retrieves the session cookie from a test response.
 func extractSessionCookie(resp *httptest.ResponseRecorder) *http.Cookie {
 	for _, cookie := range resp.Result().Cookies() {
@@ -4,26 +4,118 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
 	"go-ref-lights/models"
+	"go-ref-lights/services"
 )
 
 // --------------- global variables ---------------
 
-// loadMeetsFunc allows dependency injection for testing.
-var loadMeetsFunc = LoadMeets
+// loadMeetsFunc reads the default meets file until SetMeetRegistry serves the
+// configured one; tests replace it.
+var loadMeetsFunc = func() (*models.MeetCreds, error) {
+	return LoadMeets(config.Default().MeetsPath)
+}
+
+// nowFunc is the clock for deciding which meets and sessions are current; tests replace it.
+var nowFunc = time.Now
+
+// meetRegistry serves meets from memory once SetMeetRegistry is called; until then
+// (and in tests) the files are read on demand.
+var meetRegistry *services.MeetRegistry
+
+// SetMeetRegistry makes the registry the source of meets and credentials.
+func SetMeetRegistry(r *services.MeetRegistry) {
+	meetRegistry = r
+	loadMeetCredsFunc = func() (*models.MeetCreds, error) { return r.Creds(), nil }
+	loadMeetsFunc = func() (*models.MeetCreds, error) { return &models.MeetCreds{Meets: r.Listed()}, nil }
+}
+
+// lookupMeet returns the meet with the given ID. A meet that does not exist is reported as
+// services.ErrMeetNotFound; any other error means the meets could not be loaded.
+func lookupMeet(meetID string) (*models.Meet, error) {
+	if meetRegistry != nil {
+		meet, err := meetRegistry.Meet(meetID)
+		if err != nil {
+			return nil, err
+		}
+		return &meet, nil
+	}
+	creds, err := loadMeetCredsFunc()
+	if err != nil {
+		return nil, err
+	}
+	if meet := findMeet(creds, meetID); meet != nil {
+		return meet, nil
+	}
+	return nil, fmt.Errorf("%w: %q", services.ErrMeetNotFound, meetID)
+}
+
+// resolveMeet is lookupMeet for references from outside the app: an ID, a slug, or
+// the display name that links and integrations used before meets had IDs.
+func resolveMeet(ref string) (*models.Meet, error) {
+	if meetRegistry != nil {
+		meet, err := meetRegistry.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &meet, nil
+	}
+	creds, err := loadMeetCredsFunc()
+	if err != nil {
+		return nil, err
+	}
+	creds.AssignIdentities()
+	for _, match := range []func(m *models.Meet) bool{
+		func(m *models.Meet) bool { return m.ID == ref },
+		func(m *models.Meet) bool { return m.Slug == ref },
+		func(m *models.Meet) bool { return strings.TrimSpace(m.Name) == strings.TrimSpace(ref) },
+	} {
+		for i := range creds.Meets {
+			if match(&creds.Meets[i]) {
+				return &creds.Meets[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", services.ErrMeetNotFound, ref)
+}
+
+// meetDisplayName returns the name to show for a meet ID, or the ID itself if the
+// meet cannot be found (the superuser's "Sudo" pseudo-meet, say).
+func meetDisplayName(meetID string) string {
+	if meet, err := lookupMeet(meetID); err == nil {
+		return meet.Name
+	}
+	return meetID
+}
+
+// reloadMeetRegistry picks up a change this process just wrote, rather than
+// waiting for the file watcher.
+func reloadMeetRegistry(handler string) {
+	if meetRegistry == nil {
+		return
+	}
+	if err := meetRegistry.Reload(); err != nil {
+		logger.Error.Printf("[%s] Meet registry did not accept the change: %v", handler, err)
+	}
+}
 
 // ------------- meet configuration management -------------
 
-// LoadMeets loads the meet configuration from `./config/meets.json`.
+// LoadMeets loads the meet configuration from the meets file at path
+// (`./config/meets.json` by default).
 // This function retrieves the available meets and their details from the JSON file.
-func LoadMeets() (*models.MeetCreds, error) {
+func LoadMeets(path string) (*models.MeetCreds, error) {
 	// read the config file
-	data, err := os.ReadFile("./config/meets.json")
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +124,7 @@ func LoadMeets() (*models.MeetCreds, error) {
 	if err := json.Unmarshal(data, &meets); err != nil {
 		return nil, err
 	}
+	meets.AssignIdentities()
 
 	logger.Info.Printf("[LoadMeets] Successfully loaded %d meets", len(meets.Meets))
 	return &meets, nil
@@ -40,7 +133,7 @@ func LoadMeets() (*models.MeetCreds, error) {
 // -------------- meet selection handling --------------
 
 // ShowMeets renders the meet selection page.
-// It fetches the list of available meets and passes them to the template.
+// It lists current and upcoming meets by start date; meets that are over drop off.
 // If loading fails, it returns an HTTP 500 response.
 func ShowMeets(c *gin.Context) {
 	// retrieve meet data using a mockable function for easier testing
@@ -53,6 +146,6 @@ func ShowMeets(c *gin.Context) {
 
 	// render the meet selection page with available meets
 	c.HTML(http.StatusOK, "choose_meet.html", gin.H{
-		"availableMeets": meetsData.Meets,
+		"availableMeets": models.UpcomingMeets(meetsData.Meets, nowFunc()),
 	})
 }
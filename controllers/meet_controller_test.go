@@ -9,11 +9,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go-ref-lights/models"
+	"go-ref-lights/services"
 	"go-ref-lights/websocket"
 )
 
@@ -75,6 +80,35 @@ func TestShowMeets(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "TestMeet2", "Response should contain TestMeet2")
 }
 
+// TestShowMeets_ListsCurrentAndUpcoming checks meets that are over drop out of the
+// picker and the rest come in start order.
+func TestShowMeets_ListsCurrentAndUpcoming(t *testing.T) {
+	router := setupTestRouter(t)
+	router.GET("/meets", ShowMeets)
+
+	scheduled := func(name, start string) models.Meet {
+		return models.Meet{ID: models.Slugify(name), Name: name,
+			Schedule: &models.Schedule{TimeZone: "Australia/Brisbane", StartDate: start}}
+	}
+	originalLoadMeetsFunc, originalNow := loadMeetsFunc, nowFunc
+	loadMeetsFunc = func() (*models.MeetCreds, error) {
+		return &models.MeetCreds{Meets: []models.Meet{
+			scheduled("Winter Open", "2025-07-05"), scheduled("Autumn Open", "2025-03-01"), scheduled("Cairns Cup", "2025-03-22"),
+		}}, nil
+	}
+	nowFunc = func() time.Time { return time.Date(2025, 3, 22, 1, 0, 0, 0, time.UTC) }
+	defer func() { loadMeetsFunc, nowFunc = originalLoadMeetsFunc, originalNow }()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/meets", nil))
+
+	body := w.Body.String()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, body, "Autumn Open")
+	require.Contains(t, body, "Cairns Cup")
+	assert.Less(t, strings.Index(body, "Cairns Cup"), strings.Index(body, "Winter Open"))
+}
+
 // Test ShowMeets Failure
 func TestShowMeets_Failure(t *testing.T) {
 	websocket.InitTest()
@@ -94,3 +128,35 @@ func TestShowMeets_Failure(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code, "ShowMeets should return 500 on failure")
 	assert.Contains(t, w.Body.String(), "Failed to load meets", "Error message should be returned")
 }
+
+// TestSetMeetRegistry checks lookups and the meet picker are served from the registry.
+func TestSetMeetRegistry(t *testing.T) {
+	dir := t.TempDir()
+	credsPath, meetsPath := filepath.Join(dir, "meet_creds.json"), filepath.Join(dir, "meets.json")
+	const hash = "$2b$12$KFKBzEMcKJhuGh6Q7R/GZOBBS4S6EoAWxrML2jv1Zl9Fwf0P0ylVC"
+	creds := `{"meets":[{"name":"Cairns Cup","date":"March 22","admin":{"username":"cairns","password":"` + hash + `","isadmin":true}}]}`
+	require.NoError(t, os.WriteFile(credsPath, []byte(creds), 0600))
+	require.NoError(t, os.WriteFile(meetsPath, []byte(`{"meets":[{"name":"Cairns Cup"},{"name":"Sudo"}]}`), 0644))
+
+	registry, err := services.NewMeetRegistry(credsPath, meetsPath)
+	require.NoError(t, err)
+	origLoadCreds, origLoadMeets := loadMeetCredsFunc, loadMeetsFunc
+	SetMeetRegistry(registry)
+	t.Cleanup(func() { meetRegistry, loadMeetCredsFunc, loadMeetsFunc = nil, origLoadCreds, origLoadMeets })
+
+	meet, err := lookupMeet("cairns-cup")
+	require.NoError(t, err)
+	assert.Equal(t, "cairns", meet.Admin.Username)
+
+	meet, err = resolveMeet("Cairns Cup")
+	require.NoError(t, err)
+	assert.Equal(t, "cairns-cup", meet.ID, "display names resolve to the meet")
+
+	_, err = lookupMeet("Nope")
+	assert.ErrorIs(t, err, services.ErrMeetNotFound)
+
+	listed, err := loadMeetsFunc()
+	require.NoError(t, err)
+	require.Len(t, listed.Meets, 1, "the Sudo entry is not a meet")
+	assert.Equal(t, "Cairns Cup", listed.Meets[0].Name)
+}
@@ -19,27 +19,32 @@ type MockOccupancyService struct {
 
 // UnsetPosition removes the position assignment for a given referee.
 // This function simulates the behavior of unsetting a referee’s assigned position in a meet.
-func (m *MockOccupancyService) UnsetPosition(meetName, position, user string) error {
-	args := m.Called(meetName, position, user)
+func (m *MockOccupancyService) UnsetPosition(meetID, platformID, position, user string) error {
+	args := m.Called(meetID, platformID, position, user)
 	return args.Error(0)
 }
 
-// GetOccupancy retrieves the current occupancy status for a given meet.
+// GetOccupancy retrieves the current occupancy status for a platform of a meet.
 // This function returns a mock response based on predefined test cases.
-func (m *MockOccupancyService) GetOccupancy(meetName string) services.Occupancy {
-	args := m.Called(meetName)
+func (m *MockOccupancyService) GetOccupancy(meetID, platformID string) services.Occupancy {
+	args := m.Called(meetID, platformID)
 	return args.Get(0).(services.Occupancy)
 }
 
+// ResetOccupancyForPlatform clears all referee positions on one platform of a meet.
+func (m *MockOccupancyService) ResetOccupancyForPlatform(meetID, platformID string) {
+	m.Called(meetID, platformID)
+}
+
 // ResetOccupancyForMeet clears all referee positions for a specific meet.
 // This function ensures that all referee positions are reset to vacant.
-func (m *MockOccupancyService) ResetOccupancyForMeet(meetName string) {
-	m.Called(meetName)
+func (m *MockOccupancyService) ResetOccupancyForMeet(meetID string) {
+	m.Called(meetID)
 }
 
 // SetPosition assigns a referee to a specific position in a meet.
 // This function mimics the process of assigning a referee to a seat.
-func (m *MockOccupancyService) SetPosition(meetName, position, user string) error {
-	args := m.Called(meetName, position, user)
+func (m *MockOccupancyService) SetPosition(meetID, platformID, position, user string) error {
+	args := m.Called(meetID, platformID, position, user)
 	return args.Error(0)
 }
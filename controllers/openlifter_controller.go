@@ -0,0 +1,91 @@
+// Package controllers accepts lifter/attempt context pushed by OpenLifter.
+// File: controllers/openlifter_controller.go
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/services"
+	"go-ref-lights/websocket"
+)
+
+// OpenLifterController handles the inbound OpenLifter integration.
+type OpenLifterController struct {
+	APIToken string // shared secret expected as "Authorization: Bearer <token>"
+}
+
+// NewOpenLifterController initializes an OpenLifterController. An empty token disables the endpoint.
+func NewOpenLifterController(apiToken string) *OpenLifterController {
+	return &OpenLifterController{APIToken: apiToken}
+}
+
+// currentAttemptRequest is the JSON body accepted by SetCurrentAttempt. Meet is the
+// meet's ID or slug; MeetName (a display name) is accepted from older setups.
+// Platform is the platform ID, and may be left out for the meet's first platform.
+type currentAttemptRequest struct {
+	Meet     string `json:"meet"`
+	MeetName string `json:"meetName"`
+	Platform string `json:"platform"`
+	websocket.AttemptContext
+}
+
+// SetCurrentAttempt sets the lifter, lift, attempt number and weight on a platform.
+// Requires a valid bearer token.
+func (oc *OpenLifterController) SetCurrentAttempt(c *gin.Context) {
+	if oc.APIToken == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "OpenLifter integration is not enabled"})
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(oc.APIToken)) != 1 {
+		logger.Warn.Printf("[SetCurrentAttempt] Rejected request from %s with invalid token", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req currentAttemptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON body"})
+		return
+	}
+
+	ref := req.Meet
+	if ref == "" {
+		ref = req.MeetName
+	}
+	meet, err := resolveMeet(ref)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Meet not found"})
+		return
+	}
+	if err != nil {
+		logger.Error.Printf("[SetCurrentAttempt] Failed to load meets: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load meets"})
+		return
+	}
+
+	platform, ok := meet.FindPlatform(req.Platform)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Platform not found"})
+		return
+	}
+
+	err = websocket.SetCurrentAttempt(meet.PlatformKey(platform.ID), req.AttemptContext)
+	if errors.Is(err, websocket.ErrPlatformElsewhere) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Current attempt updated"})
+}
@@ -0,0 +1,72 @@
+// controllers/openlifter_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go-ref-lights/models"
+	"go-ref-lights/websocket"
+)
+
+const currentAttemptBody = `{"meet":"openlifter-test","lifterId":"uuid-9","lift":"deadlift","attemptNumber":1,"weightKg":250}`
+
+func postCurrentAttempt(t *testing.T, oc *OpenLifterController, auth, body string) *httptest.ResponseRecorder {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "OpenLifter Test"}}})
+	router := setupTestRouter(t)
+	router.POST("/api/openlifter/current-attempt", oc.SetCurrentAttempt)
+	req, _ := http.NewRequest("POST", "/api/openlifter/current-attempt", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOpenLifterSetCurrentAttempt_Disabled(t *testing.T) {
+	w := postCurrentAttempt(t, NewOpenLifterController(""), "Bearer anything", currentAttemptBody)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestOpenLifterSetCurrentAttempt_Unauthorized(t *testing.T) {
+	w := postCurrentAttempt(t, NewOpenLifterController("s3cret"), "Bearer wrong", currentAttemptBody)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOpenLifterSetCurrentAttempt_InvalidAttempt(t *testing.T) {
+	body := `{"meet":"openlifter-test","lifterId":"uuid-9","lift":"snatch","attemptNumber":1,"weightKg":250}`
+	w := postCurrentAttempt(t, NewOpenLifterController("s3cret"), "Bearer s3cret", body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown lift")
+}
+
+func TestOpenLifterSetCurrentAttempt_Success(t *testing.T) {
+	websocket.ClearMeetState("openlifter-test")
+	w := postCurrentAttempt(t, NewOpenLifterController("s3cret"), "Bearer s3cret", currentAttemptBody)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	state := websocket.GetMeetState("openlifter-test")
+	if assert.NotNil(t, state.CurrentAttempt) {
+		assert.Equal(t, "uuid-9", state.CurrentAttempt.LifterID)
+		assert.Equal(t, 250.0, state.CurrentAttempt.WeightKg)
+	}
+}
+
+func TestOpenLifterSetCurrentAttempt_LegacyMeetName(t *testing.T) {
+	websocket.ClearMeetState("openlifter-test")
+	body := `{"meetName":"OpenLifter Test","lifterId":"uuid-9","lift":"deadlift","attemptNumber":1,"weightKg":250}`
+	w := postCurrentAttempt(t, NewOpenLifterController("s3cret"), "Bearer s3cret", body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotNil(t, websocket.GetMeetState("openlifter-test").CurrentAttempt, "state is keyed by the meet ID")
+
+	w = postCurrentAttempt(t, NewOpenLifterController("s3cret"), "Bearer s3cret", `{"meet":"nope","lift":"deadlift"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -3,14 +3,18 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sync"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/skip2/go-qrcode"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
 	"go-ref-lights/models"
 	"go-ref-lights/services"
 )
@@ -20,13 +24,22 @@ import (
 var anonOccupantCounter int
 var anonCounterMu sync.Mutex
 
-var (
-	// ApplicationURL is the base URL of the application
-	ApplicationURL string
+// -------------------- page controller --------------------
 
-	// WebsocketURL is the URL for the WebSocket server
-	WebsocketURL string
-)
+// PageController renders the pages that link back to the server: the positions,
+// seat and lights pages open WebSockets, and QR codes link referee seats.
+type PageController struct {
+	ApplicationURL string // prefix of the referee links in QR codes
+	WebsocketURL   string // endpoint the rendered pages connect to
+}
+
+// NewPageController initializes a PageController with the configured URLs.
+func NewPageController(cfg *config.Config) *PageController {
+	return &PageController{
+		ApplicationURL: cfg.ApplicationURL,
+		WebsocketURL:   cfg.WebsocketURL,
+	}
+}
 
 // -------------------- active users --------------------
 
@@ -58,20 +71,20 @@ func Home(c *gin.Context, occupancyService *services.OccupancyService) {
 
 	userEmail, ok1 := session.Get("user").(string)
 	position, ok2 := session.Get("refPosition").(string)
-	meetName, ok3 := session.Get("meetName").(string)
+	meetID, ok3 := session.Get("meetID").(string)
 
 	if ok1 && ok2 && ok3 {
-		if err := occupancyService.UnsetPosition(meetName, position, userEmail); err != nil {
+		if err := occupancyService.UnsetPosition(meetID, sessionPlatform(session), position, userEmail); err != nil {
 			logger.Error.Printf("[Home] Error vacating position: %v", err)
 		} else {
-			logger.Info.Printf("[Home] Position '%s' vacated for user '%s' in meet '%s'", position, userEmail, meetName)
+			logger.Info.Printf("[Home] Position '%s' vacated for user '%s' in meet '%s'", position, userEmail, meetID)
 			session.Delete("refPosition")
 			if err := session.Save(); err != nil {
 				logger.Error.Printf("[Home] Session save error after vacating position: %v", err)
 			}
 		}
 	} else {
-		logger.Warn.Println("[Home] Missing user, refPosition, or meetName in session.")
+		logger.Warn.Println("[Home] Missing user, refPosition, or meetID in session.")
 	}
 	c.Redirect(http.StatusFound, "/choose-meet")
 }
@@ -83,20 +96,19 @@ func Logout(c *gin.Context, occupancyService services.OccupancyServiceInterface)
 
 	userEmail, hasUser := session.Get("user").(string)
 	position, hasPosition := session.Get("refPosition").(string)
-	meetName, hasMeet := session.Get("meetName").(string)
+	meetID, hasMeet := session.Get("meetID").(string)
 
-	isAdmin, _ := session.Get("isAdmin").(bool)
-	if isAdmin && hasMeet {
-		logger.Info.Printf("[Logout] Admin user is logging out; resetting meet: %s", meetName)
-		occupancyService.ResetOccupancyForMeet(meetName)
+	if middleware.HasPermission(c, models.PermResetMeet) && hasMeet {
+		logger.Info.Printf("[Logout] Admin user is logging out; resetting meet: %s", meetID)
+		occupancyService.ResetOccupancyForMeet(meetID)
 	}
 
 	if hasUser && hasPosition && hasMeet {
-		if err := occupancyService.UnsetPosition(meetName, position, userEmail); err != nil {
+		if err := occupancyService.UnsetPosition(meetID, sessionPlatform(session), position, userEmail); err != nil {
 			logger.Error.Printf("[Logout] Error vacating position: %v", err)
 		} else {
 			logger.Info.Printf("[Logout] Position '%s' vacated for user '%s' in meet '%s'",
-				position, userEmail, meetName)
+				position, userEmail, meetID)
 		}
 
 		ActiveUsersMu.Lock()
@@ -105,7 +117,7 @@ func Logout(c *gin.Context, occupancyService services.OccupancyServiceInterface)
 
 		logger.Info.Printf("[Logout] User %s removed from active users list", userEmail)
 	} else {
-		logger.Warn.Println("[Logout] Missing user, refPosition, or meetName from session.")
+		logger.Warn.Println("[Logout] Missing user, refPosition, or meetID from session.")
 	}
 
 	session.Clear()
@@ -118,92 +130,107 @@ func Logout(c *gin.Context, occupancyService services.OccupancyServiceInterface)
 // Index renders the main dashboard page screen after logging in
 func Index(c *gin.Context) {
 	session := sessions.Default(c)
-	meetName, ok := session.Get("meetName").(string)
-	isSudo, _ := session.Get("sudo").(bool)
+	meetID, ok := session.Get("meetID").(string)
+	isSudo := middleware.HasPermission(c, models.PermManageAllMeets)
 
-	// If the user didn't pick any meetName and is not superuser, redirect them
-	if !ok || meetName == "" {
+	// If the user didn't pick any meet and is not superuser, redirect them
+	if !ok || meetID == "" {
 		c.Redirect(http.StatusFound, "/set-meet")
 		return
 	}
 
-	// If they selected "Sudo" as their meetName,
+	// If they selected "Sudo" as their meet,
 	// we skip normal meet logic and go to /sudo
-	if meetName == "Sudo" {
+	if meetID == services.SudoMeetName {
 		c.Redirect(http.StatusFound, "/sudo")
 		return
 	}
 
 	// Normal meet logic:
-	creds, err := loadMeetCredsFunc()
+	currentMeet, err := lookupMeet(meetID)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		logger.Warn.Printf("[Index] Meet not found: %s", meetID)
+		c.String(http.StatusNotFound, "Meet not found")
+		return
+	}
 	if err != nil {
 		logger.Error.Printf("[Index] Failed to load meet creds: %v", err)
 		c.String(http.StatusInternalServerError, "Failed to load meet credentials")
 		return
 	}
 
-	// find the current meet
-	var currentMeet *models.Meet
-	for _, m := range creds.Meets {
-		if m.Name == meetName {
-			currentMeet = &m
-			break
-		}
-	}
-	if currentMeet == nil {
-		logger.Warn.Printf("[Index] Meet not found: %s", meetName)
-		c.String(http.StatusNotFound, "Meet not found")
-		return
-	}
-
+	platform, _ := currentMeet.FindPlatform(sessionPlatform(session))
 	data := gin.H{
-		"meetName": meetName,
-		"IsSudo":   isSudo,
-		"Logo":     currentMeet.Logo,
+		"meetID":       currentMeet.ID,
+		"meetName":     currentMeet.Name,
+		"platformID":   platform.ID,
+		"platformName": platform.Name,
+		"platforms":    currentMeet.PlatformList(),
+		"positions":    currentMeet.PositionList(),
+		"IsSudo":       isSudo,
+		"Logo":         currentMeet.Logo,
 	}
 
 	c.HTML(http.StatusOK, "index.html", data)
 }
 
 // ShowPositionsPage renders the positions selection page.
-func ShowPositionsPage(c *gin.Context) {
+func (pc *PageController) ShowPositionsPage(c *gin.Context) {
 	session := sessions.Default(c)
 	user := session.Get("user")
-	meetName, ok := session.Get("meetName").(string)
-	if user == nil || !ok || meetName == "" {
+	meetID, ok := session.Get("meetID").(string)
+	if user == nil || !ok || meetID == "" {
 		logger.Warn.Println("[ShowPositionsPage] User not logged in or no meet selected; redirecting to /meets")
 		c.Redirect(http.StatusFound, "/meets")
 		return
 	}
 
+	platformID := sessionPlatform(session)
 	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
-		"Positions": map[string]interface{}{
-			"LeftOccupied":   false, // todo: Example data, replace with actual occupancy logic
-			"LeftUser":       "",
-			"centerOccupied": false,
-			"centerUser":     "",
-			"RightOccupied":  false,
-			"RightUser":      "",
-		},
+		"WebsocketURL": pc.WebsocketURL,
+		"meetID":       meetID,
+		"meetName":     meetDisplayName(meetID),
+		"platformID":   platformID,
+		"platformName": platformName(meetID, platformID),
+		"Seats":        panelSeats(PositionsFor(meetID), services.Occupancy{}), // todo: show actual occupancy
 	}
 	logger.Info.Println("[ShowPositionsPage] Rendering positions page")
 	c.HTML(http.StatusOK, "positions.html", data)
 }
 
-// GetQRCode generates and returns a QR code for the application URL.
-func GetQRCode(c *gin.Context) {
+// GetQRCode generates a QR code linking a referee seat on the session's platform, or
+// the one named by ?platform=. The link uses the meet's slug, so it stays short and
+// URL-safe whatever the meet is called.
+func (pc *PageController) GetQRCode(c *gin.Context) {
 	logger.Info.Println("[GetQRCode] Generating QR code")
 
-	meetName := c.Query("meetName")
+	session := sessions.Default(c)
+	meetID, _ := session.Get("meetID").(string)
+	platformID := c.DefaultQuery("platform", sessionPlatform(session))
 	position := c.Query("position")
-	if meetName == "" || position == "" {
-		c.String(http.StatusBadRequest, "Missing meetName or position query param")
+	if meetID == "" || position == "" {
+		c.String(http.StatusBadRequest, "Missing meet or position query param")
+		return
+	}
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		logger.Warn.Printf("[GetQRCode] Meet %s not found: %v", meetID, err)
+		c.String(http.StatusNotFound, "Meet not found")
 		return
 	}
 
-	qrURL := fmt.Sprintf("%s/referee/%s/%s", ApplicationURL, meetName, position)
+	platform, ok := meet.FindPlatform(platformID)
+	if !ok {
+		c.String(http.StatusNotFound, "Platform not found")
+		return
+	}
+
+	if _, ok := meet.FindPosition(position); !ok {
+		c.String(http.StatusNotFound, "Unknown position: %s", position)
+		return
+	}
+
+	qrURL := pc.refereeURL(meet, platform.ID, position)
 
 	qrBytes, err := services.GenerateQRCode(qrURL, 300, qrcode.Medium)
 	if err != nil {
@@ -219,117 +246,146 @@ func GetQRCode(c *gin.Context) {
 	}
 }
 
-// SetConfig updates the global application and WebSocket URLs.
-func SetConfig(appURL, wsURL string) {
-	ApplicationURL = appURL
-	WebsocketURL = wsURL
-	logger.Info.Printf("[SetConfig] Global config updated: ApplicationURL=%s, WebsocketURL=%s", appURL, wsURL)
+// refereeURL is the link a referee scans to take a seat. Seats on the meet's first
+// platform keep the short form, /referee/<slug>/<position>.
+func (pc *PageController) refereeURL(meet *models.Meet, platformID, position string) string {
+	return pc.ApplicationURL + refereePath(meet, platformID, position)
 }
 
-// -------------------- referee view rendering --------------------
-
-// Left renders the left referee view
-func Left(c *gin.Context) {
-	session := sessions.Default(c)
-	meetName, ok := session.Get("meetName").(string)
-	refPosition := session.Get("refPosition")
-	logger.Debug.Printf("[Left handler] Session meetName='%s', refPosition='%v'", meetName, refPosition)
-	if !ok || meetName == "" {
-		c.Redirect(http.StatusFound, "/meets")
-		return
-	}
-	logger.Info.Println("[Left] Rendering left referee view")
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
+// refereePath is refereeURL without the host.
+func refereePath(meet *models.Meet, platformID, position string) string {
+	if platformID == meet.DefaultPlatform().ID {
+		return fmt.Sprintf("/referee/%s/%s", meet.Slug, url.PathEscape(position))
 	}
-	c.HTML(http.StatusOK, "left.html", data)
+	return fmt.Sprintf("/referee/%s/%s/%s", meet.Slug, url.PathEscape(platformID), url.PathEscape(position))
 }
 
-// Center renders the center referee view
-func Center(c *gin.Context) {
-	session := sessions.Default(c)
-	meetName, ok := session.Get("meetName").(string)
-	refPosition := session.Get("refPosition")
-	logger.Debug.Printf("[Center handler] Session meetName='%s', refPosition='%v'", meetName, refPosition)
-	if !ok || meetName == "" {
-		c.Redirect(http.StatusFound, "/meets")
-		return
-	}
-	logger.Info.Println("[Center] Rendering center referee view")
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
-	}
-	c.HTML(http.StatusOK, "center.html", data)
-}
+// -------------------- referee view rendering --------------------
 
-// Right renders the right referee view
-func Right(c *gin.Context) {
+// Seat renders the page a seated referee judges from. The seat must be one of the
+// meet's positions; jury seats get a read-only view of the decisions.
+func (pc *PageController) Seat(c *gin.Context) {
 	session := sessions.Default(c)
-	meetName, ok := session.Get("meetName").(string)
-	refPosition := session.Get("refPosition")
-	logger.Debug.Printf("[Right handler] Session meetName='%s', refPosition='%v'", meetName, refPosition)
-
-	if !ok || meetName == "" {
+	meetID, ok := session.Get("meetID").(string)
+	position := c.Param("position")
+	logger.Debug.Printf("[Seat] Session meetID='%s', position='%s'", meetID, position)
+	if !ok || meetID == "" {
 		c.Redirect(http.StatusFound, "/meets")
 		return
 	}
 
-	logger.Info.Println("[Right] Rendering right referee view")
-
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		logger.Warn.Printf("[Seat] Could not load meet=%s: %v", meetID, err)
+		meet = &models.Meet{ID: meetID, Name: meetID}
 	}
-
-	c.HTML(http.StatusOK, "right.html", data)
+	seat, ok := meet.FindPosition(position)
+	if !ok {
+		c.String(http.StatusNotFound, "Unknown position: %s", position)
+		return
+	}
+	logger.Info.Printf("[Seat] Rendering %s referee view", seat.ID)
+	c.HTML(http.StatusOK, "seat.html", pc.seatPageData(meet, sessionPlatform(session), seat))
 }
 
 // Lights renders the light control panel
-func Lights(c *gin.Context) {
+func (pc *PageController) Lights(c *gin.Context) {
 	session := sessions.Default(c)
-	meetName, ok := session.Get("meetName").(string)
-	if !ok || meetName == "" {
+	meetID, ok := session.Get("meetID").(string)
+	if !ok || meetID == "" {
 		c.Redirect(http.StatusFound, "/meets")
 		return
 	}
 	logger.Info.Println("[Lights] Rendering lights page")
 
-	creds, err := loadMeetCredsFunc()
+	currentMeet, err := lookupMeet(meetID)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		logger.Warn.Printf("[Lights] Meet not found: %s", meetID)
+		c.String(http.StatusNotFound, "Meet not found")
+		return
+	}
 	if err != nil {
 		logger.Error.Printf("[Lights] Failed to load meet creds: %v", err)
 		c.String(http.StatusInternalServerError, "Failed to load meet credentials")
 		return
 	}
 
-	// find the current meet
-	var currentMeet *models.Meet
-	for _, m := range creds.Meets {
-		if m.Name == meetName {
-			currentMeet = &m
-			break
-		}
-	}
-	if currentMeet == nil {
-		logger.Warn.Printf("[Lights] Meet not found: %s", meetName)
-		c.String(http.StatusNotFound, "Meet not found")
+	// a display may follow any platform of the meet; ?platform= picks one
+	platform, ok := currentMeet.FindPlatform(c.DefaultQuery("platform", sessionPlatform(session)))
+	if !ok {
+		c.String(http.StatusNotFound, "Platform not found")
 		return
 	}
 
 	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
-		"Logo":         currentMeet.Logo,
+		"WebsocketURL":    pc.WebsocketURL,
+		"meetID":          currentMeet.ID,
+		"meetName":        currentMeet.Name,
+		"platformID":      platform.ID,
+		"platformName":    platform.Name,
+		"multiPlatform":   len(currentMeet.PlatformList()) > 1,
+		"referees":        refereePositions(currentMeet),
+		"Logo":            currentMeet.Logo,
+		"canControlTimer": middleware.HasPermission(c, models.PermControlTimer),
+	}
+	if currentMeet.Schedule != nil {
+		if active, ok := currentMeet.Schedule.Active(nowFunc()); ok {
+			data["activeSession"] = active.ForPlatform(platform.Name)
+		}
 	}
 
 	c.HTML(http.StatusOK, "lights.html", data)
 }
 
-// RefereeHandler renders the referee view based on the position parameter.
-func RefereeHandler(c *gin.Context, occupancyService services.OccupancyServiceInterface) {
-	meetName := c.Param("meetName")
-	position := c.Param("position")
+// refereePositions returns the voting seats of a meet, one light each on the display.
+func refereePositions(meet *models.Meet) []models.Position {
+	var referees []models.Position
+	for _, p := range meet.PositionList() {
+		if p.Votes() {
+			referees = append(referees, p)
+		}
+	}
+	return referees
+}
+
+// RefereeHandler renders the referee view based on the position parameter. The meet
+// is addressed by slug; links made with an ID or a display name are redirected there.
+// It serves /referee/<meet>/<platform>/<position> and, for the meet's first platform,
+// /referee/<meet>/<position>.
+func (pc *PageController) RefereeHandler(c *gin.Context, occupancyService services.OccupancyServiceInterface) {
+	ref := c.Param("meet")
+	platformID, position := c.Param("platform"), c.Param("position")
+	if position == "" {
+		platformID, position = "", platformID
+	}
+
+	meet, err := resolveMeet(ref)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		logger.Warn.Printf("[RefereeHandler] Unknown meet %q", ref)
+		c.String(http.StatusNotFound, "Meet not found")
+		return
+	}
+	if err != nil {
+		logger.Error.Printf("[RefereeHandler] Failed to load meets: %v", err)
+		c.String(http.StatusInternalServerError, "Failed to load meet credentials")
+		return
+	}
+	platform, ok := meet.FindPlatform(platformID)
+	if !ok {
+		logger.Warn.Printf("[RefereeHandler] Unknown platform %q for meet=%s", platformID, meet.ID)
+		c.String(http.StatusNotFound, "Platform not found")
+		return
+	}
+	seat, ok := meet.FindPosition(position)
+	if !ok {
+		logger.Warn.Printf("[RefereeHandler] Unknown position %q for meet=%s", position, meet.ID)
+		c.String(http.StatusNotFound, "Unknown position: %s", position)
+		return
+	}
+	if ref != meet.Slug {
+		c.Redirect(http.StatusMovedPermanently, refereePath(meet, platform.ID, position))
+		return
+	}
 
 	// 1) Get or create a unique occupant for this session
 	session := sessions.Default(c)
@@ -343,7 +399,7 @@ func RefereeHandler(c *gin.Context, occupancyService services.OccupancyServiceIn
 	}
 
 	// 2) Attempt to claim seat under occupant's name
-	if err := occupancyService.SetPosition(meetName, position, occupant); err != nil {
+	if err := occupancyService.SetPosition(meet.ID, platform.ID, position, occupant); err != nil {
 		logger.Warn.Printf("[RefereeHandler] Attempt to claim seat=%s for occupant=%s failed: %v",
 			position, occupant, err)
 		c.String(http.StatusConflict, "This referee seat (%s) is already taken.", position)
@@ -353,50 +409,32 @@ func RefereeHandler(c *gin.Context, occupancyService services.OccupancyServiceIn
 	// 3) Update the session so that .VacatePosition will find "user" + "refPosition"
 	session.Set("user", occupant)
 	session.Set("refPosition", position)
+	session.Set("meetID", meet.ID) // the WebSocket upgrade binds the seat to this meet
+	session.Set("platform", platform.ID)
 	if err := session.Save(); err != nil {
 		logger.Error.Printf("[RefereeHandler] Failed to save session for occupant=%s: %v", occupant, err)
 	}
 
 	// 4) Log success
-	logger.Info.Printf("[RefereeHandler] meetName=%s, position=%s claimed successfully by occupant=%s",
-		meetName, position, occupant)
-
-	// 5) Render the appropriate referee view
-	switch position {
-	case "left", "Left":
-		renderLeft(c, meetName)
-	case "center", "Center":
-		renderCenter(c, meetName)
-	case "right", "Right":
-		renderRight(c, meetName)
-	default:
-		c.String(http.StatusBadRequest, "Unknown position: %s", position)
-	}
-}
-
-// renderCenter renders the center referee page
-func renderCenter(c *gin.Context, meetName string) {
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
-	}
-	c.HTML(http.StatusOK, "center.html", data)
-}
+	logger.Info.Printf("[RefereeHandler] meet=%s, platform=%s, position=%s claimed successfully by occupant=%s",
+		meet.ID, platform.ID, position, occupant)
 
-// renderRight renders the right referee page
-func renderRight(c *gin.Context, meetName string) {
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
-	}
-	c.HTML(http.StatusOK, "right.html", data)
+	// 5) Render the seat's referee view
+	c.HTML(http.StatusOK, "seat.html", pc.seatPageData(meet, platform.ID, seat))
 }
 
-// renderLeft renders the left referee page
-func renderLeft(c *gin.Context, meetName string) {
-	data := gin.H{
-		"WebsocketURL": WebsocketURL,
-		"meetName":     meetName,
+// seatPageData is the template data for a seat's referee view. Only voting seats get
+// decision buttons, and only the chief gets the clock.
+func (pc *PageController) seatPageData(meet *models.Meet, platformID string, seat models.Position) gin.H {
+	return gin.H{
+		"WebsocketURL": pc.WebsocketURL,
+		"meetID":       meet.ID,
+		"meetName":     meet.Name,
+		"platformID":   platformID,
+		"platformName": platformName(meet.ID, platformID),
+		"positionID":   seat.ID,
+		"positionName": seat.Name,
+		"votes":        seat.Votes(),
+		"chief":        seat.ID == meet.ChiefPosition().ID,
 	}
-	c.HTML(http.StatusOK, "left.html", data)
 }
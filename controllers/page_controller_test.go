@@ -9,16 +9,26 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go-ref-lights/config"
 	"go-ref-lights/models"
 	"go-ref-lights/websocket"
 )
 
 var mockOccService = new(MockOccupancyService)
 
+// registerRefereeRoutes mounts RefereeHandler on both referee URL forms, as main.go does.
+func registerRefereeRoutes(router *gin.Engine) {
+	pages := NewPageController(config.Default())
+	handler := func(c *gin.Context) { pages.RefereeHandler(c, mockOccService) }
+	router.GET("/referee/:meet/:platform", handler)
+	router.GET("/referee/:meet/:platform/:position", handler)
+}
+
 // TestHealth tests the Health function
 func TestHealth(t *testing.T) {
 	websocket.InitTest()
@@ -137,7 +147,7 @@ func Test_WithMeetName(t *testing.T) {
 	}()
 
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"meetName": "TestMeet",
+		"meetID": "testmeet",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
@@ -160,7 +170,7 @@ func Test_WithMeetName(t *testing.T) {
 // TestLights_NoMeetSelected tests the Lights handler when no meet is selected
 func TestLights_NoMeetSelected(t *testing.T) {
 	router := setupTestRouter(t)
-	router.GET("/lights", Lights)
+	router.GET("/lights", NewPageController(config.Default()).Lights)
 
 	req, _ := http.NewRequest("GET", "/lights", nil)
 	w := httptest.NewRecorder()
@@ -170,6 +180,33 @@ func TestLights_NoMeetSelected(t *testing.T) {
 	assert.Equal(t, "/meets", w.Header().Get("Location"))
 }
 
+// TestLights_ShowsActiveSession checks the lights page names the session under way.
+func TestLights_ShowsActiveSession(t *testing.T) {
+	router := setupTestRouter(t)
+	router.GET("/lights", NewPageController(config.Default()).Lights)
+
+	originalFunc, originalNow := loadMeetCredsFunc, nowFunc
+	loadMeetCredsFunc = func() (*models.MeetCreds, error) {
+		return &models.MeetCreds{Meets: []models.Meet{{Name: "TestMeet", Schedule: &models.Schedule{
+			TimeZone: "UTC", StartDate: "2025-03-22",
+			Days: []models.MeetDay{{Date: "2025-03-22", Sessions: []models.Session{
+				{Name: "Session 1", Start: "09:00"}, {Name: "Session 2", Start: "13:00"},
+			}}},
+		}}}}, nil
+	}
+	nowFunc = func() time.Time { return time.Date(2025, 3, 22, 14, 0, 0, 0, time.UTC) }
+	defer func() { loadMeetCredsFunc, nowFunc = originalFunc, originalNow }()
+
+	cookie := SetSession(router, "/set-session", map[string]interface{}{"meetID": "testmeet"})
+	req, _ := http.NewRequest("GET", "/lights", nil)
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Lights for TestMeet (Session 2)")
+}
+
 // TestIndex_WithMeetName tests the Index handler when a meet is selected
 func TestIndex_WithMeetName(t *testing.T) {
 	router := setupTestRouter(t)
@@ -192,7 +229,7 @@ func TestIndex_WithMeetName(t *testing.T) {
 
 	// Put "meetName" in the session so the /index route sees we selected "TestMeet"
 	sessionCookie := SetSession(router, "/set-session", map[string]interface{}{
-		"meetName": "TestMeet",
+		"meetID": "testmeet",
 	})
 	if sessionCookie == nil {
 		t.Fatal("Session cookie not found")
@@ -217,20 +254,19 @@ func TestIndex_WithMeetName(t *testing.T) {
 // TestRefereeHandler_Success tests the RefereeHandler function when it should succeed.
 func TestRefereeHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "DemoMeet"}}})
 	router := setupTestRouter(t)
 
 	// For this route, the code calls RefereeHandler(..., mockOccService)
-	router.GET("/referee/:meetName/:position", func(c *gin.Context) {
-		RefereeHandler(c, mockOccService)
-	})
+	registerRefereeRoutes(router)
 
 	// The occupant tries to claim seat => success => Return nil (no error)
 	mockOccService.
-		On("SetPosition", "DemoMeet", "left", mock.AnythingOfType("string")).
+		On("SetPosition", "demomeet", models.DefaultPlatformID, "left", mock.AnythingOfType("string")).
 		Return(nil).
 		Once()
 
-	req, _ := http.NewRequest("GET", "/referee/DemoMeet/left", nil)
+	req, _ := http.NewRequest("GET", "/referee/demomeet/left", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -244,19 +280,18 @@ func TestRefereeHandler_Success(t *testing.T) {
 // TestRefereeHandler_Conflict tests the RefereeHandler function when SetPosition should fail.
 func TestRefereeHandler_Conflict(t *testing.T) {
 	gin.SetMode(gin.TestMode)
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "DemoMeet"}}})
 	router := setupTestRouter(t)
 
-	router.GET("/referee/:meetName/:position", func(c *gin.Context) {
-		RefereeHandler(c, mockOccService)
-	})
+	registerRefereeRoutes(router)
 
 	// This time, for the first (and only) call, we simulate an already-occupied seat => return error
 	mockOccService.
-		On("SetPosition", "DemoMeet", "left", mock.AnythingOfType("string")).
+		On("SetPosition", "demomeet", models.DefaultPlatformID, "left", mock.AnythingOfType("string")).
 		Return(fmt.Errorf("left seat is already taken")).
 		Once()
 
-	req, _ := http.NewRequest("GET", "/referee/DemoMeet/left", nil)
+	req, _ := http.NewRequest("GET", "/referee/demomeet/left", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
@@ -266,3 +301,116 @@ func TestRefereeHandler_Conflict(t *testing.T) {
 
 	mockOccService.AssertExpectations(t)
 }
+
+// TestRefereeHandler_RedirectsLegacyURLs checks links made with a meet's name or ID
+// land on the slug URL, and unknown meets are not found.
+func TestRefereeHandler_RedirectsLegacyURLs(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{
+		{Name: "New South Wales State Championships "},
+		{ID: "cairns-2025", Slug: "cairns", Name: "Cairns Cup"},
+	}})
+	router := setupTestRouter(t)
+	registerRefereeRoutes(router)
+
+	for path, want := range map[string]string{
+		"/referee/New%20South%20Wales%20State%20Championships%20/left": "/referee/new-south-wales-state-championships/left",
+		"/referee/Cairns%20Cup/center":                                 "/referee/cairns/center",
+		"/referee/cairns-2025/right":                                   "/referee/cairns/right",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusMovedPermanently, w.Code, path)
+		assert.Equal(t, want, w.Header().Get("Location"), path)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/referee/no-such-meet/left", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestRefereeURL checks QR codes link the slug, not the display name.
+func TestRefereeURL(t *testing.T) {
+	pc := &PageController{ApplicationURL: "https://lights.example.com"}
+
+	meet := &models.Meet{ID: "nsw-2025", Slug: "nsw", Name: "New South Wales State Championships "}
+	assert.Equal(t, "https://lights.example.com/referee/nsw/left", pc.refereeURL(meet, models.DefaultPlatformID, "left"))
+
+	// the first platform keeps the short link; others name their platform
+	meet.Platforms = []models.Platform{{ID: "a", Name: "Platform A"}, {ID: "b", Name: "Platform B"}}
+	assert.Equal(t, "https://lights.example.com/referee/nsw/left", pc.refereeURL(meet, "a", "left"))
+	assert.Equal(t, "https://lights.example.com/referee/nsw/b/left", pc.refereeURL(meet, "b", "left"))
+}
+
+// TestRefereeHandler_SeatsPlatform checks a platform link seats the referee on that
+// platform, and unknown platforms are not found.
+func TestRefereeHandler_SeatsPlatform(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		Name:      "DemoMeet",
+		Platforms: []models.Platform{{ID: "a", Name: "Platform A"}, {ID: "b", Name: "Platform B"}},
+	}}})
+	router := setupTestRouter(t)
+	registerRefereeRoutes(router)
+
+	mockOccService.
+		On("SetPosition", "demomeet", "b", "center", mock.AnythingOfType("string")).
+		Return(nil).
+		Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/referee/demomeet/b/center", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockOccService.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/referee/demomeet/c/center", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestRefereeHandler_ConfiguredPositions checks a meet's own panel decides which
+// seats can be claimed, and that each seat page is named after its position.
+func TestRefereeHandler_ConfiguredPositions(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		Name: "DemoMeet",
+		Positions: []models.Position{
+			{ID: "referee", Name: "Referee"},
+			{ID: "jury", Name: "Jury", Kind: models.SeatJury},
+		},
+	}}})
+	router := setupTestRouter(t)
+	registerRefereeRoutes(router)
+
+	mockOccService.
+		On("SetPosition", "demomeet", models.DefaultPlatformID, "jury", mock.AnythingOfType("string")).
+		Return(nil).
+		Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/referee/demomeet/jury", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Jury view for DemoMeet")
+	mockOccService.AssertExpectations(t)
+
+	// the default seats are not on this meet's panel
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/referee/demomeet/left", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// TestSeatPageData checks only voting seats get decision buttons and only the chief
+// gets the clock.
+func TestSeatPageData(t *testing.T) {
+	meet := &models.Meet{ID: "demomeet", Name: "DemoMeet"}
+	pc := NewPageController(config.Default())
+
+	center := pc.seatPageData(meet, models.DefaultPlatformID, models.DefaultPositions[1])
+	assert.Equal(t, true, center["votes"])
+	assert.Equal(t, true, center["chief"])
+
+	left := pc.seatPageData(meet, models.DefaultPlatformID, models.DefaultPositions[0])
+	assert.Equal(t, "Left Referee", left["positionName"])
+	assert.Equal(t, false, left["chief"])
+
+	jury := pc.seatPageData(meet, models.DefaultPlatformID, models.Position{ID: "jury", Name: "Jury", Kind: models.SeatJury})
+	assert.Equal(t, false, jury["votes"])
+	assert.Equal(t, false, jury["chief"])
+}
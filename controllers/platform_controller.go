@@ -0,0 +1,101 @@
+// Package controllers handles choosing which platform of a meet a session works on.
+// File: controllers/platform_controller.go
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/models"
+	"go-ref-lights/websocket"
+)
+
+// sessionPlatform returns the platform ID chosen in the session. Sessions that have not
+// chosen one, or chose a platform the meet no longer has, use the meet's first platform.
+func sessionPlatform(session sessions.Session) string {
+	platformID, _ := session.Get("platform").(string)
+	meetID, _ := session.Get("meetID").(string)
+	if meet, err := lookupMeet(meetID); err == nil {
+		if platform, ok := meet.FindPlatform(platformID); ok {
+			return platform.ID
+		}
+		return meet.DefaultPlatform().ID
+	}
+	if platformID != "" {
+		return platformID
+	}
+	return models.DefaultPlatformID
+}
+
+// platformName returns the display name of a platform, or its ID if the meet or
+// platform cannot be found.
+func platformName(meetID, platformID string) string {
+	if meet, err := lookupMeet(meetID); err == nil {
+		if platform, ok := meet.FindPlatform(platformID); ok {
+			return platform.Name
+		}
+	}
+	return platformID
+}
+
+// meetPlatforms lists a meet's platforms, or just the main platform if the meet
+// cannot be found.
+func meetPlatforms(meetID string) []models.Platform {
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		return models.Meet{}.PlatformList()
+	}
+	return meet.PlatformList()
+}
+
+// hasPlatform reports whether platformID is one of the meet's platforms.
+func hasPlatform(meetID, platformID string) bool {
+	for _, platform := range meetPlatforms(meetID) {
+		if platform.ID == platformID {
+			return true
+		}
+	}
+	return false
+}
+
+// clearMeetStates drops the live state of every platform of a meet.
+func clearMeetStates(meet *models.Meet) {
+	for _, platform := range meet.PlatformList() {
+		websocket.ClearMeetState(meet.PlatformKey(platform.ID))
+	}
+}
+
+// SetPlatformHandler switches the session to another platform of its meet. A seated
+// referee must vacate their seat first, since the seat belongs to the old platform.
+func SetPlatformHandler(c *gin.Context) {
+	session := sessions.Default(c)
+	meetID, _ := session.Get("meetID").(string)
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		logger.Warn.Printf("[SetPlatformHandler] No meet for session meet=%q: %v", meetID, err)
+		c.Redirect(http.StatusFound, "/meets")
+		return
+	}
+
+	platform, ok := meet.FindPlatform(c.PostForm("platformID"))
+	if !ok {
+		c.String(http.StatusBadRequest, "Unknown platform")
+		return
+	}
+	if position, _ := session.Get("refPosition").(string); position != "" && platform.ID != sessionPlatform(session) {
+		c.String(http.StatusConflict, "Vacate your %s seat before switching platforms", position)
+		return
+	}
+
+	session.Set("platform", platform.ID)
+	if err := session.Save(); err != nil {
+		logger.Error.Printf("[SetPlatformHandler] Failed to save session: %v", err)
+		c.String(http.StatusInternalServerError, "Error saving session")
+		return
+	}
+	logger.Info.Printf("[SetPlatformHandler] Session switched to meet=%s platform=%s", meet.ID, platform.ID)
+	c.Redirect(http.StatusFound, "/index")
+}
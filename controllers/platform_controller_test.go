@@ -0,0 +1,84 @@
+// controllers/platform_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go-ref-lights/models"
+)
+
+// platformRouter serves SetPlatformHandler and reports the session's platform.
+func platformRouter(t *testing.T, session map[string]interface{}) (*gin.Engine, *http.Cookie) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		ID:        "testmeet",
+		Name:      "Test Meet",
+		Platforms: []models.Platform{{ID: "a", Name: "Platform A"}, {ID: "b", Name: "Platform B"}},
+	}}})
+	router := setupTestRouter(t)
+	router.POST("/set-platform", SetPlatformHandler)
+	router.GET("/platform", func(c *gin.Context) {
+		c.String(http.StatusOK, sessionPlatform(sessions.Default(c)))
+	})
+	return router, SetSession(router, "/set-session", session)
+}
+
+func postPlatform(router *gin.Engine, cookie *http.Cookie, platformID string) *httptest.ResponseRecorder {
+	form := url.Values{"platformID": {platformID}}
+	req, _ := http.NewRequest(http.MethodPost, "/set-platform", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func getPlatform(router *gin.Engine, cookies ...*http.Cookie) string {
+	req, _ := http.NewRequest(http.MethodGet, "/platform", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Body.String()
+}
+
+func TestSessionPlatform_DefaultsToFirstPlatform(t *testing.T) {
+	router, cookie := platformRouter(t, map[string]interface{}{"meetID": "testmeet", "platform": "gone"})
+	assert.Equal(t, "a", getPlatform(router, cookie), "a platform the meet no longer has falls back to its first")
+}
+
+func TestSetPlatformHandler_SwitchesPlatform(t *testing.T) {
+	router, cookie := platformRouter(t, map[string]interface{}{"meetID": "testmeet", "user": "director"})
+
+	w := postPlatform(router, cookie, "b")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/index", w.Header().Get("Location"))
+	assert.Equal(t, "b", getPlatform(router, w.Result().Cookies()...))
+}
+
+func TestSetPlatformHandler_RejectsUnknownPlatform(t *testing.T) {
+	router, cookie := platformRouter(t, map[string]interface{}{"meetID": "testmeet"})
+
+	w := postPlatform(router, cookie, "main")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSetPlatformHandler_SeatedRefereeMustVacate(t *testing.T) {
+	router, cookie := platformRouter(t, map[string]interface{}{
+		"meetID": "testmeet", "user": "ref1", "refPosition": "left", "platform": "a",
+	})
+
+	w := postPlatform(router, cookie, "b")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "Vacate your left seat")
+}
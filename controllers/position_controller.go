@@ -5,10 +5,14 @@ package controllers
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
+	"go-ref-lights/protocol"
 	"go-ref-lights/services"
 	"go-ref-lights/websocket"
 )
@@ -30,29 +34,23 @@ func NewPositionController(service services.OccupancyServiceInterface) *Position
 func (pc *PositionController) ShowPositionsPage(c *gin.Context) {
 	session := sessions.Default(c)
 	user := session.Get("user")
-	meetName, ok := session.Get("meetName").(string)
-	if user == nil || !ok || meetName == "" {
+	meetID, ok := session.Get("meetID").(string)
+	if user == nil || !ok || meetID == "" {
 		logger.Warn.Println("[ShowPositionsPage] User not logged in or no meet selected; redirecting to /meets")
 		c.Redirect(http.StatusFound, "/meets")
 		return
 	}
 
-	occ := pc.OccupancyService.GetOccupancy(meetName)
+	platformID := sessionPlatform(session)
+	occ := pc.OccupancyService.GetOccupancy(meetID, platformID)
 	logger.Debug.Printf("[ShowPositionsPage] Retrieved occupancy state: %+v", occ)
 
-	// Possibly redundant second call?
-	occ = pc.OccupancyService.GetOccupancy(meetName)
-
 	data := gin.H{
-		"Positions": map[string]interface{}{
-			"LeftOccupied":   occ.LeftUser != "",
-			"LeftUser":       occ.LeftUser,
-			"centerOccupied": occ.CenterUser != "",
-			"centerUser":     occ.CenterUser,
-			"RightOccupied":  occ.RightUser != "",
-			"RightUser":      occ.RightUser,
-		},
-		"meetName": meetName,
+		"Seats":        panelSeats(PositionsFor(meetID), occ),
+		"meetID":       meetID,
+		"meetName":     meetDisplayName(meetID),
+		"platformID":   platformID,
+		"platformName": platformName(meetID, platformID),
 	}
 
 	logger.Info.Println("[ShowPositionsPage] Rendering positions page")
@@ -65,36 +63,39 @@ func (pc *PositionController) ShowPositionsPage(c *gin.Context) {
 func (pc *PositionController) ClaimPosition(c *gin.Context) {
 	session := sessions.Default(c)
 	user := session.Get("user")
-	meetName, ok := session.Get("meetName").(string)
+	meetID, ok := session.Get("meetID").(string)
 
-	if user == nil || !ok || meetName == "" {
+	if user == nil || !ok || meetID == "" {
 		logger.Warn.Println("[ClaimPosition] User not logged in or no meet selected; redirecting to /login")
 		c.Redirect(http.StatusFound, "/login")
 		return
 	}
 
 	position := c.PostForm("position")
+	platformID := sessionPlatform(session)
 	userEmail := user.(string)
-	logger.Info.Printf("[ClaimPosition] User=%s attempting to claim position=%s in meet=%s", userEmail, position, meetName)
+	if perm := claimPermission(meetID, position); !middleware.HasPermission(c, perm) {
+		logger.Warn.Printf("[ClaimPosition] Role %s lacks %s for position=%s", middleware.SessionRole(c), perm, position)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Permission required: " + string(perm)})
+		return
+	}
+	logger.Info.Printf("[ClaimPosition] User=%s attempting to claim position=%s in meet=%s platform=%s",
+		userEmail, position, meetID, platformID)
 
-	err := pc.OccupancyService.SetPosition(meetName, position, userEmail)
+	err := pc.OccupancyService.SetPosition(meetID, platformID, position, userEmail)
 	if err != nil {
 		logger.Error.Printf("[ClaimPosition] Position is taken or invalid: %v", err)
 		// Replacing old fmt.Println:
-		logger.Debug.Printf("[ClaimPosition] Controller calling GetOccupancy with: %s", meetName)
+		logger.Debug.Printf("[ClaimPosition] Controller calling GetOccupancy with: %s", meetID)
 
-		occ := pc.OccupancyService.GetOccupancy(meetName)
+		occ := pc.OccupancyService.GetOccupancy(meetID, platformID)
 		c.HTML(http.StatusForbidden, "positions.html", gin.H{
-			"Error":    "Sorry, that referee position is already occupied. Please choose a different one.",
-			"meetName": meetName,
-			"Positions": map[string]interface{}{
-				"LeftOccupied":   occ.LeftUser != "",
-				"LeftUser":       occ.LeftUser,
-				"centerOccupied": occ.CenterUser != "",
-				"centerUser":     occ.CenterUser,
-				"RightOccupied":  occ.RightUser != "",
-				"RightUser":      occ.RightUser,
-			},
+			"Error":        "Sorry, that referee position is already occupied. Please choose a different one.",
+			"meetID":       meetID,
+			"meetName":     meetDisplayName(meetID),
+			"platformID":   platformID,
+			"platformName": platformName(meetID, platformID),
+			"Seats":        panelSeats(PositionsFor(meetID), occ),
 		})
 		return
 	}
@@ -107,22 +108,12 @@ func (pc *PositionController) ClaimPosition(c *gin.Context) {
 		return
 	}
 
-	logger.Info.Printf("[ClaimPosition] User=%s successfully claimed position=%s for meet=%s", userEmail, position, meetName)
+	logger.Info.Printf("[ClaimPosition] User=%s successfully claimed position=%s for meet=%s", userEmail, position, meetID)
 
-	// redirect to the correct path
-	switch position {
-	case "left":
-		c.Redirect(http.StatusFound, "/left")
-	case "center":
-		c.Redirect(http.StatusFound, "/center")
-	case "right":
-		c.Redirect(http.StatusFound, "/right")
-	default:
-		logger.Warn.Printf("[ClaimPosition] Unknown position %s; redirecting to /positions", position)
-		c.Redirect(http.StatusFound, "/positions")
-	}
+	// redirect to the seat's page
+	c.Redirect(http.StatusFound, seatPath(position))
 	// broadcast occupancy changes asynchronously
-	go pc.BroadcastOccupancy(meetName)
+	go pc.BroadcastOccupancy(meetID, platformID)
 }
 
 // ------------------- Position vacancy -------------------
@@ -131,9 +122,9 @@ func (pc *PositionController) ClaimPosition(c *gin.Context) {
 func (pc *PositionController) VacatePosition(c *gin.Context) {
 	session := sessions.Default(c)
 	userEmail, ok := session.Get("user").(string)
-	meetName, ok2 := session.Get("meetName").(string)
+	meetID, ok2 := session.Get("meetID").(string)
 
-	if !ok || !ok2 || userEmail == "" || meetName == "" {
+	if !ok || !ok2 || userEmail == "" || meetID == "" {
 		logger.Warn.Println("[VacatePosition] User not logged in or no meet selected; redirecting to /login")
 		c.Redirect(http.StatusFound, "/index")
 		return
@@ -141,12 +132,13 @@ func (pc *PositionController) VacatePosition(c *gin.Context) {
 
 	position, ok3 := session.Get("refPosition").(string)
 	if !ok3 || position == "" {
-		logger.Warn.Printf("[VacatePosition] user=%s not in any seat for meet=%s; can't vacate", userEmail, meetName)
+		logger.Warn.Printf("[VacatePosition] user=%s not in any seat for meet=%s; can't vacate", userEmail, meetID)
 		c.Redirect(http.StatusFound, "/index")
 		return
 	}
 
-	if err := pc.OccupancyService.UnsetPosition(meetName, position, userEmail); err != nil {
+	platformID := sessionPlatform(session)
+	if err := pc.OccupancyService.UnsetPosition(meetID, platformID, position, userEmail); err != nil {
 		logger.Error.Printf("[VacatePosition] Error unsetting position for user=%s: %v", userEmail, err)
 		c.Redirect(http.StatusFound, "/index")
 		return
@@ -159,32 +151,29 @@ func (pc *PositionController) VacatePosition(c *gin.Context) {
 		return
 	}
 
-	logger.Info.Printf("[VacatePosition] user=%s vacated seat=%s for meet=%s", userEmail, position, meetName)
-	go pc.BroadcastOccupancy(meetName)
+	logger.Info.Printf("[VacatePosition] user=%s vacated seat=%s for meet=%s", userEmail, position, meetID)
+	go pc.BroadcastOccupancy(meetID, platformID)
 	c.Redirect(http.StatusFound, "/index")
 }
 
 // ------------------- Real-time occupancy updates -------------------
 
-// BroadcastOccupancy sends a real-time update of occupied referee positions.
-func (pc *PositionController) BroadcastOccupancy(meetName string) {
-	logger.Debug.Printf("[BroadcastOccupancy] Entering for meet=%s", meetName)
-	occ := pc.OccupancyService.GetOccupancy(meetName)
+// BroadcastOccupancy sends a real-time update of occupied referee positions on one
+// platform to the clients following it.
+func (pc *PositionController) BroadcastOccupancy(meetID, platformID string) {
+	logger.Debug.Printf("[BroadcastOccupancy] Entering for meet=%s platform=%s", meetID, platformID)
+	occ := pc.OccupancyService.GetOccupancy(meetID, platformID)
 
 	logger.Debug.Printf("[BroadcastOccupancy] Fetched occupancy: %+v", occ)
 
-	msg := map[string]interface{}{
-		"action":     "occupancyChanged",
-		"leftUser":   occ.LeftUser,
-		"centerUser": occ.CenterUser,
-		"rightUser":  occ.RightUser,
-		"meetName":   meetName,
-	}
+	header := protocol.NewHeader(protocol.ActionOccupancyChanged, meetID)
+	header.Platform = platformID
+	msg := protocol.OccupancyChanged{Header: header, Seats: occ.Seats}
 	jsonBytes, _ := json.Marshal(msg)
 	logger.Debug.Printf("[BroadcastOccupancy] Sending message: %s", string(jsonBytes))
 
-	go websocket.SendBroadcastMessage(jsonBytes)
-	logger.Debug.Printf("[BroadcastOccupancy] Finished for meet=%s", meetName)
+	go websocket.SendBroadcastMessage(models.PlatformKey(meetID, platformID), jsonBytes)
+	logger.Debug.Printf("[BroadcastOccupancy] Finished for meet=%s", meetID)
 }
 
 // ------------------- API endpoints -------------------
@@ -192,18 +181,80 @@ func (pc *PositionController) BroadcastOccupancy(meetName string) {
 // GetOccupancyAPI provides a JSON response with the current referee occupancy.
 func (pc *PositionController) GetOccupancyAPI(c *gin.Context) {
 	session := sessions.Default(c)
-	meetNameRaw := session.Get("meetName")
-	meetName, ok := meetNameRaw.(string)
+	meetIDRaw := session.Get("meetID")
+	meetID, ok := meetIDRaw.(string)
 
-	if !ok || meetName == "" {
+	if !ok || meetID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No meet selected"})
 		return
 	}
 
-	occ := pc.OccupancyService.GetOccupancy(meetName)
-	c.JSON(http.StatusOK, gin.H{
-		"leftUser":   occ.LeftUser,
-		"centreUser": occ.CenterUser, // spelled “centreUser” for the JSON response
-		"rightUser":  occ.RightUser,
-	})
+	occ := pc.OccupancyService.GetOccupancy(meetID, sessionPlatform(session))
+	c.JSON(http.StatusOK, gin.H{"seats": occ.Seats})
+}
+
+// ------------------- Panel helpers -------------------
+
+// seatView pairs a seat on the panel with its occupant, for the templates.
+type seatView struct {
+	Position models.Position
+	User     string
+}
+
+// panelSeats lists the seats in panel order with who holds each one.
+func panelSeats(positions []models.Position, occ services.Occupancy) []seatView {
+	seats := make([]seatView, 0, len(positions))
+	for _, p := range positions {
+		seats = append(seats, seatView{Position: p, User: occ.UserAt(p.ID)})
+	}
+	return seats
+}
+
+// PositionsFor returns the seats of a meet, or the default three-referee panel if the
+// meet cannot be found. The occupancy service validates claims against it.
+func PositionsFor(meetID string) []models.Position {
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		return models.DefaultPositions
+	}
+	return meet.PositionList()
+}
+
+// hasPosition reports whether position is a seat on the meet's panel.
+func hasPosition(meetID, position string) bool {
+	_, ok := findPosition(meetID, position)
+	return ok
+}
+
+// findPosition returns the seat with the given ID on the meet's panel.
+func findPosition(meetID, position string) (models.Position, bool) {
+	for _, p := range PositionsFor(meetID) {
+		if p.ID == position {
+			return p, true
+		}
+	}
+	return models.Position{}, false
+}
+
+// claimPermission is the permission needed to claim a seat on the meet's panel. Seats
+// the panel does not have need judge; claiming them fails anyway.
+func claimPermission(meetID, position string) models.Permission {
+	if seat, ok := findPosition(meetID, position); ok {
+		return seat.ClaimPermission()
+	}
+	return models.PermJudge
+}
+
+// PanelFor describes a meet's panel to the websocket layer, which counts votes with it.
+func PanelFor(meetID string) (websocket.Panel, bool) {
+	meet, err := lookupMeet(meetID)
+	if err != nil {
+		return websocket.Panel{}, false
+	}
+	return meet.Panel(), true
+}
+
+// seatPath is the page a seated referee judges from.
+func seatPath(position string) string {
+	return "/seat/" + url.PathEscape(position)
 }
@@ -17,6 +17,8 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go-ref-lights/models"
 	"go-ref-lights/services"
 	"go-ref-lights/websocket"
 )
@@ -67,9 +69,9 @@ func TestClaimPosition_Success(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		mockOccupancyService.
-			On("SetPosition", "TestMeet", "left", "testuser").Return(nil).Once()
-		mockOccupancyService.On("GetOccupancy", "TestMeet").
-			Return(services.Occupancy{LeftUser: "testuser"}).Once()
+			On("SetPosition", "testmeet", models.DefaultPlatformID, "left", "testuser").Return(nil).Once()
+		mockOccupancyService.On("GetOccupancy", "testmeet", models.DefaultPlatformID).
+			Return(services.Occupancy{Seats: map[string]string{"left": "testuser"}}).Once()
 
 		c, _ := gin.CreateTestContext(w)
 		c.Request = req
@@ -78,7 +80,8 @@ func TestClaimPosition_Success(t *testing.T) {
 
 		session := sessions.Default(c)
 		session.Set("user", "testuser")
-		session.Set("meetName", "TestMeet")
+		session.Set("meetID", "testmeet")
+		session.Set("role", string(models.RoleReferee))
 		_ = session.Save()
 
 		router.ServeHTTP(w, req)
@@ -86,14 +89,73 @@ func TestClaimPosition_Success(t *testing.T) {
 
 		t.Log("Assertions after ClaimPosition execution")
 		assert.Equal(t, http.StatusFound, w.Code, "Should redirect after claiming position")
-		assert.Equal(t, "/left", w.Header().Get("Location"))
+		assert.Equal(t, "/seat/left", w.Header().Get("Location"))
 		time.Sleep(200 * time.Millisecond)
 
-		mockOccupancyService.AssertCalled(t, "GetOccupancy", "TestMeet")
+		mockOccupancyService.AssertCalled(t, "GetOccupancy", "testmeet", models.DefaultPlatformID)
 		mockOccupancyService.AssertExpectations(t)
 	})
 }
 
+// claimRouter serves ClaimPosition to a session with the given role in a meet with a jury seat.
+func claimRouter(t *testing.T, role models.Role) (*gin.Engine, *http.Cookie, *services.MockOccupancyService) {
+	websocket.InitTest()
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		ID:   "testmeet",
+		Name: "Test Meet",
+		Positions: []models.Position{
+			{ID: "left", Name: "Left Referee"},
+			{ID: "center", Name: "Center Referee", Chief: true},
+			{ID: "right", Name: "Right Referee"},
+			{ID: "jury", Name: "Jury", Kind: models.SeatJury},
+		},
+	}}})
+	occupancy := new(services.MockOccupancyService)
+	occupancy.On("GetOccupancy", mock.Anything, mock.Anything).Return(services.Occupancy{}).Maybe()
+	router := setupTestRouter(t)
+	router.POST("/position/claim", NewPositionController(occupancy).ClaimPosition)
+	cookie := SetSession(router, "/set-session", map[string]interface{}{
+		"user": "member", "meetID": "testmeet", "role": string(role),
+	})
+	return router, cookie, occupancy
+}
+
+func postClaim(router *gin.Engine, cookie *http.Cookie, position string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", "/position/claim", bytes.NewBufferString("position="+position))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestClaimPosition_JuryTakesJurySeat(t *testing.T) {
+	router, cookie, occupancy := claimRouter(t, models.RoleJury)
+	occupancy.On("SetPosition", "testmeet", models.DefaultPlatformID, "jury", "member").Return(nil).Once()
+
+	w := postClaim(router, cookie, "jury")
+	assert.Equal(t, http.StatusFound, w.Code, "jury accounts may sit in jury seats")
+	assert.Equal(t, "/seat/jury", w.Header().Get("Location"))
+	occupancy.AssertExpectations(t)
+}
+
+func TestClaimPosition_SeatKindNeedsItsPermission(t *testing.T) {
+	tests := []struct {
+		role     models.Role
+		position string
+		why      string
+	}{
+		{models.RoleJury, "left", "the jury does not vote"},
+		{models.RoleReferee, "jury", "referees vote from referee seats"},
+		{models.RoleViewer, "left", "viewers only watch"},
+	}
+	for _, tt := range tests {
+		router, cookie, occupancy := claimRouter(t, tt.role)
+		assert.Equal(t, http.StatusForbidden, postClaim(router, cookie, tt.position).Code, tt.why)
+		occupancy.AssertNotCalled(t, "SetPosition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
 // Test VacatePosition (Successful Vacate)
 func TestVacatePosition_Success(t *testing.T) {
 	websocket.InitTest()
@@ -104,8 +166,8 @@ func TestVacatePosition_Success(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/vacate-position", nil)
 	w := httptest.NewRecorder()
 
-	mockOccupancyService.On("UnsetPosition", "TestMeet", "left", "testuser").Return(nil).Once()
-	mockOccupancyService.On("GetOccupancy", "TestMeet").Return(services.Occupancy{}).Once()
+	mockOccupancyService.On("UnsetPosition", "testmeet", models.DefaultPlatformID, "left", "testuser").Return(nil).Once()
+	mockOccupancyService.On("GetOccupancy", "testmeet", models.DefaultPlatformID).Return(services.Occupancy{}).Once()
 
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
@@ -114,7 +176,7 @@ func TestVacatePosition_Success(t *testing.T) {
 
 	session := sessions.Default(c)
 	session.Set("user", "testuser")
-	session.Set("meetName", "TestMeet")
+	session.Set("meetID", "testmeet")
 	session.Set("refPosition", "left")
 	_ = session.Save()
 
@@ -127,7 +189,7 @@ func TestVacatePosition_Success(t *testing.T) {
 	assert.Equal(t, "/index", w.Header().Get("Location"))
 	time.Sleep(150 * time.Millisecond)
 
-	mockOccupancyService.AssertCalled(t, "GetOccupancy", "TestMeet") // Checks at least one call
+	mockOccupancyService.AssertCalled(t, "GetOccupancy", "testmeet", models.DefaultPlatformID) // Checks at least one call
 	mockOccupancyService.AssertExpectations(t)
 }
 
@@ -141,10 +203,8 @@ func TestGetOccupancyAPI_Success(t *testing.T) {
 	req, _ := http.NewRequest("GET", "/occupancy", nil)
 	w := httptest.NewRecorder()
 
-	mockOccupancyService.On("GetOccupancy", "TestMeet").Return(services.Occupancy{
-		LeftUser:   "user1",
-		CenterUser: "",
-		RightUser:  "user2",
+	mockOccupancyService.On("GetOccupancy", "testmeet", models.DefaultPlatformID).Return(services.Occupancy{
+		Seats: map[string]string{"left": "user1", "right": "user2"},
 	}).Once()
 
 	c, _ := gin.CreateTestContext(w)
@@ -153,17 +213,15 @@ func TestGetOccupancyAPI_Success(t *testing.T) {
 	sessions.Sessions("testsession", store)(c)
 
 	session := sessions.Default(c)
-	session.Set("meetName", "TestMeet")
+	session.Set("meetID", "testmeet")
 	_ = session.Save()
 
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response map[string]string
+	var response map[string]map[string]string
 	_ = json.Unmarshal(w.Body.Bytes(), &response)
-	assert.Contains(t, response, "leftUser")
-	assert.Contains(t, response, "centreUser")
-	assert.Contains(t, response, "rightUser")
+	assert.Equal(t, map[string]string{"left": "user1", "right": "user2"}, response["seats"])
 
 	time.Sleep(150 * time.Millisecond)
 	mockOccupancyService.AssertExpectations(t)
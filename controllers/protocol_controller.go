@@ -0,0 +1,35 @@
+// Package controllers serves the websocket protocol's JSON Schema to client authors.
+// File: controllers/protocol_controller.go
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
+)
+
+// ProtocolSchema serves the JSON Schema of the websocket protocol, for the version in
+// ?version= or the current one.
+func ProtocolSchema(c *gin.Context) {
+	version := protocol.Version
+	if v := c.Query("version"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || !protocol.Supported(n) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Unknown protocol version", "supported": protocol.SupportedVersions()})
+			return
+		}
+		version = n
+	}
+
+	schema, err := protocol.Schema(version)
+	if err != nil {
+		logger.Error.Printf("[ProtocolSchema] Failed to build schema for version %d: %v", version, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build schema"})
+		return
+	}
+	c.Data(http.StatusOK, "application/schema+json", schema)
+}
@@ -0,0 +1,42 @@
+// controllers/protocol_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocolSchema(t *testing.T) {
+	router := setupTestRouter(t)
+	router.GET("/protocol/schema.json", ProtocolSchema)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protocol/schema.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/schema+json", w.Header().Get("Content-Type"))
+
+	var schema struct {
+		ID   string                     `json:"$id"`
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.Equal(t, "reflights.v1", schema.ID)
+	assert.Contains(t, schema.Defs, "ClientMessage")
+	assert.Contains(t, schema.Defs, "ServerMessage")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protocol/schema.json?version=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protocol/schema.json?version=7", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
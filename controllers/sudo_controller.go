@@ -3,7 +3,10 @@ package controllers
 import (
 	"encoding/json"
 	"github.com/gin-gonic/gin"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/models"
+	"go-ref-lights/protocol"
 	"go-ref-lights/services"
 	"go-ref-lights/websocket"
 	"net/http"
@@ -12,12 +15,17 @@ import (
 // SudoController handles global "superuser" actions across meets.
 type SudoController struct {
 	OccupancyService services.OccupancyServiceInterface
+	MeetCredsPath    string // credentials file the meet editor saves
+	MeetsPath        string // meet picker listing the meet editor keeps in step
 }
 
-// NewSudoController constructs the controller, injecting needed services.
-func NewSudoController(svc services.OccupancyServiceInterface) *SudoController {
+// NewSudoController constructs the controller, injecting needed services and the
+// meet files it edits.
+func NewSudoController(svc services.OccupancyServiceInterface, cfg *config.Config) *SudoController {
 	return &SudoController{
 		OccupancyService: svc,
+		MeetCredsPath:    cfg.MeetCredsPath,
+		MeetsPath:        cfg.MeetsPath,
 	}
 }
 
@@ -28,13 +36,12 @@ func (sc *SudoController) SudoPanel(c *gin.Context) {
 	// user := sessions.Default(c).Get("user") // your superuser's name if needed
 	meetsData, _ := loadMeetCredsFunc()
 	var allOccupancies []map[string]interface{}
-	for _, meet := range meetsData.Meets {
-		occ := sc.OccupancyService.GetOccupancy(meet.Name)
+	for i := range meetsData.Meets {
+		meet := &meetsData.Meets[i]
 		allOccupancies = append(allOccupancies, map[string]interface{}{
-			"meetName":   meet.Name,
-			"leftUser":   occ.LeftUser,
-			"centerUser": occ.CenterUser,
-			"rightUser":  occ.RightUser,
+			"meetID":    meet.ID,
+			"meetName":  meet.Name,
+			"platforms": meetOccupancy(sc.OccupancyService, meet),
 		})
 	}
 
@@ -45,28 +52,21 @@ func (sc *SudoController) SudoPanel(c *gin.Context) {
 
 // ForceVacateRefForAnyMeet forcibly vacates a referee from some meet.
 func (sc *SudoController) ForceVacateRefForAnyMeet(c *gin.Context) {
-	meetName := c.PostForm("meetName")
+	meetID := c.PostForm("meetID")
+	platformID := c.DefaultPostForm("platformID", models.DefaultPlatformID)
 	position := c.PostForm("position")
 
 	// do minimal validation
-	if meetName == "" || position == "" {
-		c.String(http.StatusBadRequest, "Missing meetName or position")
+	if meetID == "" || position == "" {
+		c.String(http.StatusBadRequest, "Missing meetID or position")
 		return
 	}
 
-	occ := sc.OccupancyService.GetOccupancy(meetName)
-	var occupant string
-	switch position {
-	case "left":
-		occupant = occ.LeftUser
-	case "center":
-		occupant = occ.CenterUser
-	case "right":
-		occupant = occ.RightUser
-	default:
+	if !hasPosition(meetID, position) {
 		c.String(http.StatusBadRequest, "Invalid position")
 		return
 	}
+	occupant := sc.OccupancyService.GetOccupancy(meetID, platformID).UserAt(position)
 
 	if occupant == "" {
 		c.String(http.StatusBadRequest, "Position is already vacant")
@@ -74,7 +74,7 @@ func (sc *SudoController) ForceVacateRefForAnyMeet(c *gin.Context) {
 	}
 
 	// remove occupant from occupancy
-	if err := sc.OccupancyService.UnsetPosition(meetName, position, occupant); err != nil {
+	if err := sc.OccupancyService.UnsetPosition(meetID, platformID, position, occupant); err != nil {
 		c.String(http.StatusInternalServerError, "Error vacating position: "+err.Error())
 		return
 	}
@@ -85,9 +85,9 @@ func (sc *SudoController) ForceVacateRefForAnyMeet(c *gin.Context) {
 	ActiveUsersMu.Unlock()
 
 	// broadcast update
-	logger.Info.Printf("[ForceVacateRefForAnyMeet] Superuser forcibly removed %s from meet=%s pos=%s",
-		occupant, meetName, position)
-	go sc.broadcastOccupancy(meetName)
+	logger.Info.Printf("[ForceVacateRefForAnyMeet] Superuser forcibly removed %s from meet=%s platform=%s pos=%s",
+		occupant, meetID, platformID, position)
+	go sc.broadcastOccupancy(meetID, platformID)
 
 	// redirect or return success
 	c.Redirect(http.StatusFound, "/sudo")
@@ -120,17 +120,21 @@ func (sc *SudoController) ForceLogoutMeetDirector(c *gin.Context) {
 
 // RestartAndClearMeet forcibly resets an unhealthy meet instance
 func (sc *SudoController) RestartAndClearMeet(c *gin.Context) {
-	meetName := c.PostForm("meetName")
-	if meetName == "" {
-		c.String(http.StatusBadRequest, "meetName is required")
+	meetID := c.PostForm("meetID")
+	if meetID == "" {
+		c.String(http.StatusBadRequest, "meetID is required")
 		return
 	}
 
-	// 1) Clear the meet state from the unified state
-	websocket.ClearMeetState(meetName)
+	// 1) Clear the live state of every platform
+	if meet, err := lookupMeet(meetID); err == nil {
+		clearMeetStates(meet)
+	} else {
+		websocket.ClearMeetState(meetID)
+	}
 
 	// 2) Reset occupancy
-	sc.OccupancyService.ResetOccupancyForMeet(meetName)
+	sc.OccupancyService.ResetOccupancyForMeet(meetID)
 
 	// 3) Optionally remove all users from ActiveUsers who are in that meet
 	//    This is optional. If your logic needs to track which user belongs to which meet,
@@ -138,24 +142,20 @@ func (sc *SudoController) RestartAndClearMeet(c *gin.Context) {
 	//      (You might not actually track each user’s meet, so do what fits your design)
 	//
 	//    for userName := range ActiveUsers {
-	//      // if userName is a ref or admin of meetName => forcibly remove
+	//      // if userName is a ref or admin of meetID => forcibly remove
 	//    }
 
-	logger.Info.Printf("[RestartAndClearMeet] Superuser forcibly reset meet: %s", meetName)
+	logger.Info.Printf("[RestartAndClearMeet] Superuser forcibly reset meet: %s", meetID)
 	c.Redirect(http.StatusFound, "/sudo")
 }
 
 // broadcastOccupancy is just a re-use of your existing logic from PositionController
-func (sc *SudoController) broadcastOccupancy(meetName string) {
-	occ := sc.OccupancyService.GetOccupancy(meetName)
-	msg := map[string]interface{}{
-		"action":     "occupancyChanged",
-		"leftUser":   occ.LeftUser,
-		"centerUser": occ.CenterUser,
-		"rightUser":  occ.RightUser,
-		"meetName":   meetName,
-	}
-	websocket.SendBroadcastMessage(mustMarshal(msg))
+func (sc *SudoController) broadcastOccupancy(meetID, platformID string) {
+	occ := sc.OccupancyService.GetOccupancy(meetID, platformID)
+	header := protocol.NewHeader(protocol.ActionOccupancyChanged, meetID)
+	header.Platform = platformID
+	msg := protocol.OccupancyChanged{Header: header, Seats: occ.Seats}
+	websocket.SendBroadcastMessage(models.PlatformKey(meetID, platformID), mustMarshal(msg))
 }
 
 // mustMarshal is a tiny helper
@@ -0,0 +1,436 @@
+// Package controllers provide HTTP handlers for various admin operations.
+// File: controllers/sudo_meets_controller.go
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/models"
+	"go-ref-lights/services"
+	"go-ref-lights/storage"
+)
+
+// minPasswordLength keeps volunteers from setting trivially guessable passwords.
+const minPasswordLength = 8
+
+// hashPasswordFunc allows dependency injection for testing (cost 12 is slow).
+var hashPasswordFunc = hashMeetPassword
+
+// hashMeetPassword bcrypt-hashes a new account password.
+func hashMeetPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), services.PasswordHashCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// ---------------- meet listing ----------------
+
+// meetListingEntry is one meet in the meets file, which only feeds the meet picker.
+// Entries written before meets had IDs have only a name.
+type meetListingEntry struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+// is reports whether the entry lists the given meet. A name-only entry still matches
+// after a rename, since the meet's ID was derived from the name the entry has.
+func (e meetListingEntry) is(meet *models.Meet) bool {
+	if e.ID != "" {
+		return e.ID == meet.ID
+	}
+	name := strings.TrimSpace(e.Name)
+	return name == meet.Name || models.Slugify(name) == meet.ID
+}
+
+// updateMeetListing rewrites the meets file atomically. Entries the editor does not
+// manage (such as "Sudo") are passed through untouched. Callers hold meetCredsMu.
+func (sc *SudoController) updateMeetListing(change func(entries []meetListingEntry) []meetListingEntry) error {
+	var listing struct {
+		Meets []meetListingEntry `json:"meets"`
+	}
+	data, err := os.ReadFile(sc.MeetsPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read meets file: %w", err)
+	default:
+		if err := json.Unmarshal(data, &listing); err != nil {
+			return fmt.Errorf("failed to parse meets file: %w", err)
+		}
+	}
+
+	listing.Meets = change(listing.Meets)
+	data, err = json.MarshalIndent(listing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode meets file: %w", err)
+	}
+	if err := storage.WriteFileAtomic(sc.MeetsPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write meets file: %w", err)
+	}
+	return nil
+}
+
+// listMeet adds or refreshes a meet in the picker.
+func (sc *SudoController) listMeet(meet *models.Meet) error {
+	entry := meetListingEntry{ID: meet.ID, Name: meet.Name, Date: meet.DateLabel()}
+	return sc.updateMeetListing(func(entries []meetListingEntry) []meetListingEntry {
+		for i := range entries {
+			if entries[i].is(meet) {
+				entries[i] = entry
+				return entries
+			}
+		}
+		return append(entries, entry)
+	})
+}
+
+// unlistMeet removes a meet from the picker.
+func (sc *SudoController) unlistMeet(meet *models.Meet) error {
+	return sc.updateMeetListing(func(entries []meetListingEntry) []meetListingEntry {
+		kept := entries[:0]
+		for _, e := range entries {
+			if !e.is(meet) {
+				kept = append(kept, e)
+			}
+		}
+		return kept
+	})
+}
+
+// uniqueMeetID derives an ID for a new meet from its name, numbering it if another
+// meet already has it ("cairns-cup-2").
+func uniqueMeetID(creds *models.MeetCreds, name string) string {
+	base := models.Slugify(name)
+	taken := func(id string) bool {
+		for _, m := range creds.Meets {
+			if m.ID == id || m.Slug == id {
+				return true
+			}
+		}
+		return false
+	}
+	id := base
+	for n := 2; taken(id); n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+// scheduleFromForm reads the dates from a meet form. An empty start date leaves
+// the meet on its free-text date; the session plan survives date changes.
+func scheduleFromForm(c *gin.Context, current *models.Schedule) *models.Schedule {
+	start := strings.TrimSpace(c.PostForm("startDate"))
+	if start == "" {
+		return nil
+	}
+	schedule := &models.Schedule{}
+	if current != nil {
+		schedule = current.Clone()
+	}
+	schedule.TimeZone = strings.TrimSpace(c.PostForm("timeZone"))
+	schedule.StartDate = start
+	schedule.EndDate = strings.TrimSpace(c.PostForm("endDate"))
+	return schedule
+}
+
+// ---------------- meet management ----------------
+
+// MeetsPage lists every meet, archived ones included, with forms to edit them.
+func (sc *SudoController) MeetsPage(c *gin.Context) {
+	creds, err := loadMeetCredsFunc()
+	if err != nil {
+		logger.Error.Printf("[MeetsPage] Failed to load meet credentials: %v", err)
+		c.String(http.StatusInternalServerError, "Failed to load meet configuration")
+		return
+	}
+	c.HTML(http.StatusOK, "sudo_meets.html", gin.H{
+		"meets":   creds.Meets,
+		"roles":   []models.Role{models.RoleMeetDirector, models.RoleTechnicalController, models.RoleReferee, models.RoleJury, models.RoleAnnouncer, models.RoleViewer},
+		"message": c.Query("message"),
+		"error":   c.Query("error"),
+	})
+}
+
+// CreateMeet adds a meet with its primary admin and lists it in the meet picker. The
+// meet gets its ID and slug here, from the name, and keeps them for good.
+func (sc *SudoController) CreateMeet(c *gin.Context) {
+	name := strings.TrimSpace(c.PostForm("name"))
+	username := strings.TrimSpace(c.PostForm("adminUsername"))
+	hash, err := hashPasswordFunc(c.PostForm("adminPassword"))
+	if err != nil {
+		redirectMeets(c, "", err.Error())
+		return
+	}
+
+	meet := models.Meet{
+		Name:     name,
+		Date:     strings.TrimSpace(c.PostForm("date")),
+		Schedule: scheduleFromForm(c, nil),
+		Logo:     strings.TrimSpace(c.PostForm("logo")),
+		Admin:    models.Admin{Username: username, Password: hash, IsAdmin: true, Role: models.RoleMeetDirector},
+	}
+	if name == services.SudoMeetName {
+		redirectMeets(c, "", fmt.Sprintf("'%s' is reserved for the superuser", name))
+		return
+	}
+	if err := meet.Validate(); err != nil {
+		redirectMeets(c, "", err.Error())
+		return
+	}
+
+	sc.editCreds(c, "CreateMeet", "Created meet "+name, func(creds *models.MeetCreds) error {
+		creds.AssignIdentities()
+		for _, m := range creds.Meets {
+			if m.Name == name {
+				return fmt.Errorf("a meet named '%s' already exists", name)
+			}
+		}
+		meet.ID = uniqueMeetID(creds, name)
+		meet.Slug = meet.ID
+		creds.Meets = append(creds.Meets, meet)
+		return nil
+	}, func() error { return sc.listMeet(&meet) })
+}
+
+// UpdateMeet edits a meet's name, dates, logo and primary admin username. Live state,
+// sessions and referee links use the meet's ID and slug, so a rename is safe mid-meet.
+func (sc *SudoController) UpdateMeet(c *gin.Context) {
+	id := c.PostForm("meetID")
+	var updated models.Meet
+	sc.editCreds(c, "UpdateMeet", "Saved meet "+id, func(creds *models.MeetCreds) error {
+		meet := findMeet(creds, id)
+		if meet == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		if name := strings.TrimSpace(c.PostForm("name")); name != "" && name != meet.Name {
+			if name == services.SudoMeetName {
+				return fmt.Errorf("'%s' is reserved for the superuser", name)
+			}
+			for _, m := range creds.Meets {
+				if m.Name == name {
+					return fmt.Errorf("a meet named '%s' already exists", name)
+				}
+			}
+			meet.Name = name
+		}
+		meet.Date = strings.TrimSpace(c.PostForm("date"))
+		meet.Schedule = scheduleFromForm(c, meet.Schedule)
+		meet.Logo = strings.TrimSpace(c.PostForm("logo"))
+		if username := strings.TrimSpace(c.PostForm("adminUsername")); username != "" {
+			meet.Admin.Username = username
+		}
+		updated = *meet
+		return meet.Validate()
+	}, func() error {
+		if updated.Archived {
+			return nil
+		}
+		return sc.listMeet(&updated)
+	})
+}
+
+// ArchiveMeet hides a meet from selection and closes it to logins, or restores it.
+func (sc *SudoController) ArchiveMeet(c *gin.Context) {
+	id := c.PostForm("meetID")
+	archive := c.PostForm("archived") != "false"
+	var meet models.Meet
+	verb := "Archived"
+	if !archive {
+		verb = "Restored"
+	}
+	sc.editCreds(c, "ArchiveMeet", verb+" meet "+id, func(creds *models.MeetCreds) error {
+		found := findMeet(creds, id)
+		if found == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		found.Archived = archive
+		meet = *found
+		return nil
+	}, func() error {
+		if archive {
+			return sc.unlistMeet(&meet)
+		}
+		return sc.listMeet(&meet)
+	})
+}
+
+// DeleteMeet removes a meet for good, along with its seats and live state. The
+// superuser confirms by typing the meet's display name.
+func (sc *SudoController) DeleteMeet(c *gin.Context) {
+	id := c.PostForm("meetID")
+	var deleted models.Meet
+	sc.editCreds(c, "DeleteMeet", "Deleted meet "+id, func(creds *models.MeetCreds) error {
+		meet := findMeet(creds, id)
+		if meet == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		if c.PostForm("confirm") != meet.Name {
+			return errors.New("Type the meet name to confirm deletion")
+		}
+		deleted = *meet
+		for i := range creds.Meets {
+			if creds.Meets[i].ID == id {
+				creds.Meets = append(creds.Meets[:i], creds.Meets[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}, func() error {
+		sc.OccupancyService.ResetOccupancyForMeet(deleted.ID)
+		clearMeetStates(&deleted)
+		return sc.unlistMeet(&deleted)
+	})
+}
+
+// ---------------- account management ----------------
+
+// SaveMeetAccount adds a secondary account, or changes an existing account's role.
+// A password is required for new accounts and optional for existing ones. The primary
+// admin may only be given roles that keep the admin panel.
+func (sc *SudoController) SaveMeetAccount(c *gin.Context) {
+	id := c.PostForm("meetID")
+	username := strings.TrimSpace(c.PostForm("username"))
+	role := models.Role(c.PostForm("role"))
+	password := c.PostForm("password")
+
+	var hash string
+	if password != "" {
+		var err error
+		if hash, err = hashPasswordFunc(password); err != nil {
+			redirectMeets(c, "", err.Error())
+			return
+		}
+	}
+
+	sc.editCreds(c, "SaveMeetAccount", "Saved account "+username, func(creds *models.MeetCreds) error {
+		meet := findMeet(creds, id)
+		if meet == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		account := meet.Account(username)
+		if account == &meet.Admin && !role.Can(models.PermViewAdminPanel) {
+			// demoting the primary admin would lock the meet director out of their own meet
+			return fmt.Errorf("the primary admin '%s' must keep a role with admin panel access", username)
+		}
+		if account == nil {
+			if hash == "" {
+				return fmt.Errorf("a password is required for new account '%s'", username)
+			}
+			meet.SecondaryAdmins = append(meet.SecondaryAdmins, models.Admin{Username: username})
+			account = &meet.SecondaryAdmins[len(meet.SecondaryAdmins)-1]
+		}
+		account.Role = role
+		account.IsAdmin = role.Can(models.PermViewAdminPanel)
+		if hash != "" {
+			account.Password = hash
+		}
+		return meet.Validate()
+	}, nil)
+}
+
+// ResetMeetPassword sets a new password for any account of a meet.
+func (sc *SudoController) ResetMeetPassword(c *gin.Context) {
+	id := c.PostForm("meetID")
+	username := c.PostForm("username")
+	hash, err := hashPasswordFunc(c.PostForm("password"))
+	if err != nil {
+		redirectMeets(c, "", err.Error())
+		return
+	}
+	sc.editCreds(c, "ResetMeetPassword", "Reset password for "+username, func(creds *models.MeetCreds) error {
+		meet := findMeet(creds, id)
+		if meet == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		account := meet.Account(username)
+		if account == nil {
+			return fmt.Errorf("meet '%s' has no account '%s'", meet.Name, username)
+		}
+		account.Password = hash
+		return nil
+	}, nil)
+}
+
+// DeleteMeetAccount removes a secondary account from a meet.
+func (sc *SudoController) DeleteMeetAccount(c *gin.Context) {
+	id := c.PostForm("meetID")
+	username := c.PostForm("username")
+	sc.editCreds(c, "DeleteMeetAccount", "Removed account "+username, func(creds *models.MeetCreds) error {
+		meet := findMeet(creds, id)
+		if meet == nil {
+			return fmt.Errorf("no meet with id '%s'", id)
+		}
+		if !meet.RemoveAccount(username) {
+			return fmt.Errorf("meet '%s' has no secondary account '%s'", meet.Name, username)
+		}
+		return nil
+	}, nil)
+}
+
+// ---------------- helpers ----------------
+
+// editCreds applies change to the credentials, saves them, then runs after (which
+// keeps the meet picker in step) and redirects back to the meets page with the outcome.
+func (sc *SudoController) editCreds(c *gin.Context, handler, success string, change func(creds *models.MeetCreds) error, after func() error) {
+	meetCredsMu.Lock()
+	defer meetCredsMu.Unlock()
+
+	creds, err := loadMeetCredsFunc()
+	if err != nil {
+		logger.Error.Printf("[%s] Failed to load meet credentials: %v", handler, err)
+		redirectMeets(c, "", "Failed to load meet configuration")
+		return
+	}
+	if err := change(creds); err != nil {
+		redirectMeets(c, "", err.Error())
+		return
+	}
+	if err := saveMeetCredsFunc(sc.MeetCredsPath, creds); err != nil {
+		logger.Error.Printf("[%s] Failed to save meet credentials: %v", handler, err)
+		redirectMeets(c, "", "Failed to save meet configuration")
+		return
+	}
+	if after != nil {
+		if err := after(); err != nil {
+			logger.Error.Printf("[%s] Saved credentials but failed to update the meet list: %v", handler, err)
+			redirectMeets(c, "", success+", but the meet list could not be updated")
+			return
+		}
+	}
+
+	reloadMeetRegistry(handler)
+	logger.Info.Printf("[%s] %s", handler, success)
+	redirectMeets(c, success, "")
+}
+
+// redirectMeets returns to the meets page with a flash message or error.
+func redirectMeets(c *gin.Context, message, errMsg string) {
+	q := url.Values{}
+	if message != "" {
+		q.Set("message", message)
+	}
+	if errMsg != "" {
+		q.Set("error", errMsg)
+	}
+	target := "/sudo/meets"
+	if len(q) > 0 {
+		target += "?" + q.Encode()
+	}
+	c.Redirect(http.StatusFound, target)
+}
@@ -0,0 +1,277 @@
+// controllers/sudo_meets_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/config"
+	"go-ref-lights/models"
+	"go-ref-lights/services"
+	"go-ref-lights/websocket"
+)
+
+// sudoMeetsFixture wires the meet editor to in-memory creds and a temporary meets file.
+type sudoMeetsFixture struct {
+	router    *gin.Engine
+	creds     *models.MeetCreds
+	meetsPath string
+	occupancy *MockOccupancyService
+}
+
+func newSudoMeetsFixture(t *testing.T, creds *models.MeetCreds, listing string) *sudoMeetsFixture {
+	websocket.InitTest()
+	stubMeetCreds(t, creds)
+
+	meetsPath := filepath.Join(t.TempDir(), "meets.json")
+	require.NoError(t, os.WriteFile(meetsPath, []byte(listing), 0644))
+
+	origHash := hashPasswordFunc
+	hashPasswordFunc = func(password string) (string, error) {
+		if len(password) < minPasswordLength {
+			return hashMeetPassword(password) // for the error
+		}
+		return "hashed:" + password, nil
+	}
+	t.Cleanup(func() { hashPasswordFunc = origHash })
+
+	occupancy := new(MockOccupancyService)
+	cfg := config.Default()
+	cfg.MeetsPath = meetsPath
+	sc := NewSudoController(occupancy, cfg)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(sessions.Sessions("testsession", cookie.NewStore([]byte("test-secret"))))
+	router.LoadHTMLFiles(filepath.Join("..", "templates", "sudo_meets.html"))
+	router.GET("/sudo/meets", sc.MeetsPage)
+	router.POST("/sudo/meets", sc.CreateMeet)
+	router.POST("/sudo/meets/update", sc.UpdateMeet)
+	router.POST("/sudo/meets/archive", sc.ArchiveMeet)
+	router.POST("/sudo/meets/delete", sc.DeleteMeet)
+	router.POST("/sudo/meets/accounts", sc.SaveMeetAccount)
+	router.POST("/sudo/meets/accounts/delete", sc.DeleteMeetAccount)
+	router.POST("/sudo/meets/password", sc.ResetMeetPassword)
+
+	return &sudoMeetsFixture{router: router, creds: creds, meetsPath: meetsPath, occupancy: occupancy}
+}
+
+// listed returns the names in the meets file.
+func (f *sudoMeetsFixture) listed(t *testing.T) []string {
+	data, err := os.ReadFile(f.meetsPath)
+	require.NoError(t, err)
+	var listing struct {
+		Meets []meetListingEntry `json:"meets"`
+	}
+	require.NoError(t, json.Unmarshal(data, &listing))
+	var names []string
+	for _, e := range listing.Meets {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// post submits a form and returns the flash error from the redirect, if any.
+func (f *sudoMeetsFixture) post(t *testing.T, path string, form url.Values) string {
+	w := postForm(f.router, nil, path, form)
+	require.Equal(t, http.StatusFound, w.Code)
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "/sudo/meets", loc.Path)
+	return loc.Query().Get("error")
+}
+
+func TestSudoMeets_CreateArchiveRestoreDelete(t *testing.T) {
+	f := newSudoMeetsFixture(t, &models.MeetCreds{}, `{"meets":[{"name":"Sudo","date":"N/A"}]}`)
+
+	assert.Empty(t, f.post(t, "/sudo/meets", url.Values{
+		"name": {"Spring Open"}, "date": {"April 5"}, "logo": {"static/images/spring.png"},
+		"adminUsername": {"spring"}, "adminPassword": {"correct-horse"},
+	}))
+	require.Len(t, f.creds.Meets, 1)
+	meet := f.creds.Meets[0]
+	assert.Equal(t, "spring-open", meet.ID)
+	assert.Equal(t, "spring-open", meet.Slug)
+	assert.Equal(t, "April 5", meet.Date)
+	assert.Equal(t, "hashed:correct-horse", meet.Admin.Password)
+	assert.Equal(t, models.RoleMeetDirector, meet.Admin.AccountRole())
+	assert.Equal(t, []string{"Sudo", "Spring Open"}, f.listed(t))
+
+	assert.Contains(t, f.post(t, "/sudo/meets", url.Values{
+		"name": {"Spring Open"}, "adminUsername": {"other"}, "adminPassword": {"correct-horse"},
+	}), "already exists")
+	assert.Contains(t, f.post(t, "/sudo/meets", url.Values{
+		"name": {"Short"}, "adminUsername": {"short"}, "adminPassword": {"short"},
+	}), "at least 8 characters")
+
+	// a different name with the same slug gets a numbered ID
+	assert.Empty(t, f.post(t, "/sudo/meets", url.Values{
+		"name": {"Spring Open!"}, "adminUsername": {"spring2"}, "adminPassword": {"correct-horse"},
+	}))
+	require.Len(t, f.creds.Meets, 2)
+	assert.Equal(t, "spring-open-2", f.creds.Meets[1].ID)
+	f.occupancy.On("ResetOccupancyForMeet", "spring-open-2").Return().Once()
+	assert.Empty(t, f.post(t, "/sudo/meets/delete", url.Values{"meetID": {"spring-open-2"}, "confirm": {"Spring Open!"}}))
+
+	assert.Empty(t, f.post(t, "/sudo/meets/archive", url.Values{"meetID": {"spring-open"}, "archived": {"true"}}))
+	assert.True(t, f.creds.Meets[0].Archived)
+	assert.Equal(t, []string{"Sudo"}, f.listed(t), "archived meets leave the picker")
+
+	assert.Empty(t, f.post(t, "/sudo/meets/archive", url.Values{"meetID": {"spring-open"}, "archived": {"false"}}))
+	assert.False(t, f.creds.Meets[0].Archived)
+	assert.Equal(t, []string{"Sudo", "Spring Open"}, f.listed(t))
+
+	assert.Contains(t, f.post(t, "/sudo/meets/delete", url.Values{"meetID": {"spring-open"}, "confirm": {"spring"}}),
+		"confirm")
+	require.Len(t, f.creds.Meets, 1)
+
+	f.occupancy.On("ResetOccupancyForMeet", "spring-open").Return().Once()
+	assert.Empty(t, f.post(t, "/sudo/meets/delete", url.Values{"meetID": {"spring-open"}, "confirm": {"Spring Open"}}))
+	assert.Empty(t, f.creds.Meets)
+	assert.Equal(t, []string{"Sudo"}, f.listed(t))
+	f.occupancy.AssertExpectations(t)
+}
+
+func TestSudoMeets_UpdateRenamesAndRelists(t *testing.T) {
+	f := newSudoMeetsFixture(t, &models.MeetCreds{Meets: []models.Meet{
+		{Name: "Cairns Cup", Date: "March 22", Admin: models.Admin{Username: "cairns", Password: "x", IsAdmin: true}},
+		{Name: "Dragon Cup", Admin: models.Admin{Username: "dragon", Password: "x", IsAdmin: true}},
+	}}, `{"meets":[{"name":"Cairns Cup ","date":"March 22"}]}`)
+
+	assert.Empty(t, f.post(t, "/sudo/meets/update", url.Values{
+		"meetID": {"cairns-cup"}, "name": {"Cairns Cup 2025"}, "date": {"March 29"},
+		"logo": {"static/images/cairns.png"}, "adminUsername": {"cairns_md"},
+	}))
+	meet := f.creds.Meets[0]
+	assert.Equal(t, "Cairns Cup 2025", meet.Name)
+	assert.Equal(t, "cairns-cup", meet.ID, "renaming keeps the ID")
+	assert.Equal(t, "cairns-cup", meet.Slug, "and the referee links")
+	assert.Equal(t, "March 29", meet.Date)
+	assert.Equal(t, "static/images/cairns.png", meet.Logo)
+	assert.Equal(t, "cairns_md", meet.Admin.Username)
+	assert.Equal(t, "x", meet.Admin.Password, "editing a meet leaves passwords alone")
+	assert.Equal(t, []string{"Cairns Cup 2025"}, f.listed(t), "the padded legacy entry is updated, not duplicated")
+
+	assert.Contains(t, f.post(t, "/sudo/meets/update", url.Values{"meetID": {"cairns-cup"}, "name": {"Dragon Cup"}}),
+		"already exists")
+	assert.Contains(t, f.post(t, "/sudo/meets/update", url.Values{"meetID": {"nope"}}), "no meet with id")
+}
+
+func TestSudoMeets_UpdateSetsScheduleAndKeepsSessions(t *testing.T) {
+	sessions := []models.MeetDay{{Date: "2025-03-22", Sessions: []models.Session{{Name: "Session 1", Start: "09:00"}}}}
+	f := newSudoMeetsFixture(t, &models.MeetCreds{Meets: []models.Meet{
+		{Name: "Cairns Cup", Admin: models.Admin{Username: "cairns", Password: "x", IsAdmin: true},
+			Schedule: &models.Schedule{TimeZone: "Australia/Brisbane", StartDate: "2025-03-22", Days: sessions}},
+	}}, `{"meets":[{"name":"Cairns Cup"}]}`)
+
+	assert.Empty(t, f.post(t, "/sudo/meets/update", url.Values{
+		"meetID": {"cairns-cup"}, "startDate": {"2025-03-22"}, "endDate": {"2025-03-23"}, "timeZone": {"Australia/Brisbane"},
+	}))
+	meet := f.creds.Meets[0]
+	require.NotNil(t, meet.Schedule)
+	assert.Equal(t, "2025-03-23", meet.Schedule.EndDate)
+	assert.Equal(t, sessions, meet.Schedule.Days, "the session plan is kept")
+
+	assert.Contains(t, f.post(t, "/sudo/meets/update", url.Values{
+		"meetID": {"cairns-cup"}, "startDate": {"2025-03-22"}, "timeZone": {"Queensland"},
+	}), "unknown time zone")
+}
+
+func TestSudoMeets_Accounts(t *testing.T) {
+	f := newSudoMeetsFixture(t, &models.MeetCreds{Meets: []models.Meet{
+		{Name: "Cairns Cup", Admin: models.Admin{Username: "cairns", Password: "old", IsAdmin: true}},
+	}}, `{"meets":[]}`)
+
+	assert.Contains(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"tc"}, "role": {"technical_controller"},
+	}), "password is required")
+
+	assert.Empty(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"tc"}, "role": {"technical_controller"}, "password": {"platform-one"},
+	}))
+	meet := &f.creds.Meets[0]
+	require.NotNil(t, meet.Account("tc"))
+	assert.Equal(t, models.RoleTechnicalController, meet.Account("tc").AccountRole())
+	assert.True(t, meet.Account("tc").IsAdmin)
+	assert.Equal(t, "hashed:platform-one", meet.Account("tc").Password)
+
+	// change role only; the password stays
+	assert.Empty(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"tc"}, "role": {"announcer"},
+	}))
+	meet = &f.creds.Meets[0]
+	assert.Equal(t, models.RoleAnnouncer, meet.Account("tc").Role)
+	assert.False(t, meet.Account("tc").IsAdmin)
+	assert.Equal(t, "hashed:platform-one", meet.Account("tc").Password)
+
+	assert.Contains(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"boss"}, "role": {"superuser"}, "password": {"long-enough"},
+	}), "invalid role")
+
+	// the primary admin keeps admin panel access
+	assert.Contains(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"cairns"}, "role": {"viewer"},
+	}), "must keep a role with admin panel access")
+	assert.True(t, f.creds.Meets[0].Admin.IsAdmin)
+	assert.Empty(t, f.post(t, "/sudo/meets/accounts", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"cairns"}, "role": {"technical_controller"},
+	}))
+	assert.Equal(t, models.RoleTechnicalController, f.creds.Meets[0].Admin.Role)
+	assert.True(t, f.creds.Meets[0].Admin.IsAdmin)
+
+	assert.Empty(t, f.post(t, "/sudo/meets/password", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"cairns"}, "password": {"new-password"},
+	}))
+	assert.Equal(t, "hashed:new-password", f.creds.Meets[0].Admin.Password)
+	assert.Contains(t, f.post(t, "/sudo/meets/password", url.Values{
+		"meetID": {"cairns-cup"}, "username": {"ghost"}, "password": {"new-password"},
+	}), "no account")
+
+	assert.Contains(t, f.post(t, "/sudo/meets/accounts/delete", url.Values{"meetID": {"cairns-cup"}, "username": {"cairns"}}),
+		"no secondary account", "the primary admin cannot be removed")
+	assert.Empty(t, f.post(t, "/sudo/meets/accounts/delete", url.Values{"meetID": {"cairns-cup"}, "username": {"tc"}}))
+	assert.Nil(t, f.creds.Meets[0].Account("tc"))
+}
+
+func TestSudoMeets_PageRenders(t *testing.T) {
+	f := newSudoMeetsFixture(t, &models.MeetCreds{Meets: []models.Meet{
+		{Name: "Cairns Cup", Date: "March 22", Admin: models.Admin{Username: "cairns", IsAdmin: true},
+			SecondaryAdmins: []models.Admin{{Username: "tc", Role: models.RoleTechnicalController}}},
+		{Name: "Old Meet", Admin: models.Admin{Username: "old"}, Archived: true},
+	}}, `{"meets":[]}`)
+
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sudo/meets?error=Oops", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "Cairns Cup")
+	assert.Contains(t, body, "technical_controller")
+	assert.Contains(t, body, "Old Meet (archived)")
+	assert.Contains(t, body, "Oops")
+}
+
+func TestHashMeetPassword(t *testing.T) {
+	hash, err := hashMeetPassword("correct-horse")
+	require.NoError(t, err)
+	assert.True(t, services.ValidPasswordHash(hash), "server-side hashes must pass LoadMeetCreds")
+	assert.True(t, checkPasswordHash("correct-horse", hash))
+
+	_, err = hashMeetPassword("short")
+	assert.Error(t, err)
+
+	assert.True(t, services.ValidPasswordHash("$2b$12$KFKBzEMcKJhuGh6Q7R/GZOBBS4S6EoAWxrML2jv1Zl9Fwf0P0ylVC"))
+	assert.False(t, services.ValidPasswordHash(hashPassword("cheap")), "cost 10 is below the minimum")
+	assert.False(t, services.ValidPasswordHash("plaintext"))
+}
@@ -47,9 +47,9 @@ func createDummyTemplates(dir string) error {
 		"login.html":       `<html><body>{{.}}</body></html>`,
 		"positions.html":   `<html><body>{{.}}</body></html>`,
 		"index.html":       `<html><body>{{.}}</body></html>`,
-		"left.html":        `<html><body>Left ref view for {{.meetName}}</body></html>`,
-		"center.html":      `<html><body>Center ref view for {{.meetName}}</body></html>`,
-		"right.html":       `<html><body>Right ref view for {{.meetName}}</body></html>`,
+		"seat.html":        `<html><body>{{.positionName}} view for {{.meetName}}</body></html>`,
+		"history.html":     `<html><body>History for {{.meetName}}</body></html>`,
+		"lights.html":      `<html><body>Lights for {{.meetName}}{{with .activeSession}} ({{.Session}}){{end}}</body></html>`,
 	}
 
 	for name, content := range templates {
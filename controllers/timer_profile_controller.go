@@ -0,0 +1,229 @@
+// Package controllers provide HTTP handlers for various admin operations.
+// File: controllers/timer_profile_controller.go
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
+	"go-ref-lights/services"
+	"go-ref-lights/storage"
+	"go-ref-lights/websocket"
+)
+
+// meetCredsMu serialises read-modify-write cycles on the credentials file.
+var meetCredsMu sync.Mutex
+
+// saveMeetCredsFunc allows dependency injection for testing.
+var saveMeetCredsFunc = SaveMeetCreds
+
+// SaveMeetCreds writes the credentials back to the JSON file at path atomically,
+// so a crash mid-write never leaves a truncated file behind.
+func SaveMeetCreds(path string, creds *models.MeetCreds) error {
+	data, err := json.MarshalIndent(creds, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode meet credentials: %w", err)
+	}
+	if err := storage.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write meet credentials file: %w", err)
+	}
+	return nil
+}
+
+// TimerProfileFor returns the active timer profile of a meet for the websocket package.
+// Meets without an active profile use the configured default clocks.
+func TimerProfileFor(meetID string) (websocket.TimerProfile, bool) {
+	meet, err := lookupMeet(meetID)
+	if errors.Is(err, services.ErrMeetNotFound) {
+		return websocket.TimerProfile{}, false
+	}
+	if err != nil {
+		logger.Warn.Printf("[TimerProfileFor] Using default clocks for meet=%s: %v", meetID, err)
+		return websocket.TimerProfile{}, false
+	}
+	profile, ok := meet.ActiveProfile()
+	if !ok {
+		return websocket.TimerProfile{}, false
+	}
+	return websocket.TimerProfile{
+		PlatformReady:      time.Duration(profile.PlatformReadySeconds) * time.Second,
+		NextAttempt:        time.Duration(profile.NextAttemptSeconds) * time.Second,
+		ConsecutiveAttempt: time.Duration(profile.ConsecutiveAttemptSeconds) * time.Second,
+		ResultsDisplay:     time.Duration(profile.ResultsDisplaySeconds) * time.Second,
+	}, true
+}
+
+// findMeet returns a pointer into creds for the meet with the given ID, or nil.
+// Meets read from a file that predates IDs are given theirs first, so the next
+// save writes them out.
+func findMeet(creds *models.MeetCreds, meetID string) *models.Meet {
+	creds.AssignIdentities()
+	for i := range creds.Meets {
+		if creds.Meets[i].ID == meetID {
+			return &creds.Meets[i]
+		}
+	}
+	return nil
+}
+
+// ---------------- timer profile management ----------------
+
+// SaveTimerProfile adds or replaces a timer profile for the admin's meet and, when
+// `activate` is set, makes it the active one. Clocks already running are not changed.
+func (ac *AdminController) SaveTimerProfile(c *gin.Context) {
+	meetID, ok := adminMeet(c, "SaveTimerProfile", models.PermManageTimerProfiles)
+	if !ok {
+		return
+	}
+
+	profile, err := timerProfileFromForm(c)
+	if err == nil {
+		err = profile.Validate()
+	}
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid timer profile: "+err.Error())
+		return
+	}
+
+	activate := c.PostForm("activate") != ""
+	ac.updateMeet(c, meetID, "SaveTimerProfile", func(meet *models.Meet) error {
+		meet.SetTimerProfile(profile)
+		if activate {
+			meet.ActiveTimerProfile = profile.Name
+		}
+		return nil
+	})
+}
+
+// ActivateTimerProfile switches the admin's meet to the named profile.
+// An empty name reverts the meet to the default clocks.
+func (ac *AdminController) ActivateTimerProfile(c *gin.Context) {
+	meetID, ok := adminMeet(c, "ActivateTimerProfile", models.PermManageTimerProfiles)
+	if !ok {
+		return
+	}
+
+	name := c.PostForm("name")
+	ac.updateMeet(c, meetID, "ActivateTimerProfile", func(meet *models.Meet) error {
+		if name != "" {
+			if _, exists := findTimerProfile(meet, name); !exists {
+				return fmt.Errorf("no timer profile named %q", name)
+			}
+		}
+		meet.ActiveTimerProfile = name
+		return nil
+	})
+}
+
+// DeleteTimerProfile removes the named profile from the admin's meet.
+func (ac *AdminController) DeleteTimerProfile(c *gin.Context) {
+	meetID, ok := adminMeet(c, "DeleteTimerProfile", models.PermManageTimerProfiles)
+	if !ok {
+		return
+	}
+
+	name := c.PostForm("name")
+	ac.updateMeet(c, meetID, "DeleteTimerProfile", func(meet *models.Meet) error {
+		if !meet.RemoveTimerProfile(name) {
+			return fmt.Errorf("no timer profile named %q", name)
+		}
+		return nil
+	})
+}
+
+// adminMeet checks the session's role grants perm and returns the meet it is logged in to.
+// Admins can only edit their own meet, so the meet never comes from the form.
+func adminMeet(c *gin.Context, handler string, perm models.Permission) (string, bool) {
+	if !middleware.HasPermission(c, perm) {
+		logger.Warn.Printf("[%s] Unauthorized attempt", handler)
+		c.String(http.StatusUnauthorized, "Unauthorized")
+		return "", false
+	}
+	meetID, _ := sessions.Default(c).Get("meetID").(string)
+	if meetID == "" {
+		c.String(http.StatusBadRequest, "Meet not specified")
+		return "", false
+	}
+	return meetID, true
+}
+
+// updateMeet applies change to the named meet in the credentials file, saves it and
+// redirects back to the admin panel. A change error is reported as a bad request.
+func (ac *AdminController) updateMeet(c *gin.Context, meetID, handler string, change func(meet *models.Meet) error) {
+	meetCredsMu.Lock()
+	defer meetCredsMu.Unlock()
+
+	creds, err := loadMeetCredsFunc()
+	if err != nil {
+		logger.Error.Printf("[%s] Failed to load meet credentials: %v", handler, err)
+		c.String(http.StatusInternalServerError, "Failed to load meet configuration")
+		return
+	}
+	meet := findMeet(creds, meetID)
+	if meet == nil {
+		c.String(http.StatusNotFound, "Meet not found")
+		return
+	}
+	if err := change(meet); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := saveMeetCredsFunc(ac.MeetCredsPath, creds); err != nil {
+		logger.Error.Printf("[%s] Failed to save meet credentials: %v", handler, err)
+		c.String(http.StatusInternalServerError, "Failed to save meet configuration")
+		return
+	}
+
+	reloadMeetRegistry(handler)
+	logger.Info.Printf("[%s] Saved meet '%s'", handler, meetID)
+	c.Redirect(http.StatusFound, "/admin?meet="+meetID)
+}
+
+// findTimerProfile looks up a profile by name.
+func findTimerProfile(meet *models.Meet, name string) (models.TimerProfile, bool) {
+	for _, p := range meet.TimerProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return models.TimerProfile{}, false
+}
+
+// timerProfileFromForm reads a profile from the admin panel form.
+func timerProfileFromForm(c *gin.Context) (models.TimerProfile, error) {
+	profile := models.TimerProfile{Name: strings.TrimSpace(c.PostForm("name"))}
+	fields := []struct {
+		key      string
+		target   *int
+		optional bool
+	}{
+		{"platformReadySeconds", &profile.PlatformReadySeconds, false},
+		{"nextAttemptSeconds", &profile.NextAttemptSeconds, false},
+		{"consecutiveAttemptSeconds", &profile.ConsecutiveAttemptSeconds, true},
+		{"resultsDisplaySeconds", &profile.ResultsDisplaySeconds, false},
+	}
+	for _, f := range fields {
+		raw := strings.TrimSpace(c.PostForm(f.key))
+		if raw == "" && f.optional {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return profile, fmt.Errorf("%s must be a whole number of seconds", f.key)
+		}
+		*f.target = n
+	}
+	return profile, nil
+}
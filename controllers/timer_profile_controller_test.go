@@ -0,0 +1,152 @@
+// controllers/timer_profile_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/config"
+	"go-ref-lights/models"
+)
+
+// stubMeetCreds serves creds from memory and records every save for the duration of a test.
+func stubMeetCreds(t *testing.T, creds *models.MeetCreds) *int {
+	origLoad, origSave := loadMeetCredsFunc, saveMeetCredsFunc
+	saves := 0
+	loadMeetCredsFunc = func() (*models.MeetCreds, error) {
+		// hand out a deep copy, as re-reading the file would
+		data, _ := json.Marshal(creds)
+		var copied models.MeetCreds
+		_ = json.Unmarshal(data, &copied)
+		copied.AssignIdentities()
+		return &copied, nil
+	}
+	saveMeetCredsFunc = func(_ string, updated *models.MeetCreds) error {
+		*creds = *updated
+		saves++
+		return nil
+	}
+	t.Cleanup(func() { loadMeetCredsFunc, saveMeetCredsFunc = origLoad, origSave })
+	return &saves
+}
+
+// timerProfileRouter wires the timer profile routes behind an admin session for meetName.
+func timerProfileRouter(t *testing.T, meetID string) (*gin.Engine, *http.Cookie) {
+	ac := NewAdminController(new(MockOccupancyService), nil, config.Default())
+	router := setupTestRouter(t)
+	router.POST("/admin/timer-profiles", ac.SaveTimerProfile)
+	router.POST("/admin/timer-profiles/activate", ac.ActivateTimerProfile)
+	router.POST("/admin/timer-profiles/delete", ac.DeleteTimerProfile)
+	cookie := SetSession(router, "/set-session", map[string]interface{}{"role": "meet_director", "meetID": meetID})
+	require.NotNil(t, cookie)
+	return router, cookie
+}
+
+func postForm(router *gin.Engine, cookie *http.Cookie, path string, form url.Values) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTimerProfiles_SaveActivateDelete(t *testing.T) {
+	creds := &models.MeetCreds{Meets: []models.Meet{{Name: "TestMeet"}, {Name: "OtherMeet"}}}
+	saves := stubMeetCreds(t, creds)
+	router, cookie := timerProfileRouter(t, "testmeet")
+
+	w := postForm(router, cookie, "/admin/timer-profiles", url.Values{
+		"name":                      {"Flight of one"},
+		"platformReadySeconds":      {"60"},
+		"nextAttemptSeconds":        {"60"},
+		"consecutiveAttemptSeconds": {"180"},
+		"resultsDisplaySeconds":     {"10"},
+		"activate":                  {"true"},
+	})
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, 1, *saves)
+	require.Len(t, creds.Meets[0].TimerProfiles, 1)
+	assert.Equal(t, "Flight of one", creds.Meets[0].ActiveTimerProfile)
+	assert.Empty(t, creds.Meets[1].TimerProfiles, "other meets are untouched")
+
+	// the websocket package sees the active profile as durations
+	profile, ok := TimerProfileFor("testmeet")
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Minute, profile.ConsecutiveAttempt)
+	assert.Equal(t, 10*time.Second, profile.ResultsDisplay)
+	_, ok = TimerProfileFor("OtherMeet")
+	assert.False(t, ok)
+
+	// unknown profiles cannot be activated
+	w = postForm(router, cookie, "/admin/timer-profiles/activate", url.Values{"name": {"Missing"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// an empty name reverts to the default clocks
+	w = postForm(router, cookie, "/admin/timer-profiles/activate", url.Values{"name": {""}})
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Empty(t, creds.Meets[0].ActiveTimerProfile)
+
+	w = postForm(router, cookie, "/admin/timer-profiles/delete", url.Values{"name": {"Flight of one"}})
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Empty(t, creds.Meets[0].TimerProfiles)
+}
+
+func TestTimerProfiles_RejectsInvalidInput(t *testing.T) {
+	creds := &models.MeetCreds{Meets: []models.Meet{{Name: "TestMeet"}}}
+	saves := stubMeetCreds(t, creds)
+	router, cookie := timerProfileRouter(t, "testmeet")
+
+	for name, form := range map[string]url.Values{
+		"missing name":  {"platformReadySeconds": {"60"}, "nextAttemptSeconds": {"60"}, "resultsDisplaySeconds": {"15"}},
+		"not a number":  {"name": {"X"}, "platformReadySeconds": {"one minute"}, "nextAttemptSeconds": {"60"}, "resultsDisplaySeconds": {"15"}},
+		"zero duration": {"name": {"X"}, "platformReadySeconds": {"60"}, "nextAttemptSeconds": {"0"}, "resultsDisplaySeconds": {"15"}},
+	} {
+		w := postForm(router, cookie, "/admin/timer-profiles", form)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+	assert.Equal(t, 0, *saves)
+}
+
+func TestTimerProfiles_RequiresAdmin(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{Name: "TestMeet"}}})
+	ac := NewAdminController(new(MockOccupancyService), nil, config.Default())
+	router := setupTestRouter(t)
+	router.POST("/admin/timer-profiles", ac.SaveTimerProfile)
+
+	w := postForm(router, nil, "/admin/timer-profiles", url.Values{"name": {"X"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSaveMeetCreds_WritesConfiguredFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meet_creds.json")
+
+	creds := &models.MeetCreds{Meets: []models.Meet{{
+		Name:               "TestMeet",
+		TimerProfiles:      []models.TimerProfile{{Name: "IPF", PlatformReadySeconds: 60, NextAttemptSeconds: 60, ResultsDisplaySeconds: 15}},
+		ActiveTimerProfile: "IPF",
+	}}}
+	require.NoError(t, SaveMeetCreds(path, creds))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded models.MeetCreds
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, creds.Meets[0].TimerProfiles, decoded.Meets[0].TimerProfiles)
+	assert.Equal(t, "IPF", decoded.Meets[0].ActiveTimerProfile)
+}
@@ -0,0 +1,98 @@
+// Package controllers authenticates WebSocket upgrades against the HTTP session.
+// File: controllers/websocket_controller.go
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+
+	"go-ref-lights/config"
+	"go-ref-lights/logger"
+	"go-ref-lights/middleware"
+	"go-ref-lights/models"
+	"go-ref-lights/services"
+	"go-ref-lights/websocket"
+)
+
+// WebSocketController upgrades /referee-updates requests for logged-in clients.
+type WebSocketController struct {
+	OccupancyService services.OccupancyServiceInterface
+	Settings         config.WebSocket // deadlines and limits of the upgraded connections
+}
+
+// NewWebSocketController initializes a WebSocketController.
+func NewWebSocketController(occupancyService services.OccupancyServiceInterface, settings config.WebSocket) *WebSocketController {
+	return &WebSocketController{OccupancyService: occupancyService, Settings: settings}
+}
+
+// Upgrade authenticates the request from the session cookie and hands it to the WebSocket layer.
+func (wc *WebSocketController) Upgrade(c *gin.Context) {
+	id, status, err := wc.identify(c)
+	if err != nil {
+		logger.Warn.Printf("[WebSocketController.Upgrade] Rejected %s: %v", c.ClientIP(), err)
+		c.String(status, err.Error())
+		return
+	}
+	websocket.ServeWs(c.Writer, c.Request, id, wc.Settings)
+}
+
+// identify builds the connection identity from the session, refusing sessions nobody
+// has logged in to. The meet ID comes from the session (superusers may pick one with
+// ?meet=), the platform from ?platform= or the session, and the referee seat is only
+// granted if OccupancyService confirms the session user holds it on that platform.
+// Only a jury seat held by a role that may amend decisions makes the connection the jury's.
+func (wc *WebSocketController) identify(c *gin.Context) (websocket.Identity, int, error) {
+	session := sessions.Default(c)
+	meetID, _ := session.Get("meetID").(string)
+	user, _ := session.Get("user").(string)
+	position, _ := session.Get("refPosition").(string)
+	role := middleware.SessionRole(c)
+	isSudo := role.Can(models.PermManageAllMeets)
+	if user == "" {
+		return websocket.Identity{}, http.StatusUnauthorized, errors.New("not logged in")
+	}
+
+	requested := c.Query("meet")
+	switch {
+	case isSudo && requested != "":
+		meetID = requested
+	case meetID == "":
+		return websocket.Identity{}, http.StatusUnauthorized, errors.New("no meet in session")
+	case requested != "" && requested != meetID:
+		return websocket.Identity{}, http.StatusForbidden, errors.New("meet does not match session")
+	}
+
+	platformID := c.Query("platform")
+	if platformID == "" {
+		platformID = models.DefaultPlatformID
+		if sessionMeet, _ := session.Get("meetID").(string); sessionMeet == meetID {
+			platformID = sessionPlatform(session)
+		}
+	}
+	if meet, err := lookupMeet(meetID); err == nil {
+		platform, ok := meet.FindPlatform(platformID)
+		if !ok {
+			return websocket.Identity{}, http.StatusNotFound, errors.New("unknown platform")
+		}
+		platformID = platform.ID
+	} else if platformID != models.DefaultPlatformID {
+		return websocket.Identity{}, http.StatusNotFound, errors.New("unknown platform")
+	}
+
+	id := websocket.Identity{
+		MeetName: meetID,
+		Platform: platformID,
+		User:     user,
+		Admin:    role.Can(models.PermControlTimer),
+	}
+	if user != "" && position != "" && wc.OccupancyService.GetOccupancy(meetID, platformID).UserAt(position) == user {
+		id.JudgeID = position
+		// amendments come from the jury seat a jury member has claimed
+		seat, ok := findPosition(meetID, position)
+		id.Jury = ok && !seat.Votes() && role.Can(models.PermAmendDecision)
+	}
+	return id, http.StatusOK, nil
+}
@@ -0,0 +1,170 @@
+// controllers/websocket_controller_test.go
+//go:build unit
+// +build unit
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/config"
+	"go-ref-lights/models"
+	"go-ref-lights/services"
+	"go-ref-lights/websocket"
+)
+
+// identifyRouter exposes WebSocketController.identify as JSON so it can be tested without an upgrade.
+func identifyRouter(t *testing.T, occ services.Occupancy, session map[string]interface{}) (*gin.Engine, *http.Cookie) {
+	mockOccupancy := new(MockOccupancyService)
+	mockOccupancy.On("GetOccupancy", mock.Anything, mock.Anything).Return(occ)
+	wc := NewWebSocketController(mockOccupancy, config.Default().WebSocket)
+
+	router := setupTestRouter(t)
+	router.GET("/identify", func(c *gin.Context) {
+		id, status, err := wc.identify(c)
+		if err != nil {
+			c.String(status, err.Error())
+			return
+		}
+		c.JSON(status, id)
+	})
+	router.GET("/referee-updates", wc.Upgrade)
+	return router, SetSession(router, "/set-session", session)
+}
+
+func getIdentity(t *testing.T, router *gin.Engine, cookie *http.Cookie, path string) (int, websocket.Identity) {
+	req, _ := http.NewRequest("GET", path, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var id websocket.Identity
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &id))
+	}
+	return w.Code, id
+}
+
+func TestWebSocketIdentify_BindsHeldSeat(t *testing.T) {
+	router, cookie := identifyRouter(t, services.Occupancy{Seats: map[string]string{"left": "ref1"}}, map[string]interface{}{
+		"meetID": "testmeet", "user": "ref1", "refPosition": "left",
+	})
+
+	code, id := getIdentity(t, router, cookie, "/identify?meet=testmeet")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, websocket.Identity{MeetName: "testmeet", Platform: models.DefaultPlatformID, User: "ref1", JudgeID: "left"}, id)
+}
+
+func TestWebSocketIdentify_NoSeatWhenNotHeld(t *testing.T) {
+	router, cookie := identifyRouter(t, services.Occupancy{Seats: map[string]string{"left": "someoneElse"}}, map[string]interface{}{
+		"meetID": "testmeet", "user": "ref1", "refPosition": "left",
+	})
+
+	code, id := getIdentity(t, router, cookie, "/identify")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "testmeet", id.MeetName)
+	assert.Empty(t, id.JudgeID, "a stale session must not be bound to a seat held by someone else")
+}
+
+func TestWebSocketIdentify_RejectsOtherMeet(t *testing.T) {
+	router, cookie := identifyRouter(t, services.Occupancy{}, map[string]interface{}{"meetID": "testmeet", "user": "ref1"})
+
+	code, _ := getIdentity(t, router, cookie, "/identify?meet=othermeet")
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestWebSocketIdentify_SudoMayChooseMeet(t *testing.T) {
+	router, cookie := identifyRouter(t, services.Occupancy{}, map[string]interface{}{"user": "root", "role": "superuser"})
+
+	code, id := getIdentity(t, router, cookie, "/identify?meet=othermeet")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "othermeet", id.MeetName)
+	assert.True(t, id.Admin)
+}
+
+func TestWebSocketIdentify_MarksAdmins(t *testing.T) {
+	router, cookie := identifyRouter(t, services.Occupancy{}, map[string]interface{}{
+		"meetID": "testmeet", "user": "director", "role": "meet_director",
+	})
+
+	code, id := getIdentity(t, router, cookie, "/identify")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, id.Admin, "meet admins may control the clock")
+	assert.Empty(t, id.JudgeID)
+}
+
+func TestWebSocketIdentify_JuryFromClaimedSeat(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		ID:   "testmeet",
+		Name: "Test Meet",
+		Positions: []models.Position{
+			{ID: "left", Name: "Left Referee", Chief: true},
+			{ID: "jury", Name: "Jury", Kind: models.SeatJury},
+		},
+	}}})
+
+	router, cookie := identifyRouter(t, services.Occupancy{Seats: map[string]string{"jury": "jury1"}}, map[string]interface{}{
+		"meetID": "testmeet", "user": "jury1", "role": "jury", "refPosition": "jury",
+	})
+	code, id := getIdentity(t, router, cookie, "/identify")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "jury", id.JudgeID)
+	assert.True(t, id.Jury, "a jury member in a jury seat may amend")
+
+	router, cookie = identifyRouter(t, services.Occupancy{}, map[string]interface{}{
+		"meetID": "testmeet", "user": "jury1", "role": "jury",
+	})
+	_, id = getIdentity(t, router, cookie, "/identify")
+	assert.False(t, id.Jury, "the jury role alone does not make a connection the jury's")
+
+	router, cookie = identifyRouter(t, services.Occupancy{Seats: map[string]string{"jury": "tc"}}, map[string]interface{}{
+		"meetID": "testmeet", "user": "tc", "role": "technical_controller", "refPosition": "jury",
+	})
+	_, id = getIdentity(t, router, cookie, "/identify")
+	assert.Equal(t, "jury", id.JudgeID)
+	assert.False(t, id.Jury, "a jury seat alone does not grant amendments")
+}
+
+func TestWebSocketUpgrade_RejectsWithoutSession(t *testing.T) {
+	router, _ := identifyRouter(t, services.Occupancy{}, map[string]interface{}{})
+
+	code, _ := getIdentity(t, router, nil, "/referee-updates?meet=testmeet")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestWebSocketUpgrade_RejectsMeetWithoutLogin(t *testing.T) {
+	// choosing a meet puts it in the session; only logging in adds a user
+	router, cookie := identifyRouter(t, services.Occupancy{}, map[string]interface{}{"meetID": "testmeet"})
+
+	code, _ := getIdentity(t, router, cookie, "/referee-updates?meet=testmeet")
+	assert.Equal(t, http.StatusUnauthorized, code, "a meet alone must not open the live feed")
+}
+
+func TestWebSocketIdentify_FollowsPlatform(t *testing.T) {
+	stubMeetCreds(t, &models.MeetCreds{Meets: []models.Meet{{
+		ID:        "testmeet",
+		Name:      "Test Meet",
+		Platforms: []models.Platform{{ID: "a", Name: "Platform A"}, {ID: "b", Name: "Platform B"}},
+	}}})
+	router, cookie := identifyRouter(t, services.Occupancy{}, map[string]interface{}{"meetID": "testmeet", "user": "ref1", "platform": "b"})
+
+	code, id := getIdentity(t, router, cookie, "/identify")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "b", id.Platform, "the session's platform is followed by default")
+
+	code, id = getIdentity(t, router, cookie, "/identify?platform=a")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "a", id.Platform)
+
+	code, _ = getIdentity(t, router, cookie, "/identify?platform=main")
+	assert.Equal(t, http.StatusNotFound, code, "a meet that lists platforms has no main platform")
+}
@@ -0,0 +1,328 @@
+// Package history keeps an append-only record of every completed attempt so that
+// meet directors can review the lights after the session.
+// File: history/decision_log.go
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// ------------------------ record model ------------------------
+
+// JudgeVote is a single referee's contribution to an attempt.
+type JudgeVote struct {
+	Position    string    `json:"position"`        // seat on the meet's panel, e.g. left, center or right
+	Decision    string    `json:"decision"`        // white or red (empty if the judge never submitted)
+	Cards       []string  `json:"cards,omitempty"` // reason cards attached to a red decision
+	Occupant    string    `json:"occupant"`        // who was seated in that position
+	SubmittedAt time.Time `json:"submittedAt"`     // when the decision reached the server
+}
+
+// DecisionRecord is a completed attempt as it was shown on the lights.
+type DecisionRecord struct {
+	MeetName    string      `json:"meetName"`
+	Platform    string      `json:"platform,omitempty"`  // platform the attempt was lifted on
+	Attempt     int         `json:"attempt"`             // sequence number within the meet, assigned by the store
+	AttemptID   int64       `json:"attemptId,omitempty"` // live attempt ID on the platform, as sent to clients
+	Judges      []JudgeVote `json:"judges"`
+	Verdict     string      `json:"verdict"`    // final outcome, "good lift" or "no lift", after any jury amendment
+	WhiteCount  int         `json:"whiteCount"` // number of white decisions
+	RedCount    int         `json:"redCount"`   // number of red decisions
+	CompletedAt time.Time   `json:"completedAt"`
+	Amendments  []Amendment `json:"amendments,omitempty"` // jury corrections, oldest first
+}
+
+// Amendment is a jury's correction of a completed attempt. The referees' votes are
+// left as they were; only the verdict changes.
+type Amendment struct {
+	OriginalVerdict string    `json:"originalVerdict"` // verdict before this amendment
+	Verdict         string    `json:"verdict"`         // verdict the jury ruled
+	JuryMember      string    `json:"juryMember"`      // account that made the ruling
+	Reason          string    `json:"reason"`
+	AmendedAt       time.Time `json:"amendedAt"`
+}
+
+// OriginalVerdict is the verdict the referees gave, before any jury amendment.
+func (r DecisionRecord) OriginalVerdict() string {
+	if len(r.Amendments) > 0 {
+		return r.Amendments[0].OriginalVerdict
+	}
+	return r.Verdict
+}
+
+// ------------------------ store interface ------------------------
+
+// Store persists decision records. Implementations must be safe for concurrent use.
+type Store interface {
+	// Append assigns the next attempt sequence number for rec.MeetName and stores the record.
+	Append(rec *DecisionRecord) error
+	// List returns a page of records for a meet, newest first, and the total number of records.
+	List(meetName string, offset, limit int) ([]DecisionRecord, int, error)
+	// Amend applies a jury amendment to an attempt, filling in its OriginalVerdict, and
+	// returns the amended record.
+	Amend(meetName string, attempt int, amendment Amendment) (*DecisionRecord, error)
+}
+
+// ErrAttemptNotFound is returned by Amend when the meet has no such attempt.
+var ErrAttemptNotFound = errors.New("attempt not found")
+
+// ------------------------ JSON-lines implementation ------------------------
+
+// unsafeFileChars matches anything we don't want in a file name derived from a meet name.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// JSONLStore writes one JSON-lines file per meet into a directory. Several processes
+// may share the directory: each write holds an exclusive lock on the meet's file, and
+// attempt numbers are taken from the file itself, so they never clash.
+type JSONLStore struct {
+	dir   string
+	mu    sync.Mutex
+	tails map[string]logTail // last attempt per meet, valid while the file is unchanged
+}
+
+// logTail is the last attempt number in a meet's log when the log had the given size.
+type logTail struct {
+	size    int64
+	attempt int
+}
+
+// NewJSONLStore creates the directory if needed and returns a store that writes into it.
+func NewJSONLStore(dir string) (*JSONLStore, error) {
+	if dir == "" {
+		return nil, errors.New("decision log directory must not be empty")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create decision log directory: %w", err)
+	}
+	return &JSONLStore{
+		dir:   dir,
+		tails: make(map[string]logTail),
+	}, nil
+}
+
+// Append implements Store.
+func (s *JSONLStore) Append(rec *DecisionRecord) error {
+	if rec == nil || rec.MeetName == "" {
+		return errors.New("decision record must have a meet name")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.openLocked(rec.MeetName)
+	if err != nil {
+		return err
+	}
+	defer closeLocked(f)
+
+	last, err := s.lastAttempt(f, rec.MeetName)
+	if err != nil {
+		return err
+	}
+	rec.Attempt = last + 1
+
+	if err := s.write(f, rec); err != nil {
+		return err
+	}
+
+	logger.Debug.Printf("[JSONLStore.Append] meet=%s attempt=%d verdict=%s", rec.MeetName, rec.Attempt, rec.Verdict)
+	return nil
+}
+
+// Amend implements Store. The log stays append-only: the amended record is written
+// as a new line for the same attempt, and readers keep the last line per attempt.
+func (s *JSONLStore) Amend(meetName string, attempt int, amendment Amendment) (*DecisionRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.openLocked(meetName)
+	if err != nil {
+		return nil, err
+	}
+	defer closeLocked(f)
+
+	records, err := s.readAll(meetName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].Attempt != attempt {
+			continue
+		}
+		rec := records[i]
+		amendment.OriginalVerdict = rec.Verdict
+		rec.Verdict = amendment.Verdict
+		rec.Amendments = append(rec.Amendments, amendment)
+		if err := s.write(f, &rec); err != nil {
+			return nil, err
+		}
+		logger.Info.Printf("[JSONLStore.Amend] meet=%s attempt=%d %s -> %s by %s",
+			meetName, attempt, amendment.OriginalVerdict, amendment.Verdict, amendment.JuryMember)
+		return &rec, nil
+	}
+	return nil, ErrAttemptNotFound
+}
+
+// openLocked opens the meet's log for appending and waits for the exclusive lock that
+// every process writing the log takes. Release it with closeLocked. Caller must hold s.mu.
+func (s *JSONLStore) openLocked(meetName string) (*os.File, error) {
+	f, err := os.OpenFile(s.pathFor(meetName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to open decision log: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock decision log: %w", err)
+	}
+	return f, nil
+}
+
+// closeLocked releases the lock taken by openLocked and closes the log.
+func closeLocked(f *os.File) {
+	if err := unlockFile(f); err != nil {
+		logger.Warn.Printf("[JSONLStore] Failed to unlock %s: %v", f.Name(), err)
+	}
+	_ = f.Close()
+}
+
+// write appends one record line to the meet's log, opened and locked by openLocked.
+// If this store knew the log's last attempt before the write, it still does after it.
+// Caller must hold s.mu.
+func (s *JSONLStore) write(f *os.File, rec *DecisionRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision record: %w", err)
+	}
+	before, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat decision log: %w", err)
+	}
+	tail, known := s.tails[rec.MeetName]
+	delete(s.tails, rec.MeetName)
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write decision log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync decision log: %w", err)
+	}
+
+	if known && tail.size == before.Size() {
+		if rec.Attempt > tail.attempt {
+			tail.attempt = rec.Attempt
+		}
+		tail.size = before.Size() + int64(len(line)) + 1
+		s.tails[rec.MeetName] = tail
+	}
+	return nil
+}
+
+// List implements Store.
+func (s *JSONLStore) List(meetName string, offset, limit int) ([]DecisionRecord, int, error) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	s.mu.Lock()
+	records, err := s.readAll(meetName)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// newest first
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Attempt > records[j].Attempt })
+
+	total := len(records)
+	if offset >= total {
+		return []DecisionRecord{}, total, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return records[offset:end], total, nil
+}
+
+// lastAttempt returns the highest attempt number in the meet's log, opened and locked
+// by openLocked. The log is only read again when it has grown since this store last
+// looked, for example because another process appended to it. Caller must hold s.mu.
+func (s *JSONLStore) lastAttempt(f *os.File, meetName string) (int, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat decision log: %w", err)
+	}
+	if tail, ok := s.tails[meetName]; ok && tail.size == info.Size() {
+		return tail.attempt, nil
+	}
+	records, err := s.readAll(meetName)
+	if err != nil {
+		return 0, err
+	}
+	last := 0
+	for _, r := range records {
+		if r.Attempt > last {
+			last = r.Attempt
+		}
+	}
+	s.tails[meetName] = logTail{size: info.Size(), attempt: last}
+	return last, nil
+}
+
+// readAll loads every record for a meet, in attempt order. An amended attempt appears
+// once, as its latest line. Caller must hold s.mu.
+func (s *JSONLStore) readAll(meetName string) ([]DecisionRecord, error) {
+	f, err := os.Open(s.pathFor(meetName)) // #nosec G304
+	if errors.Is(err, os.ErrNotExist) {
+		return []DecisionRecord{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open decision log: %w", err)
+	}
+	defer f.Close()
+
+	var records []DecisionRecord
+	index := make(map[int]int) // attempt -> position in records
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec DecisionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// a torn final line (e.g. power loss mid-write) should not hide the rest of the log
+			logger.Warn.Printf("[JSONLStore.readAll] Skipping unreadable line for meet=%s: %v", meetName, err)
+			continue
+		}
+		// files are keyed by a sanitised name, so guard against collisions
+		if rec.MeetName != meetName {
+			continue
+		}
+		if i, seen := index[rec.Attempt]; seen {
+			records[i] = rec
+			continue
+		}
+		index[rec.Attempt] = len(records)
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read decision log: %w", err)
+	}
+	return records, nil
+}
+
+// pathFor maps a meet name to its log file.
+func (s *JSONLStore) pathFor(meetName string) string {
+	name := unsafeFileChars.ReplaceAllString(meetName, "_")
+	return filepath.Join(s.dir, name+".jsonl")
+}
@@ -0,0 +1,211 @@
+// file: history/decision_log_test.go
+//go:build unit
+// +build unit
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecord(meetName, verdict string) *DecisionRecord {
+	return &DecisionRecord{
+		MeetName: meetName,
+		Verdict:  verdict,
+		Judges: []JudgeVote{
+			{Position: "left", Decision: "white", Occupant: "ref1"},
+			{Position: "center", Decision: "red", Cards: []string{"blue"}, Occupant: "ref2"},
+			{Position: "right", Decision: "white", Occupant: "ref3"},
+		},
+	}
+}
+
+func TestJSONLStore_AppendAssignsSequence(t *testing.T) {
+	store, err := NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+
+	first := newRecord("APL Nationals", "good lift")
+	second := newRecord("APL Nationals", "no lift")
+	other := newRecord("Dragon Cup", "good lift")
+
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+	require.NoError(t, store.Append(other))
+
+	assert.Equal(t, 1, first.Attempt)
+	assert.Equal(t, 2, second.Attempt)
+	assert.Equal(t, 1, other.Attempt, "Each meet has its own sequence")
+}
+
+func TestJSONLStore_ListNewestFirstWithPaging(t *testing.T) {
+	store, err := NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.Append(newRecord("APL Open", "good lift")))
+	}
+
+	page, total, err := store.List("APL Open", 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	require.Len(t, page, 2)
+	assert.Equal(t, 5, page[0].Attempt)
+	assert.Equal(t, 4, page[1].Attempt)
+
+	page, _, err = store.List("APL Open", 4, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, 1, page[0].Attempt)
+
+	page, total, err = store.List("APL Open", 10, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, total)
+	assert.Empty(t, page)
+}
+
+func TestJSONLStore_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(newRecord("Cairns Cup", "good lift")))
+	require.NoError(t, store.Append(newRecord("Cairns Cup", "no lift")))
+
+	reopened, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	rec := newRecord("Cairns Cup", "good lift")
+	require.NoError(t, reopened.Append(rec))
+	assert.Equal(t, 3, rec.Attempt, "Sequence should continue after a restart")
+
+	page, total, err := reopened.List("Cairns Cup", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "ref2", page[2].Judges[1].Occupant)
+	assert.Equal(t, []string{"blue"}, page[2].Judges[1].Cards)
+}
+
+func TestJSONLStore_SkipsTornLine(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(newRecord("Torn Meet", "good lift")))
+
+	f, err := os.OpenFile(filepath.Join(dir, "Torn_Meet.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, _ = f.WriteString(`{"meetName":"Torn Meet","att`)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	page, total, err := reopened.List("Torn Meet", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, page[0].Attempt)
+}
+
+func TestJSONLStore_AmendKeepsBothVerdicts(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Append(newRecord("Jury Meet", "good lift")))
+	require.NoError(t, store.Append(newRecord("Jury Meet", "good lift")))
+
+	amended, err := store.Amend("Jury Meet", 1, Amendment{Verdict: "no lift", JuryMember: "jury1", Reason: "soft knees"})
+	require.NoError(t, err)
+	assert.Equal(t, "no lift", amended.Verdict)
+	assert.Equal(t, "good lift", amended.OriginalVerdict())
+
+	_, err = store.Amend("Jury Meet", 9, Amendment{Verdict: "no lift"})
+	assert.ErrorIs(t, err, ErrAttemptNotFound)
+
+	// the amendment survives a restart and the attempt is still listed once
+	reopened, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	page, total, err := reopened.List("Jury Meet", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, page, 2)
+	assert.Equal(t, 1, page[1].Attempt)
+	assert.Equal(t, "no lift", page[1].Verdict)
+	require.Len(t, page[1].Amendments, 1)
+	assert.Equal(t, "jury1", page[1].Amendments[0].JuryMember)
+	assert.Equal(t, "soft knees", page[1].Amendments[0].Reason)
+	assert.Equal(t, "good lift", page[1].Amendments[0].OriginalVerdict)
+	assert.Equal(t, "good lift", page[0].Verdict, "other attempts are untouched")
+
+	rec := newRecord("Jury Meet", "good lift")
+	require.NoError(t, reopened.Append(rec))
+	assert.Equal(t, 3, rec.Attempt)
+}
+
+func TestJSONLStore_SharedFileAcrossStores(t *testing.T) {
+	// two instances writing the same directory, as two servers on a shared volume would
+	dir := t.TempDir()
+	a, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+	b, err := NewJSONLStore(dir)
+	require.NoError(t, err)
+
+	first, second, third := newRecord("Shared Meet", "good lift"), newRecord("Shared Meet", "no lift"), newRecord("Shared Meet", "good lift")
+	require.NoError(t, a.Append(first))
+	require.NoError(t, b.Append(second))
+	require.NoError(t, a.Append(third))
+	assert.Equal(t, []int{1, 2, 3}, []int{first.Attempt, second.Attempt, third.Attempt},
+		"each store continues from the other's attempts")
+
+	_, err = b.Amend("Shared Meet", 3, Amendment{Verdict: "no lift", JuryMember: "jury1"})
+	require.NoError(t, err)
+	fourth := newRecord("Shared Meet", "good lift")
+	require.NoError(t, a.Append(fourth))
+	assert.Equal(t, 4, fourth.Attempt, "an amendment is not a new attempt")
+
+	page, total, err := b.List("Shared Meet", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 4, total, "no attempt hides another")
+	assert.Equal(t, "no lift", page[1].Verdict)
+}
+
+func TestJSONLStore_ConcurrentStoresNeverClash(t *testing.T) {
+	dir := t.TempDir()
+	stores := make([]*JSONLStore, 2)
+	for i := range stores {
+		store, err := NewJSONLStore(dir)
+		require.NoError(t, err)
+		stores[i] = store
+	}
+
+	const perStore = 20
+	var wg sync.WaitGroup
+	for _, store := range stores {
+		wg.Add(1)
+		go func(store *JSONLStore) {
+			defer wg.Done()
+			for i := 0; i < perStore; i++ {
+				assert.NoError(t, store.Append(newRecord("Busy Meet", "good lift")))
+			}
+		}(store)
+	}
+	wg.Wait()
+
+	page, total, err := stores[0].List("Busy Meet", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2*perStore, total)
+	seen := map[int]bool{}
+	for _, rec := range page {
+		seen[rec.Attempt] = true
+	}
+	assert.Len(t, seen, 2*perStore, "every attempt number is used once")
+}
+
+func TestJSONLStore_RejectsMissingMeet(t *testing.T) {
+	store, err := NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	assert.Error(t, store.Append(&DecisionRecord{}))
+
+	_, err = NewJSONLStore("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,14 @@
+// File: history/lock_other.go
+
+//go:build !unix
+
+package history
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; only one process may then write a
+// decision log directory.
+func lockFile(f *os.File) error { return nil }
+
+// unlockFile is a no-op, matching lockFile.
+func unlockFile(f *os.File) error { return nil }
@@ -2,15 +2,21 @@
 package main
 
 import (
+	"fmt"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go-ref-lights/config"
 	"go-ref-lights/controllers"
 	"go-ref-lights/heartbeat"
+	"go-ref-lights/history"
 	"go-ref-lights/logger"
 	"go-ref-lights/middleware"
+	"go-ref-lights/models"
+	"go-ref-lights/openlifter"
 	"go-ref-lights/services"
+	"go-ref-lights/storage"
 	"go-ref-lights/websocket"
 	"html/template"
 	"io"
@@ -20,6 +26,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"time"
+	_ "time/tzdata" // meet schedules name IANA zones; the alpine image has no zoneinfo
 )
 
 // GinHeartbeatHandler is a wrapper that calls HeartbeatHandler from your heartbeat.go file
@@ -34,68 +41,55 @@ func main() {
 		logger.Warn.Println("[main] No .env file found. Using system environment variables.")
 	}
 
-	// Determine the environment
-	env := os.Getenv("ENV")
-	if env == "" {
-		env = "development"
+	// Load and validate the application config (YAML file plus environment overrides)
+	configPath := os.Getenv("CONFIG_FILE")
+	if configPath == "" {
+		configPath = config.DefaultPath
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("[main] Invalid configuration in %s: %v", configPath, err)
 	}
 
 	// Set your logging level based on environment
-	logger.SetLogLevel(env)
+	logger.SetLogLevel(cfg.Env)
 
 	// Log the environment
-	logger.Info.Printf("[main] Running in %s mode", env)
+	logger.Info.Printf("[main] Running in %s mode", cfg.Env)
 
-	// Set application & websocket URLs based on environment
-	var applicationURL, websocketURL string
-	if env == "production" {
-		applicationURL = "https://referee-lights.michaelkingston.com.au"
-		websocketURL = "wss://referee-lights.michaelkingston.com.au/referee-updates"
-	} else {
-		applicationURL = "http://0.0.0.0:8080"
-		websocketURL = "ws://0.0.0.0:8080/referee-updates"
-	}
+	// Only let our own pages (and any configured extra hosts) open WebSockets
+	websocket.SetOriginPolicy(newOriginPolicy(cfg))
 
-	// Pass computed URLs to controllers
-	controllers.SetConfig(applicationURL, websocketURL)
-
-	// Load credentials
-	creds, err := controllers.LoadMeetCreds()
+	// Load meets and credentials once, validate them together, and reload on change
+	registry, err := services.NewMeetRegistry(cfg.MeetCredsPath, cfg.MeetsPath)
 	if err != nil {
-		logger.Error.Printf("[main] Error loading credentials: %v", err)
-	} else {
-		logger.Info.Printf("[main] Loaded meets: %+v", creds.Meets)
+		log.Fatalf("[main] %v", err)
 	}
-
-	// Announce start
-	logger.Info.Println("[main] Starting application on port :8080")
+	if err := registry.Watch(); err != nil {
+		logger.Warn.Printf("[main] Meet files will not hot-reload: %v", err)
+	}
+	defer func() { _ = registry.Close() }()
+	controllers.SetMeetRegistry(registry)
 
 	// Setup the router
-	router := SetupRouter(env)
+	router := SetupRouter(cfg)
 
 	// Start background routines
 	hbManager := heartbeat.NewHeartbeatManager()
 	go hbManager.CleanupInactiveSessions(30 * time.Second)
+	bp, err := newBackplane(cfg)
+	if err != nil {
+		log.Fatalf("[main] %v", err)
+	}
+	if bp != nil {
+		websocket.SetBackplane(bp)
+	}
 	go websocket.HandleMessages()
 
 	router.GET("/heartbeat", GinHeartbeatHandler)
 
-	// Read host/port from environment or default
-	host := os.Getenv("APP_HOST")
-	if host == "" {
-		if env == "production" {
-			host = "0.0.0.0"
-		} else {
-			host = "localhost"
-		}
-	}
-	port := os.Getenv("APP_PORT")
-	if port == "" {
-		port = "8080"
-	}
-	addr := host + ":" + port
-
 	// Create an HTTP server with timeouts
+	addr := cfg.Addr()
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      router,
@@ -112,9 +106,9 @@ func main() {
 }
 
 // SetupRouter creates and configures a Gin router.
-func SetupRouter(env string) *gin.Engine {
+func SetupRouter(cfg *config.Config) *gin.Engine {
 	// Configure Gin mode
-	if env == "production" {
+	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.TestMode)
@@ -125,14 +119,14 @@ func SetupRouter(env string) *gin.Engine {
 	router.StaticFile("/favicon.ico", "./static/images/favicon.ico")
 
 	// Reduce logs in non-production
-	if env != "production" {
+	if !cfg.IsProduction() {
 		gin.DefaultWriter = io.Discard
 		gin.DefaultErrorWriter = io.Discard
 		logger.Debug.Println("[SetupRouter] Gin logs have been discarded for non-production mode.")
 	}
 
 	// Configure session store
-	store := cookie.NewStore([]byte("secret"))
+	store := cookie.NewStore([]byte(cfg.SessionSecret))
 	store.Options(sessions.Options{
 		Path:     "/",
 		MaxAge:   86400 * 7, // 7 days
@@ -143,7 +137,7 @@ func SetupRouter(env string) *gin.Engine {
 
 	// Set security headers
 	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("X-Frame-Options", "ALLOW-FROM https://referee-lights.michaelkingston.com.au")
+		c.Writer.Header().Set("X-Frame-Options", cfg.FrameOptions)
 		c.Next()
 	})
 
@@ -184,28 +178,88 @@ func SetupRouter(env string) *gin.Engine {
 		c.Status(http.StatusOK)
 	})
 
+	// Meet clocks fall back to the configured durations; restored timers run on them too
+	websocket.SetTimerManager(websocket.NewTimerManager(cfg.Timers))
+
+	// Runtime state (referee seats, running timers) so a restart doesn't drop the flight
+	stateStore, err := newStateStore(cfg)
+	if err != nil {
+		log.Fatalf("[SetupRouter] %v", err)
+	}
+	websocket.SetStateStore(stateStore)
+	if err := websocket.RestoreMeetStates(); err != nil {
+		logger.Error.Printf("[SetupRouter] Failed to restore meet state: %v", err)
+	}
+
 	// Initialize your service layer
-	occupancyService := services.NewOccupancyService()
+	occupancyService, err := services.NewOccupancyServiceWithStore(stateStore)
+	if err != nil && cfg.State.Store == "redis" {
+		log.Fatalf("[SetupRouter] Failed to read referee seats from Redis: %v", err)
+	} else if err != nil {
+		logger.Error.Printf("[SetupRouter] Failed to restore referee seats, starting empty: %v", err)
+		occupancyService = services.NewOccupancyService()
+	}
+
+	// Seats are claimed against, and votes counted with, each meet's configured panel
+	occupancyService.SetPositionLookup(controllers.PositionsFor)
+	websocket.SetPanelLookup(controllers.PanelFor)
+
+	// WebSocket connections and the decision log both resolve seats through the occupancy service
+	websocket.SetSeatLookup(func(key, position string) string {
+		meetID, platformID := websocket.SplitPlatformKey(key)
+		return occupancyService.GetOccupancy(meetID, platformID).UserAt(position)
+	})
+	webSocketController := controllers.NewWebSocketController(occupancyService, cfg.WebSocket)
+
+	// Each meet's clocks follow its active timer profile, read fresh whenever a clock starts
+	websocket.SetTimerProfileLookup(controllers.TimerProfileFor)
+
+	// Decision history (append-only log of every completed attempt)
+	var decisionStore history.Store
+	if jsonlStore, err := history.NewJSONLStore(cfg.DecisionLogDir); err != nil {
+		logger.Error.Printf("[SetupRouter] Decision history disabled: %v", err)
+	} else {
+		decisionStore = jsonlStore
+		websocket.SetDecisionHistory(decisionStore)
+	}
+	historyController := controllers.NewHistoryController(decisionStore)
+
+	// OpenLifter integration: inbound attempt context and outbound decision push
+	if webhookURL := cfg.OpenLifter.WebhookURL; webhookURL != "" {
+		websocket.SetResultPublisher(openlifter.NewNotifier(webhookURL, cfg.OpenLifter.DeadLetterFile))
+		logger.Info.Printf("[SetupRouter] OpenLifter results will be pushed to %s", webhookURL)
+	}
+	openLifterController := controllers.NewOpenLifterController(cfg.OpenLifter.APIToken)
 
 	// build the SudoController
-	sudoController := controllers.NewSudoController(occupancyService)
+	sudoController := controllers.NewSudoController(occupancyService, cfg)
 	sudoRoutes := router.Group("/sudo")
 	{
 		// Must be logged in
 		sudoRoutes.Use(middleware.AuthRequired)
 		// Must be superuser
-		sudoRoutes.Use(middleware.SudoRequired())
+		sudoRoutes.Use(middleware.RequirePermission(models.PermManageAllMeets))
 		{
 			sudoRoutes.GET("/", sudoController.SudoPanel)
 			sudoRoutes.POST("/force-vacate-ref", sudoController.ForceVacateRefForAnyMeet)
 			sudoRoutes.POST("/force-logout-meet-director", sudoController.ForceLogoutMeetDirector)
 			sudoRoutes.POST("/restart-meet", sudoController.RestartAndClearMeet)
+
+			sudoRoutes.GET("/meets", sudoController.MeetsPage)
+			sudoRoutes.POST("/meets", sudoController.CreateMeet)
+			sudoRoutes.POST("/meets/update", sudoController.UpdateMeet)
+			sudoRoutes.POST("/meets/archive", sudoController.ArchiveMeet)
+			sudoRoutes.POST("/meets/delete", sudoController.DeleteMeet)
+			sudoRoutes.POST("/meets/accounts", sudoController.SaveMeetAccount)
+			sudoRoutes.POST("/meets/accounts/delete", sudoController.DeleteMeetAccount)
+			sudoRoutes.POST("/meets/password", sudoController.ResetMeetPassword)
 		}
 	}
 
 	// define other controllers
 	positionController := controllers.NewPositionController(occupancyService)
-	adminController := controllers.NewAdminController(occupancyService, positionController)
+	pages := controllers.NewPageController(cfg)
+	adminController := controllers.NewAdminController(occupancyService, positionController, cfg)
 	pc := controllers.NewPositionController(occupancyService)
 
 	// Public routes
@@ -215,20 +269,31 @@ func SetupRouter(env string) *gin.Engine {
 	router.GET("/login", controllers.PerformLogin)
 	router.POST("/login", controllers.LoginHandler)
 	router.GET("/index", controllers.Index)
-	router.GET("/referee/:meetName/:position", func(c *gin.Context) {
-		controllers.RefereeHandler(c, occupancyService)
+	// /referee/<meet>/<position> seats the first platform; other platforms add their ID
+	// before the position. Gin needs the same parameter names on both routes.
+	router.GET("/referee/:meet/:platform", func(c *gin.Context) {
+		pages.RefereeHandler(c, occupancyService)
 	})
+	router.GET("/referee/:meet/:platform/:position", func(c *gin.Context) {
+		pages.RefereeHandler(c, occupancyService)
+	})
+
+	// OpenLifter API (bearer-token authenticated, no session)
+	router.POST("/api/openlifter/current-attempt", openLifterController.SetCurrentAttempt)
+
+	// JSON Schema of the websocket protocol, for client authors
+	router.GET("/protocol/schema.json", controllers.ProtocolSchema)
 
 	// Load templates
 	router.SetHTMLTemplate(template.Must(template.ParseGlob("templates/*.html")))
 
-	// Ensure "meetName" is set (except for a few routes)
+	// Ensure "meetID" is set (except for a few routes)
 	router.Use(func(c *gin.Context) {
 		if c.Request.URL.Path == "/meets" || c.Request.URL.Path == "/login" {
 			return
 		}
 		session := sessions.Default(c)
-		if _, ok := session.Get("meetName").(string); !ok {
+		if _, ok := session.Get("meetID").(string); !ok {
 			c.Redirect(http.StatusFound, "/")
 			c.Abort()
 			return
@@ -240,7 +305,7 @@ func SetupRouter(env string) *gin.Engine {
 	protected.Use(middleware.AuthRequired)
 	protected.Use(func(c *gin.Context) {
 		session := sessions.Default(c)
-		if _, ok := session.Get("meetName").(string); !ok {
+		if _, ok := session.Get("meetID").(string); !ok {
 			c.Redirect(http.StatusFound, "/meets")
 			c.Abort()
 			return
@@ -249,14 +314,13 @@ func SetupRouter(env string) *gin.Engine {
 	})
 	protected.Use(middleware.PositionRequired())
 	{
-		protected.GET("/qrcode", controllers.GetQRCode)
-		protected.GET("/lights", controllers.Lights)
-		protected.GET("/positions", controllers.ShowPositionsPage)
-		protected.POST("/position/claim", pc.ClaimPosition)
-		protected.GET("/left", controllers.Left)
-		protected.GET("/center", controllers.Center)
-		protected.GET("/right", controllers.Right)
+		protected.GET("/qrcode", pages.GetQRCode)
+		protected.GET("/lights", pages.Lights)
+		protected.GET("/positions", pages.ShowPositionsPage)
+		protected.POST("/position/claim", pc.ClaimPosition) // checks the seat kind's permission
+		protected.GET("/seat/:position", pages.Seat)
 		protected.GET("/occupancy", pc.GetOccupancyAPI)
+		protected.POST("/set-platform", controllers.SetPlatformHandler)
 		protected.POST("/position/vacate", pc.VacatePosition)
 
 		// If you restore your /home routes:
@@ -273,23 +337,28 @@ func SetupRouter(env string) *gin.Engine {
 		protected.GET("/logout", func(c *gin.Context) {
 			controllers.Logout(c, occupancyService)
 		})
-		protected.POST("/force-logout", controllers.ForceLogoutHandler)
-		protected.GET("/active-users", controllers.ActiveUsersHandler)
+		protected.POST("/force-logout", middleware.RequirePermission(models.PermForceLogout), controllers.ForceLogoutHandler)
+		protected.GET("/active-users", middleware.RequirePermission(models.PermViewActiveUsers), controllers.ActiveUsersHandler)
 	}
 
 	// Admin routes
 	adminRoutes := router.Group("/admin")
-	adminRoutes.Use(middleware.AdminRequired())
 	{
-		adminRoutes.GET("", adminController.AdminPanel)
-		adminRoutes.POST("/force-vacate", adminController.ForceVacate)
-		adminRoutes.POST("/reset-instance", adminController.ResetInstance)
+		adminRoutes.GET("", middleware.RequirePermission(models.PermViewAdminPanel), adminController.AdminPanel)
+		adminRoutes.POST("/force-vacate", middleware.RequirePermission(models.PermVacateSeats), adminController.ForceVacate)
+		adminRoutes.POST("/reset-instance", middleware.RequirePermission(models.PermResetMeet), adminController.ResetInstance)
+		adminRoutes.GET("/history", middleware.RequirePermission(models.PermViewHistory), historyController.HistoryPage)
+		adminRoutes.GET("/decisions", middleware.RequirePermission(models.PermViewHistory), historyController.DecisionsAPI)
+		adminRoutes.POST("/amend-decision", middleware.RequirePermission(models.PermAmendDecision), historyController.AmendDecision)
+
+		manageTimers := middleware.RequirePermission(models.PermManageTimerProfiles)
+		adminRoutes.POST("/timer-profiles", manageTimers, adminController.SaveTimerProfile)
+		adminRoutes.POST("/timer-profiles/activate", manageTimers, adminController.ActivateTimerProfile)
+		adminRoutes.POST("/timer-profiles/delete", manageTimers, adminController.DeleteTimerProfile)
 	}
 
-	// WebSocket route
-	router.GET("/referee-updates", func(c *gin.Context) {
-		websocket.ServeWs(c.Writer, c.Request)
-	})
+	// WebSocket route (identity comes from the session, not the client)
+	router.GET("/referee-updates", middleware.AuthRequired, webSocketController.Upgrade)
 
 	// Serve static files
 	router.Static("/static", "./static")
@@ -305,3 +374,64 @@ func SetupRouter(env string) *gin.Engine {
 	logger.Debug.Printf("[SetupRouter] Templates Path: %s", templatesDir)
 	return router
 }
+
+// newStateStore picks the runtime state store from state.store ("file", "memory" or "redis").
+// A state file that cannot be opened falls back to memory. A Redis store that cannot be
+// reached is an error: instances that silently kept their state to themselves would
+// disagree about every platform they share.
+func newStateStore(cfg *config.Config) (storage.Store, error) {
+	switch cfg.State.Store {
+	case "memory":
+		logger.Info.Println("[newStateStore] Using in-memory state store; state will not survive a restart")
+		return storage.NewMemoryStore(), nil
+	case "redis":
+		redisStore, err := storage.NewRedisStore(cfg.Redis.URL, cfg.Redis.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open Redis state store: %w", err)
+		}
+		logger.Info.Println("[newStateStore] Sharing runtime state through Redis")
+		return redisStore, nil
+	}
+	fileStore, err := storage.NewFileStore(cfg.State.File)
+	if err != nil {
+		logger.Error.Printf("[newStateStore] Falling back to in-memory state store: %v", err)
+		return storage.NewMemoryStore(), nil
+	}
+	logger.Info.Printf("[newStateStore] Persisting runtime state to %s", cfg.State.File)
+	return fileStore, nil
+}
+
+// newBackplane picks how broadcasts reach other instances from websocket.backplane ("local" or "redis").
+// It returns nil for local delivery, and an error when a configured Redis backplane cannot be reached.
+func newBackplane(cfg *config.Config) (websocket.Backplane, error) {
+	if cfg.WebSocket.Backplane != "redis" {
+		return nil, nil
+	}
+	bp, err := websocket.NewRedisBackplane(cfg.Redis.URL, cfg.Redis.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Redis backplane: %w", err)
+	}
+	logger.Info.Println("[newBackplane] Broadcasting through Redis pub/sub")
+	return bp, nil
+}
+
+// newOriginPolicy builds the WebSocket origin allowlist from the application URL plus
+// websocket.allowed_origins (hosts, "*.example.com" for subdomains).
+// websocket.allow_all_origins accepts every origin, but only outside production.
+func newOriginPolicy(cfg *config.Config) *websocket.OriginPolicy {
+	permissive := cfg.WebSocket.AllowAllOrigins
+	if permissive && cfg.IsProduction() {
+		logger.Warn.Println("[newOriginPolicy] WS_ALLOW_ALL_ORIGINS is ignored in production")
+		permissive = false
+	}
+	if permissive {
+		logger.Warn.Println("[newOriginPolicy] Accepting WebSocket connections from any origin (development only)")
+	}
+
+	policy, err := websocket.NewOriginPolicy(cfg.ApplicationURL, cfg.WebSocket.AllowedOrigins, permissive)
+	if err != nil {
+		logger.Error.Printf("[newOriginPolicy] %v; falling back to the application URL only", err)
+		policy, _ = websocket.NewOriginPolicy(cfg.ApplicationURL, nil, permissive)
+	}
+	return policy
+}
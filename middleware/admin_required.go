@@ -3,33 +3,14 @@
 package middleware
 
 import (
-	"net/http"
-
-	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"go-ref-lights/logger"
+	"go-ref-lights/models"
 )
 
-// AdminRequired is a middleware that restricts access to admin-only routes.
+// AdminRequired restricts routes to roles that may use the meet admin panel.
 // Usage:
 //
 //	router.Use(AdminRequired())
 func AdminRequired() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		session := sessions.Default(c)
-		isAdmin, ok := session.Get("isAdmin").(bool)
-
-		logger.Debug.Printf("[AdminRequired] isAdmin=%v, ok=%v", isAdmin, ok)
-
-		// Block request if user is not an admin
-		if !ok || !isAdmin {
-			logger.Warn.Println("[AdminRequired] Unauthorized attempt blocked")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-			c.Abort() // prevents further execution
-			return
-		}
-
-		logger.Debug.Println("[AdminRequired] Authorized, continuing request")
-		c.Next()
-	}
+	return RequirePermission(models.PermViewAdminPanel)
 }
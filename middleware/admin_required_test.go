@@ -12,6 +12,7 @@ import (
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"go-ref-lights/models"
 )
 
 // Unique function name to avoid conflicts with other test files
@@ -45,13 +46,13 @@ func TestAdminRequired_Success(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 
-	// Setup session and set admin flag
+	// Setup session with a meet director role
 	store := cookie.NewStore([]byte("test-secret"))
 	sessionMiddleware := sessions.Sessions("testsession", store)
 	sessionMiddleware(c)
 
 	session := sessions.Default(c)
-	session.Set("isAdmin", true) // ✅ Admin user
+	session.Set(RoleKey, string(models.RoleMeetDirector)) // ✅ Admin user
 	session.Save()
 
 	// Attach session middleware
@@ -76,13 +77,13 @@ func TestAdminRequired_Unauthorized(t *testing.T) {
 	c, _ := gin.CreateTestContext(w)
 	c.Request = req
 
-	// Setup session but don't set admin flag
+	// Setup session with a role that cannot use the admin panel
 	store := cookie.NewStore([]byte("test-secret"))
 	sessionMiddleware := sessions.Sessions("testsession", store)
 	sessionMiddleware(c)
 
 	session := sessions.Default(c)
-	session.Set("isAdmin", false) // ❌ Not an admin
+	session.Set(RoleKey, string(models.RoleReferee)) // ❌ Not an admin
 	session.Save()
 
 	// Attach session middleware
@@ -92,8 +93,8 @@ func TestAdminRequired_Unauthorized(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	// Validate response
-	assert.Equal(t, http.StatusUnauthorized, w.Code, "Non-admin should be blocked")
-	assert.Contains(t, w.Body.String(), "Unauthorized")
+	assert.Equal(t, http.StatusForbidden, w.Code, "Non-admin should be blocked")
+	assert.Contains(t, w.Body.String(), "Permission required")
 }
 
 // TestAdminRequired_MissingSession ensures missing session results in unauthorized access
@@ -0,0 +1,83 @@
+//go:build unit
+// +build unit
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go-ref-lights/models"
+)
+
+// setupPermissionRouter guards /guarded with perms; /as?role= logs in with a role.
+func setupPermissionRouter(perms ...models.Permission) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(sessions.Sessions("testsession", cookie.NewStore([]byte("test-secret"))))
+
+	router.GET("/as", func(c *gin.Context) {
+		session := sessions.Default(c)
+		session.Set(RoleKey, c.Query("role"))
+		_ = session.Save()
+		c.Status(http.StatusOK)
+	})
+	router.GET("/guarded", RequirePermission(perms...), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return router
+}
+
+// requestAs calls /guarded with a session holding role ("" for no session).
+func requestAs(router *gin.Engine, role models.Role) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, "/guarded", nil)
+	if role != "" {
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest(http.MethodGet, "/as?role="+string(role), nil))
+		for _, c := range login.Result().Cookies() {
+			req.AddCookie(c)
+		}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequirePermission(t *testing.T) {
+	router := setupPermissionRouter(models.PermVacateSeats)
+
+	tests := []struct {
+		role models.Role
+		want int
+	}{
+		{"", http.StatusUnauthorized},
+		{models.RoleViewer, http.StatusForbidden},
+		{models.RoleReferee, http.StatusForbidden},
+		{models.RoleTechnicalController, http.StatusOK},
+		{models.RoleMeetDirector, http.StatusOK},
+		{models.RoleSuperuser, http.StatusOK},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, requestAs(router, tt.role).Code, "role %q", tt.role)
+	}
+}
+
+func TestRequirePermission_NeedsEveryPermission(t *testing.T) {
+	router := setupPermissionRouter(models.PermControlTimer, models.PermResetMeet)
+
+	assert.Equal(t, http.StatusForbidden, requestAs(router, models.RoleTechnicalController).Code,
+		"technical controllers can control the clock but not reset the meet")
+	assert.Equal(t, http.StatusOK, requestAs(router, models.RoleMeetDirector).Code)
+}
+
+func TestSudoRequired_OnlySuperuser(t *testing.T) {
+	router := setupPermissionRouter(models.PermManageAllMeets)
+
+	assert.Equal(t, http.StatusForbidden, requestAs(router, models.RoleMeetDirector).Code)
+	assert.Equal(t, http.StatusOK, requestAs(router, models.RoleSuperuser).Code)
+}
@@ -4,6 +4,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -35,14 +36,9 @@ func PositionRequired() gin.HandlerFunc {
 		// Determine the required position based on the request path
 		path := c.Request.URL.Path
 		var requiredPos string
-		switch path {
-		case "/left":
-			requiredPos = "left"
-		case "/center":
-			requiredPos = "center"
-		case "/right":
-			requiredPos = "right"
-		default:
+		if strings.HasPrefix(path, "/seat/") {
+			requiredPos = strings.TrimPrefix(path, "/seat/")
+		} else {
 			logger.Debug.Printf("[PositionRequired] No specific role required for path: %s", path)
 		}
 
@@ -48,9 +48,9 @@ func setupRoleTestRouter() *gin.Engine {
 	router.Use(PositionRequired())
 
 	// Protected referee routes
-	router.GET("/left", func(c *gin.Context) { c.String(http.StatusOK, "Left Judge") })
-	router.GET("/center", func(c *gin.Context) { c.String(http.StatusOK, "Center Judge") })
-	router.GET("/right", func(c *gin.Context) { c.String(http.StatusOK, "Right Judge") })
+	router.GET("/seat/left", func(c *gin.Context) { c.String(http.StatusOK, "Left Judge") })
+	router.GET("/seat/center", func(c *gin.Context) { c.String(http.StatusOK, "Center Judge") })
+	router.GET("/seat/right", func(c *gin.Context) { c.String(http.StatusOK, "Right Judge") })
 
 	// Route without a required position
 	router.GET("/other", func(c *gin.Context) { c.String(http.StatusOK, "No role required") })
@@ -65,7 +65,7 @@ func TestPositionRequired_Unauthenticated(t *testing.T) {
 		roleRouter = setupRoleTestRouter()
 	}
 
-	req, _ := http.NewRequest("GET", "/left", nil)
+	req, _ := http.NewRequest("GET", "/seat/left", nil)
 	w := httptest.NewRecorder()
 	roleRouter.ServeHTTP(w, req)
 
@@ -115,7 +115,7 @@ func TestPositionRequired_WrongRefPosition(t *testing.T) {
 	assert.NotEmpty(t, sessionCookie, "Session cookie should not be empty")
 
 	// Make request with wrong `refPosition`
-	req, _ := http.NewRequest("GET", "/left", nil)
+	req, _ := http.NewRequest("GET", "/seat/left", nil)
 	req.Header.Set("Cookie", sessionCookie)
 
 	// Set incorrect refPosition in session
@@ -156,7 +156,7 @@ func TestPositionRequired_CorrectRefPosition(t *testing.T) {
 	assert.NotEmpty(t, sessionCookie, "Session cookie should not be empty")
 
 	// Make request with correct `refPosition`
-	req, _ := http.NewRequest("GET", "/center", nil)
+	req, _ := http.NewRequest("GET", "/seat/center", nil)
 	req.Header.Set("Cookie", sessionCookie)
 
 	// Set correct refPosition in session
@@ -2,13 +2,21 @@
 // File: models/meet.go
 package models
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // ----------------------- user model -----------------------
 
-// Admin represents the meet admin user.
+// Admin represents a meet account. Role decides what it may do at the meet;
+// accounts without one fall back on IsAdmin (see AccountRole).
 type Admin struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	IsAdmin  bool   `json:"isadmin"`
+	Role     Role   `json:"role,omitempty"`
 }
 
 //------------------------ superuser model -----------------------
@@ -23,13 +31,138 @@ type Superuser struct {
 
 // ------------------------ meet model -----------------------
 
-// Meet represents a powerlifting meet with associated users.
+// Meet represents a powerlifting meet with associated users. ID keys live state,
+// sessions and history and never changes; Slug is what appears in URLs; Name is
+// for display only.
 type Meet struct {
-	Name            string  `json:"name"`  // Meet name
-	Date            string  `json:"date"`  // Meet date (should use time.Time in production)
-	Admin           Admin   `json:"admin"` // Meet admin user
+	ID              string  `json:"id,omitempty"`   // Stable key, assigned once (see AssignIdentity)
+	Slug            string  `json:"slug,omitempty"` // URL-safe name for referee links and QR codes
+	Name            string  `json:"name"`           // Meet name
+	Date            string  `json:"date"`           // Free-text date, shown for meets without a Schedule
+	Admin           Admin   `json:"admin"`          // Meet admin user
 	SecondaryAdmins []Admin `json:"secondaryAdmins,omitempty"`
 	Logo            string  `json:"logo"` // Meet logo URL
+
+	Schedule  *Schedule  `json:"schedule,omitempty"`  // Dates, sessions and flights
+	Platforms []Platform `json:"platforms,omitempty"` // Lifting platforms; one main platform if empty
+	Positions []Position `json:"positions,omitempty"` // Seats on each platform's panel; three referees if empty
+
+	TimerProfiles      []TimerProfile `json:"timerProfiles,omitempty"`      // Named clock settings for this meet
+	ActiveTimerProfile string         `json:"activeTimerProfile,omitempty"` // Name of the profile in use
+
+	Archived bool `json:"archived,omitempty"` // Hidden from meet selection and closed to logins
+}
+
+// slugPattern is the shape of IDs and slugs: lowercase words joined by hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slugify turns a display name into a URL-safe slug, e.g.
+// "New South Wales State Championships " becomes "new-south-wales-state-championships".
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		default:
+			hyphen = true
+		}
+	}
+	if b.Len() == 0 {
+		return "meet"
+	}
+	return b.String()
+}
+
+// AssignIdentity fills in a missing ID and slug from the meet name. Files written
+// before meets had IDs get the same values on every load, so state keyed by them
+// survives restarts until the editor next saves them.
+func (m *Meet) AssignIdentity() {
+	if m.ID == "" {
+		m.ID = Slugify(m.Name)
+	}
+	if m.Slug == "" {
+		m.Slug = m.ID
+	}
+}
+
+// AssignIdentities calls AssignIdentity on every meet.
+func (c *MeetCreds) AssignIdentities() {
+	for i := range c.Meets {
+		c.Meets[i].AssignIdentity()
+	}
+}
+
+// Account returns the meet account with the given username, primary admin first.
+func (m *Meet) Account(username string) *Admin {
+	if m.Admin.Username == username {
+		return &m.Admin
+	}
+	for i := range m.SecondaryAdmins {
+		if m.SecondaryAdmins[i].Username == username {
+			return &m.SecondaryAdmins[i]
+		}
+	}
+	return nil
+}
+
+// RemoveAccount deletes a secondary account; the primary admin cannot be removed.
+// It reports whether an account was removed.
+func (m *Meet) RemoveAccount(username string) bool {
+	for i, sa := range m.SecondaryAdmins {
+		if sa.Username == username {
+			m.SecondaryAdmins = append(m.SecondaryAdmins[:i], m.SecondaryAdmins[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the meet is named, has a well-formed ID and slug if set, has an admin, that account usernames
+// are unique and roles known, and that any platforms, positions and schedule are valid. Password hashes are checked by the caller.
+func (m Meet) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("meet name is required")
+	}
+	if m.ID != "" && !slugPattern.MatchString(m.ID) {
+		return fmt.Errorf("meet '%s' has an invalid id %q (use lowercase letters, digits and hyphens)", m.Name, m.ID)
+	}
+	if m.Slug != "" && !slugPattern.MatchString(m.Slug) {
+		return fmt.Errorf("meet '%s' has an invalid slug %q (use lowercase letters, digits and hyphens)", m.Name, m.Slug)
+	}
+	if strings.TrimSpace(m.Admin.Username) == "" {
+		return fmt.Errorf("meet '%s' needs an admin username", m.Name)
+	}
+	if err := m.validatePlatforms(); err != nil {
+		return err
+	}
+	if err := m.validatePositions(); err != nil {
+		return err
+	}
+	if m.Schedule != nil {
+		if err := m.Schedule.Validate(); err != nil {
+			return fmt.Errorf("meet '%s' has an invalid schedule: %w", m.Name, err)
+		}
+	}
+	seen := map[string]bool{}
+	for _, account := range append([]Admin{m.Admin}, m.SecondaryAdmins...) {
+		if strings.TrimSpace(account.Username) == "" {
+			return fmt.Errorf("meet '%s' has an account without a username", m.Name)
+		}
+		if seen[account.Username] {
+			return fmt.Errorf("meet '%s' has more than one account named '%s'", m.Name, account.Username)
+		}
+		seen[account.Username] = true
+		if account.Role != "" && (account.Role == RoleSuperuser || !account.Role.Valid()) {
+			return fmt.Errorf("meet '%s' gives user '%s' an invalid role %q", m.Name, account.Username, account.Role)
+		}
+	}
+	return nil
 }
 
 // ---------------------- meet credentials model ----------------------
@@ -121,3 +121,32 @@ func TestMeetCredsJSONSerialization(t *testing.T) {
 	assert.Equal(t, "Nationals", decodedMeetCreds.Meets[0].Name)
 	assert.Equal(t, "2025-04-10", decodedMeetCreds.Meets[1].Date)
 }
+
+// Test: slugs are URL-safe whatever the display name looks like
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "new-south-wales-state-championships", Slugify("New South Wales State Championships "))
+	assert.Equal(t, "dragon-cup-2", Slugify("Dragon Cup 2"))
+	assert.Equal(t, "st-george-s-open", Slugify("  St. George's Open!"))
+	assert.Equal(t, "meet", Slugify("***"))
+}
+
+// Test: missing IDs and slugs are derived from the name; existing ones are kept
+func TestMeetAssignIdentity(t *testing.T) {
+	creds := MeetCreds{Meets: []Meet{
+		{Name: "Cairns Cup "},
+		{ID: "cairns-2024", Name: "Cairns Cup"},
+		{ID: "nsw-2025", Slug: "nsw", Name: "NSW States"},
+	}}
+	creds.AssignIdentities()
+
+	assert.Equal(t, "cairns-cup", creds.Meets[0].ID)
+	assert.Equal(t, "cairns-cup", creds.Meets[0].Slug)
+	assert.Equal(t, "cairns-2024", creds.Meets[1].Slug, "the slug defaults to the ID")
+	assert.Equal(t, "nsw-2025", creds.Meets[2].ID)
+	assert.Equal(t, "nsw", creds.Meets[2].Slug)
+
+	bad := Meet{ID: "Cairns Cup", Name: "Cairns Cup", Admin: Admin{Username: "md"}}
+	assert.ErrorContains(t, bad.Validate(), "invalid id")
+	bad = Meet{Slug: "cairns/cup", Name: "Cairns Cup", Admin: Admin{Username: "md"}}
+	assert.ErrorContains(t, bad.Validate(), "invalid slug")
+}
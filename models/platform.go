@@ -0,0 +1,80 @@
+// Package models defines data structures used across the application.
+// File: models/platform.go
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"go-ref-lights/platform"
+)
+
+// DefaultPlatformID is the single platform of a meet that does not list any.
+const DefaultPlatformID = platform.DefaultID
+
+// ----------------------- platform model -----------------------
+
+// Platform is one lifting platform of a meet, with its own referees, lights and clocks.
+type Platform struct {
+	ID   string `json:"id"`   // Stable key within the meet, used in URLs and state keys
+	Name string `json:"name"` // Shown on the lights and in the panels
+}
+
+// PlatformList returns the meet's platforms, or the one main platform if it lists none.
+func (m Meet) PlatformList() []Platform {
+	if len(m.Platforms) == 0 {
+		return []Platform{{ID: DefaultPlatformID, Name: "Main Platform"}}
+	}
+	return m.Platforms
+}
+
+// DefaultPlatform is the platform used when none is chosen: the first one listed.
+func (m Meet) DefaultPlatform() Platform {
+	return m.PlatformList()[0]
+}
+
+// FindPlatform returns the platform with the given ID; an empty ID means the default.
+func (m Meet) FindPlatform(id string) (Platform, bool) {
+	if id == "" {
+		return m.DefaultPlatform(), true
+	}
+	for _, p := range m.PlatformList() {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Platform{}, false
+}
+
+// PlatformKey is the key the live state of a platform is kept under (see platform.Key).
+func PlatformKey(meetID, platformID string) string {
+	return platform.Key(meetID, platformID)
+}
+
+// SplitPlatformKey returns the meet and platform IDs of a PlatformKey.
+func SplitPlatformKey(key string) (meetID, platformID string) {
+	return platform.SplitKey(key)
+}
+
+// PlatformKey is the key the live state of one of the meet's platforms is kept under.
+func (m Meet) PlatformKey(platformID string) string {
+	return PlatformKey(m.ID, platformID)
+}
+
+// validatePlatforms checks platform IDs are well-formed and unique and every platform is named.
+func (m Meet) validatePlatforms() error {
+	seen := map[string]bool{}
+	for _, p := range m.Platforms {
+		if !slugPattern.MatchString(p.ID) {
+			return fmt.Errorf("meet '%s' has a platform with an invalid id %q (use lowercase letters, digits and hyphens)", m.Name, p.ID)
+		}
+		if seen[p.ID] {
+			return fmt.Errorf("meet '%s' has more than one platform with id %q", m.Name, p.ID)
+		}
+		seen[p.ID] = true
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("meet '%s' has a platform %q without a name", m.Name, p.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// file: models/platform_test.go
+
+//go:build unit
+// +build unit
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeet_PlatformListDefaultsToMain(t *testing.T) {
+	meet := Meet{ID: "spring-open", Name: "Spring Open"}
+
+	assert.Equal(t, []Platform{{ID: DefaultPlatformID, Name: "Main Platform"}}, meet.PlatformList())
+	platform, ok := meet.FindPlatform("")
+	assert.True(t, ok)
+	assert.Equal(t, DefaultPlatformID, platform.ID)
+	assert.Equal(t, "spring-open", meet.PlatformKey(platform.ID), "the main platform keeps the bare meet ID")
+}
+
+func TestMeet_FindPlatform(t *testing.T) {
+	meet := Meet{ID: "nationals", Platforms: []Platform{{ID: "a", Name: "Platform A"}, {ID: "b", Name: "Platform B"}}}
+
+	platform, ok := meet.FindPlatform("")
+	assert.True(t, ok)
+	assert.Equal(t, "a", platform.ID, "no choice means the first platform")
+
+	platform, ok = meet.FindPlatform("b")
+	assert.True(t, ok)
+	assert.Equal(t, "Platform B", platform.Name)
+	assert.Equal(t, "nationals/b", meet.PlatformKey("b"))
+
+	_, ok = meet.FindPlatform(DefaultPlatformID)
+	assert.False(t, ok, "a meet that lists platforms has no main platform")
+}
+
+func TestMeet_ValidatePlatforms(t *testing.T) {
+	for name, platforms := range map[string][]Platform{
+		"bad id":    {{ID: "Platform A", Name: "Platform A"}},
+		"duplicate": {{ID: "a", Name: "Platform A"}, {ID: "a", Name: "Platform A2"}},
+		"no name":   {{ID: "a", Name: " "}},
+	} {
+		meet := Meet{Name: "Nationals", Platforms: platforms}
+		assert.Error(t, meet.validatePlatforms(), name)
+	}
+
+	meet := Meet{Name: "Nationals", Platforms: []Platform{{ID: "a", Name: "Platform A"}, {ID: "b-2", Name: "Platform B"}}}
+	assert.NoError(t, meet.validatePlatforms())
+}
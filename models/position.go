@@ -0,0 +1,127 @@
+// Package models defines data structures used across the application.
+// File: models/position.go
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"go-ref-lights/platform"
+)
+
+// ----------------------- position model -----------------------
+
+// SeatKind says what a seat on the referee panel does.
+type SeatKind string
+
+const (
+	SeatReferee SeatKind = "referee" // votes on every attempt
+	SeatJury    SeatKind = "jury"    // watches the decisions without voting, e.g. jury or technical controller
+)
+
+// Position is one seat on a meet's referee panel.
+type Position struct {
+	ID    string   `json:"id"`              // Stable key, used in URLs, sessions and decisions
+	Name  string   `json:"name"`            // Shown on the seat page and in the panels
+	Kind  SeatKind `json:"kind,omitempty"`  // Defaults to referee
+	Chief bool     `json:"chief,omitempty"` // Starts and controls the platform ready clock
+}
+
+// DefaultPositions is the three-referee panel used by meets that do not list positions.
+var DefaultPositions = []Position{
+	{ID: "left", Name: "Left Referee"},
+	{ID: "center", Name: "Center Referee", Chief: true},
+	{ID: "right", Name: "Right Referee"},
+}
+
+// Votes reports whether the seat's decisions count towards the verdict.
+func (p Position) Votes() bool {
+	return p.Kind == "" || p.Kind == SeatReferee
+}
+
+// PositionList returns the meet's seats, or the default three-referee panel if it lists none.
+func (m Meet) PositionList() []Position {
+	if len(m.Positions) == 0 {
+		return DefaultPositions
+	}
+	return m.Positions
+}
+
+// FindPosition returns the seat with the given ID.
+func (m Meet) FindPosition(id string) (Position, bool) {
+	for _, p := range m.PositionList() {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Position{}, false
+}
+
+// ChiefPosition is the seat that runs the platform ready clock: the one marked chief,
+// otherwise the middle referee seat.
+func (m Meet) ChiefPosition() Position {
+	var referees []Position
+	for _, p := range m.PositionList() {
+		if p.Chief && p.Votes() {
+			return p
+		}
+		if p.Votes() {
+			referees = append(referees, p)
+		}
+	}
+	return referees[len(referees)/2]
+}
+
+// Panel describes the meet's seats to the websocket layer.
+func (m Meet) Panel() platform.Panel {
+	panel := platform.Panel{Chief: m.ChiefPosition().ID}
+	for _, p := range m.PositionList() {
+		if p.Votes() {
+			panel.Referees = append(panel.Referees, p.ID)
+		} else {
+			panel.Jury = append(panel.Jury, p.ID)
+		}
+	}
+	return panel
+}
+
+// validatePositions checks seat IDs are well-formed and unique, every seat is named
+// and of a known kind, and that at least one seat votes and at most one is chief.
+func (m Meet) validatePositions() error {
+	if len(m.Positions) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	referees, chiefs := 0, 0
+	for _, p := range m.Positions {
+		if !slugPattern.MatchString(p.ID) {
+			return fmt.Errorf("meet '%s' has a position with an invalid id %q (use lowercase letters, digits and hyphens)", m.Name, p.ID)
+		}
+		if seen[p.ID] {
+			return fmt.Errorf("meet '%s' has more than one position with id %q", m.Name, p.ID)
+		}
+		seen[p.ID] = true
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("meet '%s' has a position %q without a name", m.Name, p.ID)
+		}
+		if p.Kind != "" && p.Kind != SeatReferee && p.Kind != SeatJury {
+			return fmt.Errorf("meet '%s' gives position %q an unknown kind %q", m.Name, p.ID, p.Kind)
+		}
+		if p.Chief && !p.Votes() {
+			return fmt.Errorf("meet '%s' makes position %q chief, but only referees can be", m.Name, p.ID)
+		}
+		if p.Votes() {
+			referees++
+		}
+		if p.Chief {
+			chiefs++
+		}
+	}
+	if referees == 0 {
+		return fmt.Errorf("meet '%s' needs at least one referee position", m.Name)
+	}
+	if chiefs > 1 {
+		return fmt.Errorf("meet '%s' has more than one chief position", m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// file: models/position_test.go
+
+//go:build unit
+// +build unit
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-ref-lights/platform"
+)
+
+func TestMeet_PositionListDefaultsToThreeReferees(t *testing.T) {
+	meet := Meet{ID: "spring-open", Name: "Spring Open"}
+
+	assert.Equal(t, DefaultPositions, meet.PositionList())
+	assert.Equal(t, "center", meet.ChiefPosition().ID)
+	assert.Equal(t, platform.DefaultPanel, meet.Panel())
+}
+
+func TestMeet_PanelWithJury(t *testing.T) {
+	meet := Meet{ID: "nationals", Positions: []Position{
+		{ID: "referee", Name: "Referee"},
+		{ID: "jury", Name: "Jury", Kind: SeatJury},
+	}}
+
+	position, ok := meet.FindPosition("jury")
+	assert.True(t, ok)
+	assert.False(t, position.Votes())
+	_, ok = meet.FindPosition("left")
+	assert.False(t, ok, "a meet that lists positions has no default seats")
+
+	assert.Equal(t, "referee", meet.ChiefPosition().ID, "the middle referee runs the clock when none is chief")
+	assert.Equal(t, platform.Panel{Referees: []string{"referee"}, Jury: []string{"jury"}, Chief: "referee"}, meet.Panel())
+}
+
+func TestMeet_ValidatePositions(t *testing.T) {
+	for name, positions := range map[string][]Position{
+		"bad id":       {{ID: "Left Ref", Name: "Left"}},
+		"duplicate":    {{ID: "left", Name: "Left"}, {ID: "left", Name: "Left 2"}},
+		"no name":      {{ID: "left", Name: " "}},
+		"unknown kind": {{ID: "left", Name: "Left", Kind: "coach"}},
+		"jury chief":   {{ID: "left", Name: "Left"}, {ID: "jury", Name: "Jury", Kind: SeatJury, Chief: true}},
+		"no referee":   {{ID: "jury", Name: "Jury", Kind: SeatJury}},
+		"two chiefs":   {{ID: "a", Name: "A", Chief: true}, {ID: "b", Name: "B", Chief: true}},
+	} {
+		meet := Meet{Name: "Nationals", Positions: positions}
+		assert.Error(t, meet.validatePositions(), name)
+	}
+
+	meet := Meet{Name: "Nationals", Positions: []Position{
+		{ID: "left", Name: "Left"}, {ID: "head", Name: "Head", Chief: true}, {ID: "tc", Name: "TC", Kind: SeatJury},
+	}}
+	assert.NoError(t, meet.validatePositions())
+}
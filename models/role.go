@@ -0,0 +1,99 @@
+// Package models defines data structures used across the application.
+// File: models/role.go
+package models
+
+// ------------------------ roles -----------------------
+
+// Role names what an account may do at a meet. Roles are set per account in the
+// credentials file and stored in the session at login.
+type Role string
+
+const (
+	RoleSuperuser           Role = "superuser"            // Every permission, across all meets
+	RoleMeetDirector        Role = "meet_director"        // Runs the meet
+	RoleTechnicalController Role = "technical_controller" // Runs the platform: clocks and seats
+	RoleReferee             Role = "referee"              // Judges from a seat
+	RoleJury                Role = "jury"                 // Overturns referee decisions
+	RoleAnnouncer           Role = "announcer"            // Reads results and history
+	RoleViewer              Role = "viewer"               // Watches the lights display only
+)
+
+// ------------------------ permissions -----------------------
+
+// Permission is a single action guarded by the permission matrix.
+type Permission string
+
+const (
+	PermManageAllMeets      Permission = "manage_all_meets"      // Superuser panel
+	PermViewAdminPanel      Permission = "view_admin_panel"      // Meet admin panel
+	PermManageTimerProfiles Permission = "manage_timer_profiles" // Edit and switch timer profiles
+	PermControlTimer        Permission = "control_timer"         // Pause, resume and adjust the clock
+	PermVacateSeats         Permission = "vacate_seats"          // Remove a referee from a seat
+	PermResetMeet           Permission = "reset_meet"            // Clear seats and active users
+	PermForceLogout         Permission = "force_logout"          // Log another user out
+	PermViewActiveUsers     Permission = "view_active_users"     // List logged in users
+	PermViewHistory         Permission = "view_history"          // Decision history and export
+	PermJudge               Permission = "judge"                 // Claim a referee seat
+	PermJurySeat            Permission = "jury_seat"             // Claim a jury seat
+	PermAmendDecision       Permission = "amend_decision"        // Overturn a completed attempt (jury)
+)
+
+// rolePermissions is the permission matrix. Superusers are handled in Can.
+var rolePermissions = map[Role][]Permission{
+	RoleMeetDirector: {
+		PermViewAdminPanel, PermManageTimerProfiles, PermControlTimer, PermVacateSeats,
+		PermResetMeet, PermForceLogout, PermViewActiveUsers, PermViewHistory, PermJudge, PermJurySeat,
+	},
+	RoleTechnicalController: {
+		PermViewAdminPanel, PermControlTimer, PermVacateSeats, PermViewActiveUsers,
+		PermViewHistory, PermJudge, PermJurySeat,
+	},
+	RoleReferee:   {PermJudge},
+	RoleJury:      {PermViewHistory, PermJurySeat, PermAmendDecision},
+	RoleAnnouncer: {PermViewHistory},
+	RoleViewer:    {},
+}
+
+// ClaimPermission is the permission needed to sit in the seat: voting seats need
+// judge, jury seats need jury_seat.
+func (p Position) ClaimPermission() Permission {
+	if p.Votes() {
+		return PermJudge
+	}
+	return PermJurySeat
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	if r == RoleSuperuser {
+		return true
+	}
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission.
+func (r Role) Can(p Permission) bool {
+	if r == RoleSuperuser {
+		return true
+	}
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountRole returns the role configured for a meet account. Accounts written
+// before roles existed fall back on their isadmin flag.
+func (a Admin) AccountRole() Role {
+	switch {
+	case a.Role != "":
+		return a.Role
+	case a.IsAdmin:
+		return RoleMeetDirector
+	default:
+		return RoleReferee
+	}
+}
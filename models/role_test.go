@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Can(t *testing.T) {
+	assert.True(t, RoleSuperuser.Can(PermManageAllMeets))
+	assert.True(t, RoleSuperuser.Can(Permission("anything")))
+
+	assert.True(t, RoleMeetDirector.Can(PermResetMeet))
+	assert.False(t, RoleMeetDirector.Can(PermManageAllMeets))
+
+	assert.True(t, RoleTechnicalController.Can(PermControlTimer))
+	assert.False(t, RoleTechnicalController.Can(PermManageTimerProfiles))
+
+	assert.True(t, RoleReferee.Can(PermJudge))
+	assert.False(t, RoleReferee.Can(PermViewAdminPanel))
+
+	assert.True(t, RoleJury.Can(PermAmendDecision))
+	assert.True(t, RoleJury.Can(PermJurySeat))
+	assert.False(t, RoleJury.Can(PermJudge), "the jury does not vote")
+	assert.False(t, RoleReferee.Can(PermJurySeat))
+	assert.False(t, RoleMeetDirector.Can(PermAmendDecision), "only the jury overturns decisions")
+
+	assert.True(t, RoleAnnouncer.Can(PermViewHistory))
+	assert.False(t, RoleAnnouncer.Can(PermJudge))
+
+	assert.False(t, RoleViewer.Can(PermJudge))
+	assert.False(t, Role("").Can(PermJudge))
+	assert.False(t, Role("judge").Can(PermJudge))
+}
+
+func TestPosition_ClaimPermission(t *testing.T) {
+	assert.Equal(t, PermJudge, Position{ID: "left"}.ClaimPermission())
+	assert.Equal(t, PermJudge, Position{ID: "left", Kind: SeatReferee}.ClaimPermission())
+	assert.Equal(t, PermJurySeat, Position{ID: "jury", Kind: SeatJury}.ClaimPermission())
+
+	jurySeat := Position{ID: "jury", Kind: SeatJury}
+	assert.True(t, RoleJury.Can(jurySeat.ClaimPermission()), "jury accounts take jury seats")
+	assert.False(t, RoleJury.Can(Position{ID: "left"}.ClaimPermission()))
+}
+
+func TestRole_Valid(t *testing.T) {
+	for _, r := range []Role{RoleSuperuser, RoleMeetDirector, RoleTechnicalController, RoleReferee, RoleJury, RoleAnnouncer, RoleViewer} {
+		assert.True(t, r.Valid(), r)
+	}
+	assert.False(t, Role("").Valid())
+	assert.False(t, Role("director").Valid())
+}
+
+func TestAdmin_AccountRole(t *testing.T) {
+	assert.Equal(t, RoleAnnouncer, Admin{Role: RoleAnnouncer, IsAdmin: true}.AccountRole(), "explicit role wins")
+	assert.Equal(t, RoleMeetDirector, Admin{IsAdmin: true}.AccountRole())
+	assert.Equal(t, RoleReferee, Admin{}.AccountRole())
+}
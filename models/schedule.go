@@ -0,0 +1,318 @@
+// Package models defines data structures used across the application.
+// File: models/schedule.go
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	scheduleDateLayout = "2006-01-02"
+	scheduleTimeLayout = "15:04"
+)
+
+// ----------------------- schedule model -----------------------
+
+// Schedule is when a meet runs, in the meet's own time zone: the days it spans
+// and, for each day, the sessions with the flights each platform lifts.
+type Schedule struct {
+	TimeZone  string    `json:"timeZone"`          // IANA zone, e.g. "Australia/Sydney"
+	StartDate string    `json:"startDate"`         // First day, YYYY-MM-DD
+	EndDate   string    `json:"endDate,omitempty"` // Last day, YYYY-MM-DD (defaults to StartDate)
+	Days      []MeetDay `json:"days,omitempty"`    // Session plan; optional
+}
+
+// MeetDay is the session plan for one day of the meet.
+type MeetDay struct {
+	Date     string    `json:"date"` // YYYY-MM-DD, within the meet's dates
+	Sessions []Session `json:"sessions"`
+}
+
+// Session is a block of lifting that starts at a set time, e.g. "Session 1" at 09:00.
+// It runs until the next session starts or the day ends.
+type Session struct {
+	Name      string            `json:"name"`
+	Start     string            `json:"start"` // HH:MM, meet time
+	Platforms []SessionPlatform `json:"platforms,omitempty"`
+}
+
+// SessionPlatform lists the flights one platform lifts in a session, in order.
+type SessionPlatform struct {
+	Name    string   `json:"name"`
+	Flights []Flight `json:"flights,omitempty"`
+}
+
+// Flight is a group of lifters taking their attempts together.
+type Flight struct {
+	Name  string `json:"name"`            // e.g. "Flight A"
+	Start string `json:"start,omitempty"` // HH:MM, meet time; the first flight starts with the session
+}
+
+// MeetStatus places a meet relative to now.
+type MeetStatus string
+
+const (
+	MeetUpcoming MeetStatus = "upcoming"
+	MeetCurrent  MeetStatus = "current"
+	MeetPast     MeetStatus = "past"
+)
+
+// ActiveSession is the session under way, with the flight on each platform.
+type ActiveSession struct {
+	Date    string
+	Session string
+	Flights []ActiveFlight
+}
+
+// ActiveFlight is the flight one platform is lifting.
+type ActiveFlight struct {
+	Platform string
+	Flight   string
+}
+
+// Clone returns a deep copy of the schedule.
+func (s Schedule) Clone() *Schedule {
+	s.Days = append([]MeetDay(nil), s.Days...)
+	for i := range s.Days {
+		day := &s.Days[i]
+		day.Sessions = append([]Session(nil), day.Sessions...)
+		for j := range day.Sessions {
+			session := &day.Sessions[j]
+			session.Platforms = append([]SessionPlatform(nil), session.Platforms...)
+			for k := range session.Platforms {
+				session.Platforms[k].Flights = append([]Flight(nil), session.Platforms[k].Flights...)
+			}
+		}
+	}
+	return &s
+}
+
+// Location returns the meet's time zone.
+func (s Schedule) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil || s.TimeZone == "" {
+		return nil, fmt.Errorf("unknown time zone %q", s.TimeZone)
+	}
+	return loc, nil
+}
+
+// Span returns the start of the first day and the end of the last, in the meet's
+// time zone.
+func (s Schedule) Span() (time.Time, time.Time, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start, err := time.ParseInLocation(scheduleDateLayout, s.StartDate, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date %q is not YYYY-MM-DD", s.StartDate)
+	}
+	last := start
+	if s.EndDate != "" {
+		if last, err = time.ParseInLocation(scheduleDateLayout, s.EndDate, loc); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end date %q is not YYYY-MM-DD", s.EndDate)
+		}
+	}
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", s.EndDate, s.StartDate)
+	}
+	return start, last.AddDate(0, 0, 1), nil
+}
+
+// Status reports whether the meet is upcoming, under way or over at the given time.
+// A schedule that does not validate counts as upcoming, so it is never hidden.
+func (s Schedule) Status(now time.Time) MeetStatus {
+	start, end, err := s.Span()
+	switch {
+	case err != nil || now.Before(start):
+		return MeetUpcoming
+	case now.Before(end):
+		return MeetCurrent
+	default:
+		return MeetPast
+	}
+}
+
+// Validate checks the time zone and dates, that each day falls within the meet,
+// and that sessions and flights are named and in time order.
+func (s Schedule) Validate() error {
+	start, end, err := s.Span()
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, day := range s.Days {
+		date, err := time.ParseInLocation(scheduleDateLayout, day.Date, start.Location())
+		if err != nil {
+			return fmt.Errorf("day %q is not YYYY-MM-DD", day.Date)
+		}
+		if date.Before(start) || !date.Before(end) {
+			return fmt.Errorf("day %s is outside the meet dates", day.Date)
+		}
+		if seen[day.Date] {
+			return fmt.Errorf("day %s is listed more than once", day.Date)
+		}
+		seen[day.Date] = true
+
+		previous := ""
+		for _, session := range day.Sessions {
+			if strings.TrimSpace(session.Name) == "" {
+				return fmt.Errorf("day %s has a session without a name", day.Date)
+			}
+			if err := checkClock(session.Start); err != nil {
+				return fmt.Errorf("session '%s' on %s: %w", session.Name, day.Date, err)
+			}
+			if session.Start < previous {
+				return fmt.Errorf("session '%s' on %s starts before the session ahead of it", session.Name, day.Date)
+			}
+			previous = session.Start
+			for _, platform := range session.Platforms {
+				for _, flight := range platform.Flights {
+					if strings.TrimSpace(flight.Name) == "" {
+						return fmt.Errorf("session '%s' on %s has a flight without a name", session.Name, day.Date)
+					}
+					if flight.Start == "" {
+						continue
+					}
+					if err := checkClock(flight.Start); err != nil {
+						return fmt.Errorf("flight '%s' in session '%s' on %s: %w", flight.Name, session.Name, day.Date, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkClock accepts a 24-hour HH:MM time of day.
+func checkClock(clock string) error {
+	if _, err := time.Parse(scheduleTimeLayout, clock); err != nil || len(clock) != len(scheduleTimeLayout) {
+		return fmt.Errorf("start %q is not HH:MM", clock)
+	}
+	return nil
+}
+
+// Active returns the session under way at the given time: the last one on today's
+// plan that has started. On each platform the flight is the last one whose start
+// time has passed, or the first flight until then.
+func (s Schedule) Active(now time.Time) (ActiveSession, bool) {
+	loc, err := s.Location()
+	if err != nil {
+		return ActiveSession{}, false
+	}
+	local := now.In(loc)
+	date, clock := local.Format(scheduleDateLayout), local.Format(scheduleTimeLayout)
+
+	for _, day := range s.Days {
+		if day.Date != date {
+			continue
+		}
+		var current *Session
+		for i := range day.Sessions {
+			if day.Sessions[i].Start <= clock {
+				current = &day.Sessions[i]
+			}
+		}
+		if current == nil {
+			return ActiveSession{}, false
+		}
+		active := ActiveSession{Date: date, Session: current.Name}
+		for _, platform := range current.Platforms {
+			if len(platform.Flights) == 0 {
+				continue
+			}
+			flight := platform.Flights[0].Name
+			for _, f := range platform.Flights[1:] {
+				if f.Start != "" && f.Start <= clock {
+					flight = f.Name
+				}
+			}
+			active.Flights = append(active.Flights, ActiveFlight{Platform: platform.Name, Flight: flight})
+		}
+		return active, true
+	}
+	return ActiveSession{}, false
+}
+
+// ForPlatform narrows the active flights to the named platform, when the schedule
+// has flights for it; otherwise every platform's flight is kept.
+func (a ActiveSession) ForPlatform(name string) ActiveSession {
+	for _, f := range a.Flights {
+		if f.Platform == name {
+			a.Flights = []ActiveFlight{f}
+			return a
+		}
+	}
+	return a
+}
+
+// Label formats the meet dates for display: "22 Mar 2025", "22–23 Mar 2025" or
+// "30 Mar – 2 Apr 2025".
+func (s Schedule) Label() string {
+	start, end, err := s.Span()
+	if err != nil {
+		return s.StartDate
+	}
+	last := end.AddDate(0, 0, -1)
+	switch {
+	case last.Equal(start):
+		return start.Format("2 Jan 2006")
+	case last.Month() == start.Month() && last.Year() == start.Year():
+		return fmt.Sprintf("%d–%s", start.Day(), last.Format("2 Jan 2006"))
+	case last.Year() == start.Year():
+		return fmt.Sprintf("%s – %s", start.Format("2 Jan"), last.Format("2 Jan 2006"))
+	default:
+		return fmt.Sprintf("%s – %s", start.Format("2 Jan 2006"), last.Format("2 Jan 2006"))
+	}
+}
+
+// ----------------------- meet helpers -----------------------
+
+// DateLabel is the meet's dates for display: from the schedule when it has one,
+// otherwise the free-text Date.
+func (m Meet) DateLabel() string {
+	if m.Schedule != nil {
+		return m.Schedule.Label()
+	}
+	return m.Date
+}
+
+// Status reports where the meet stands at the given time. Meets without a
+// schedule are always upcoming, since nothing says they are over.
+func (m Meet) Status(now time.Time) MeetStatus {
+	if m.Schedule == nil {
+		return MeetUpcoming
+	}
+	return m.Schedule.Status(now)
+}
+
+// UpcomingMeets drops meets that are over and orders the rest by start date,
+// so meets under way come first. Meets without a schedule keep their order
+// after the scheduled ones.
+func UpcomingMeets(meets []Meet, now time.Time) []Meet {
+	kept := make([]Meet, 0, len(meets))
+	starts := make(map[string]time.Time, len(meets))
+	for _, m := range meets {
+		if m.Status(now) == MeetPast {
+			continue
+		}
+		if m.Schedule != nil {
+			if start, _, err := m.Schedule.Span(); err == nil {
+				starts[m.ID] = start
+			}
+		}
+		kept = append(kept, m)
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		a, aok := starts[kept[i].ID]
+		b, bok := starts[kept[j].ID]
+		if aok != bok {
+			return aok
+		}
+		return aok && a.Before(b)
+	})
+	return kept
+}
@@ -0,0 +1,129 @@
+// file: models/schedule_test.go
+
+//go:build unit
+// +build unit
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sydneySchedule is a two-day meet with two sessions on the first day.
+func sydneySchedule() Schedule {
+	return Schedule{
+		TimeZone:  "Australia/Sydney",
+		StartDate: "2025-03-22",
+		EndDate:   "2025-03-23",
+		Days: []MeetDay{{
+			Date: "2025-03-22",
+			Sessions: []Session{
+				{Name: "Session 1", Start: "09:00", Platforms: []SessionPlatform{
+					{Name: "Platform 1", Flights: []Flight{{Name: "Flight A"}, {Name: "Flight B", Start: "10:30"}}},
+					{Name: "Platform 2", Flights: []Flight{{Name: "Flight C"}}},
+				}},
+				{Name: "Session 2", Start: "13:00"},
+			},
+		}},
+	}
+}
+
+func sydneyTime(t *testing.T, value string) time.Time {
+	loc, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	at, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	return at
+}
+
+func TestScheduleValidate(t *testing.T) {
+	assert.NoError(t, sydneySchedule().Validate())
+
+	for name, tc := range map[string]struct {
+		change func(s *Schedule)
+		err    string
+	}{
+		"zone":          {func(s *Schedule) { s.TimeZone = "Mars/Olympus" }, "unknown time zone"},
+		"no zone":       {func(s *Schedule) { s.TimeZone = "" }, "unknown time zone"},
+		"start":         {func(s *Schedule) { s.StartDate = "March 22" }, "start date"},
+		"end first":     {func(s *Schedule) { s.EndDate = "2025-03-21" }, "before start date"},
+		"day outside":   {func(s *Schedule) { s.Days[0].Date = "2025-03-24" }, "outside the meet dates"},
+		"session time":  {func(s *Schedule) { s.Days[0].Sessions[0].Start = "9am" }, "not HH:MM"},
+		"session order": {func(s *Schedule) { s.Days[0].Sessions[1].Start = "08:00" }, "starts before"},
+		"flight name":   {func(s *Schedule) { s.Days[0].Sessions[0].Platforms[0].Flights[0].Name = " " }, "flight without a name"},
+	} {
+		s := sydneySchedule().Clone()
+		tc.change(s)
+		assert.ErrorContains(t, s.Validate(), tc.err, name)
+	}
+}
+
+func TestScheduleStatusAndLabel(t *testing.T) {
+	s := sydneySchedule()
+	assert.Equal(t, MeetUpcoming, s.Status(sydneyTime(t, "2025-03-21 23:59")))
+	assert.Equal(t, MeetCurrent, s.Status(sydneyTime(t, "2025-03-22 00:00")))
+	assert.Equal(t, MeetCurrent, s.Status(sydneyTime(t, "2025-03-23 23:59")))
+	assert.Equal(t, MeetPast, s.Status(sydneyTime(t, "2025-03-24 00:00")))
+	// 13:30 UTC on the 23rd is already the 24th in Sydney
+	assert.Equal(t, MeetPast, s.Status(time.Date(2025, 3, 23, 13, 30, 0, 0, time.UTC)))
+
+	assert.Equal(t, "22–23 Mar 2025", s.Label())
+	s.EndDate = ""
+	assert.Equal(t, "22 Mar 2025", s.Label())
+	s.EndDate = "2025-04-02"
+	assert.Equal(t, "22 Mar – 2 Apr 2025", s.Label())
+}
+
+func TestScheduleActive(t *testing.T) {
+	s := sydneySchedule()
+
+	_, ok := s.Active(sydneyTime(t, "2025-03-22 08:59"))
+	assert.False(t, ok, "nothing before the first session")
+
+	active, ok := s.Active(sydneyTime(t, "2025-03-22 09:15"))
+	require.True(t, ok)
+	assert.Equal(t, "Session 1", active.Session)
+	assert.Equal(t, []ActiveFlight{{"Platform 1", "Flight A"}, {"Platform 2", "Flight C"}}, active.Flights)
+
+	active, _ = s.Active(sydneyTime(t, "2025-03-22 10:30"))
+	assert.Equal(t, "Flight B", active.Flights[0].Flight)
+
+	active, _ = s.Active(sydneyTime(t, "2025-03-22 17:00"))
+	assert.Equal(t, "Session 2", active.Session)
+	assert.Empty(t, active.Flights)
+
+	_, ok = s.Active(sydneyTime(t, "2025-03-23 10:00"))
+	assert.False(t, ok, "no plan for the second day")
+}
+
+func TestUpcomingMeets(t *testing.T) {
+	scheduled := func(id, start string) Meet {
+		return Meet{ID: id, Name: id, Schedule: &Schedule{TimeZone: "UTC", StartDate: start}}
+	}
+	meets := []Meet{
+		{ID: "unscheduled", Name: "Unscheduled", Date: "TBA"},
+		scheduled("later", "2025-06-01"),
+		scheduled("over", "2025-03-01"),
+		scheduled("today", "2025-04-10"),
+		scheduled("soon", "2025-04-20"),
+	}
+
+	var ids []string
+	for _, m := range UpcomingMeets(meets, time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)) {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"today", "soon", "later", "unscheduled"}, ids)
+	assert.Equal(t, "TBA", meets[0].DateLabel())
+	assert.Equal(t, "1 Jun 2025", meets[1].DateLabel())
+}
+
+func TestScheduleClone(t *testing.T) {
+	s := sydneySchedule()
+	copied := s.Clone()
+	copied.Days[0].Sessions[0].Platforms[0].Flights[0].Name = "changed"
+	assert.Equal(t, "Flight A", s.Days[0].Sessions[0].Platforms[0].Flights[0].Name)
+}
@@ -0,0 +1,82 @@
+// Package models defines data structures used across the application.
+// File: models/timer_profile.go
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTimerSeconds caps any single clock at 30 minutes to catch typos like 6000.
+const maxTimerSeconds = 30 * 60
+
+// ------------------------ timer profile model -----------------------
+
+// TimerProfile is a named set of clock durations a meet can switch between,
+// e.g. a federation's standard clocks or a longer clock for a flight of one.
+type TimerProfile struct {
+	Name                      string `json:"name"`                                // Unique within the meet
+	PlatformReadySeconds      int    `json:"platformReadySeconds"`                // Platform ready clock
+	NextAttemptSeconds        int    `json:"nextAttemptSeconds"`                  // Window to submit the next attempt
+	ConsecutiveAttemptSeconds int    `json:"consecutiveAttemptSeconds,omitempty"` // Platform clock when a lifter follows themselves (0 = PlatformReadySeconds)
+	ResultsDisplaySeconds     int    `json:"resultsDisplaySeconds"`               // How long decisions stay on the lights
+}
+
+// Validate checks that the profile is named and every clock is within range.
+func (p TimerProfile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("timer profile name is required")
+	}
+	for label, secs := range map[string]int{
+		"platform ready":  p.PlatformReadySeconds,
+		"next attempt":    p.NextAttemptSeconds,
+		"results display": p.ResultsDisplaySeconds,
+	} {
+		if secs < 1 || secs > maxTimerSeconds {
+			return fmt.Errorf("%s must be between 1 and %d seconds, got %d", label, maxTimerSeconds, secs)
+		}
+	}
+	if p.ConsecutiveAttemptSeconds < 0 || p.ConsecutiveAttemptSeconds > maxTimerSeconds {
+		return fmt.Errorf("consecutive attempt must be between 0 and %d seconds, got %d", maxTimerSeconds, p.ConsecutiveAttemptSeconds)
+	}
+	return nil
+}
+
+// ActiveProfile returns the meet's active timer profile, if it has one.
+func (m Meet) ActiveProfile() (TimerProfile, bool) {
+	if m.ActiveTimerProfile == "" {
+		return TimerProfile{}, false
+	}
+	for _, p := range m.TimerProfiles {
+		if p.Name == m.ActiveTimerProfile {
+			return p, true
+		}
+	}
+	return TimerProfile{}, false
+}
+
+// SetTimerProfile adds the profile, or replaces the existing one with the same name.
+func (m *Meet) SetTimerProfile(p TimerProfile) {
+	for i := range m.TimerProfiles {
+		if m.TimerProfiles[i].Name == p.Name {
+			m.TimerProfiles[i] = p
+			return
+		}
+	}
+	m.TimerProfiles = append(m.TimerProfiles, p)
+}
+
+// RemoveTimerProfile deletes the named profile; removing the active one reverts
+// the meet to the default clocks. It reports whether a profile was removed.
+func (m *Meet) RemoveTimerProfile(name string) bool {
+	for i, p := range m.TimerProfiles {
+		if p.Name == name {
+			m.TimerProfiles = append(m.TimerProfiles[:i], m.TimerProfiles[i+1:]...)
+			if m.ActiveTimerProfile == name {
+				m.ActiveTimerProfile = ""
+			}
+			return true
+		}
+	}
+	return false
+}
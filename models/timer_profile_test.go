@@ -0,0 +1,66 @@
+// file: models/timer_profile_test.go
+
+//go:build unit
+// +build unit
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerProfileValidate(t *testing.T) {
+	valid := TimerProfile{Name: "IPF", PlatformReadySeconds: 60, NextAttemptSeconds: 60, ResultsDisplaySeconds: 15}
+	assert.NoError(t, valid.Validate())
+
+	consecutive := valid
+	consecutive.ConsecutiveAttemptSeconds = 180
+	assert.NoError(t, consecutive.Validate())
+
+	unnamed := valid
+	unnamed.Name = "  "
+	assert.Error(t, unnamed.Validate())
+
+	zeroClock := valid
+	zeroClock.NextAttemptSeconds = 0
+	assert.ErrorContains(t, zeroClock.Validate(), "next attempt")
+
+	tooLong := valid
+	tooLong.PlatformReadySeconds = 6000
+	assert.ErrorContains(t, tooLong.Validate(), "platform ready")
+}
+
+func TestMeetTimerProfiles(t *testing.T) {
+	meet := Meet{Name: "Test Meet"}
+	_, ok := meet.ActiveProfile()
+	assert.False(t, ok, "a meet without profiles uses the defaults")
+
+	meet.SetTimerProfile(TimerProfile{Name: "Standard", PlatformReadySeconds: 60, NextAttemptSeconds: 60, ResultsDisplaySeconds: 15})
+	meet.SetTimerProfile(TimerProfile{Name: "Flight of one", PlatformReadySeconds: 60, NextAttemptSeconds: 60, ConsecutiveAttemptSeconds: 180, ResultsDisplaySeconds: 10})
+	meet.ActiveTimerProfile = "Flight of one"
+
+	active, ok := meet.ActiveProfile()
+	require.True(t, ok)
+	assert.Equal(t, 180, active.ConsecutiveAttemptSeconds)
+
+	// saving under an existing name replaces rather than duplicates
+	meet.SetTimerProfile(TimerProfile{Name: "Standard", PlatformReadySeconds: 90, NextAttemptSeconds: 60, ResultsDisplaySeconds: 15})
+	assert.Len(t, meet.TimerProfiles, 2)
+	assert.Equal(t, 90, meet.TimerProfiles[0].PlatformReadySeconds)
+
+	// removing the active profile falls back to the defaults
+	assert.True(t, meet.RemoveTimerProfile("Flight of one"))
+	assert.False(t, meet.RemoveTimerProfile("Flight of one"))
+	assert.Empty(t, meet.ActiveTimerProfile)
+
+	// profiles round-trip through the credentials file format
+	data, err := json.Marshal(meet)
+	require.NoError(t, err)
+	var decoded Meet
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, meet.TimerProfiles, decoded.TimerProfiles)
+}
@@ -0,0 +1,160 @@
+// Package openlifter pushes completed referee decisions to an OpenLifter-compatible webhook.
+// File: openlifter/notifier.go
+package openlifter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// ------------------------ payload ------------------------
+
+// Result is the JSON body POSTed to the webhook when an attempt has been decided.
+type Result struct {
+	MeetName      string              `json:"meetName"`
+	Platform      string              `json:"platform,omitempty"` // platform the attempt was lifted on
+	LifterID      string              `json:"lifterId"`           // OpenLifter lifter UUID
+	LifterName    string              `json:"lifterName"`         // display name, if supplied
+	Lift          string              `json:"lift"`               // squat, bench or deadlift
+	AttemptNumber int                 `json:"attemptNumber"`      // 1-based attempt number for the lift
+	WeightKg      float64             `json:"weightKg"`           // weight on the bar
+	Verdict       string              `json:"verdict"`            // "good lift" or "no lift"
+	WhiteCount    int                 `json:"whiteCount"`         // number of white decisions
+	RedCount      int                 `json:"redCount"`           // number of red decisions
+	Decisions     map[string]string   `json:"decisions"`          // per-position white/red
+	Cards         map[string][]string `json:"cards"`              // per-position reason cards
+	DecidedAt     time.Time           `json:"decidedAt"`
+}
+
+// ------------------------ notifier ------------------------
+
+// Notifier delivers results to a webhook with retries and writes undeliverable
+// results to a dead-letter file (one JSON object per line) for manual replay.
+type Notifier struct {
+	URL            string        // webhook endpoint
+	DeadLetterPath string        // where undeliverable results are appended
+	MaxAttempts    int           // total delivery attempts per result
+	Backoff        time.Duration // initial delay between attempts, doubled after each failure
+	Client         *http.Client
+
+	deadLetterMu sync.Mutex
+}
+
+// NewNotifier returns a Notifier with sensible defaults for a meet-day network.
+func NewNotifier(url, deadLetterPath string) *Notifier {
+	return &Notifier{
+		URL:            url,
+		DeadLetterPath: deadLetterPath,
+		MaxAttempts:    5,
+		Backoff:        time.Second,
+		Client:         &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Publish delivers the result in the background so the lights are never held up by the webhook.
+func (n *Notifier) Publish(res Result) {
+	go func() {
+		if err := n.Deliver(res); err != nil {
+			logger.Error.Printf("[openlifter.Publish] %v", err)
+		}
+	}()
+}
+
+// Deliver POSTs the result, retrying transient failures. If every attempt fails
+// the result is written to the dead-letter file and an error is returned.
+func (n *Notifier) Deliver(res Result) error {
+	body, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+
+	attempts := n.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := n.Backoff
+
+	var lastErr error
+	for i := 1; i <= attempts; i++ {
+		retry, err := n.post(body)
+		if err == nil {
+			logger.Info.Printf("[openlifter.Deliver] meet=%s lifter=%s %s #%d delivered (try %d)",
+				res.MeetName, res.LifterID, res.Lift, res.AttemptNumber, i)
+			return nil
+		}
+		lastErr = err
+		logger.Warn.Printf("[openlifter.Deliver] meet=%s try %d/%d failed: %v", res.MeetName, i, attempts, err)
+		if !retry {
+			break
+		}
+		if i < attempts && delay > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	if dlErr := n.writeDeadLetter(body); dlErr != nil {
+		return fmt.Errorf("delivery failed (%v) and dead-letter write failed: %w", lastErr, dlErr)
+	}
+	return fmt.Errorf("delivery failed, result written to dead-letter file: %w", lastErr)
+}
+
+// post sends one request. It reports whether a failure is worth retrying.
+func (n *Notifier) post(body []byte) (bool, error) {
+	if n.URL == "" {
+		return false, errors.New("webhook URL is not configured")
+	}
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err // network errors are transient
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// writeDeadLetter appends an undeliverable payload to the dead-letter file.
+func (n *Notifier) writeDeadLetter(body []byte) error {
+	if n.DeadLetterPath == "" {
+		return errors.New("dead-letter path is not configured")
+	}
+
+	n.deadLetterMu.Lock()
+	defer n.deadLetterMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.DeadLetterPath), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(n.DeadLetterPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(body, '\n'))
+	return err
+}
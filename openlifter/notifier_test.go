@@ -0,0 +1,116 @@
+// file: openlifter/notifier_test.go
+//go:build unit
+// +build unit
+
+package openlifter
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResult() Result {
+	return Result{
+		MeetName:      "APL Nationals",
+		LifterID:      "0b8f3c5e-1111-2222-3333-444455556666",
+		Lift:          "squat",
+		AttemptNumber: 2,
+		WeightKg:      200.5,
+		Verdict:       "good lift",
+		WhiteCount:    2,
+		RedCount:      1,
+		Decisions:     map[string]string{"left": "white", "center": "white", "right": "red"},
+	}
+}
+
+func newTestNotifier(t *testing.T, url string) *Notifier {
+	n := NewNotifier(url, filepath.Join(t.TempDir(), "dead_letter.jsonl"))
+	n.Backoff = 0
+	n.MaxAttempts = 3
+	return n
+}
+
+func TestDeliver_Success(t *testing.T) {
+	var received Result
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, server.URL)
+	require.NoError(t, n.Deliver(testResult()))
+	assert.Equal(t, "0b8f3c5e-1111-2222-3333-444455556666", received.LifterID)
+	assert.Equal(t, 2, received.AttemptNumber)
+	assert.Equal(t, "good lift", received.Verdict)
+	assert.Equal(t, 2, received.WhiteCount)
+
+	_, err := os.Stat(n.DeadLetterPath)
+	assert.True(t, os.IsNotExist(err), "No dead letter should be written on success")
+}
+
+func TestDeliver_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, server.URL)
+	require.NoError(t, n.Deliver(testResult()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDeliver_DeadLettersAfterRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, server.URL)
+	err := n.Deliver(testResult())
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	data, readErr := os.ReadFile(n.DeadLetterPath)
+	require.NoError(t, readErr)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	var dead Result
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &dead))
+	assert.Equal(t, "APL Nationals", dead.MeetName)
+}
+
+func TestDeliver_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(t, server.URL)
+	assert.Error(t, n.Deliver(testResult()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err := os.Stat(n.DeadLetterPath)
+	assert.NoError(t, err, "Rejected results should be dead-lettered")
+}
@@ -0,0 +1,36 @@
+// Package platform - platform/panel.go
+// File: platform/panel.go
+package platform
+
+// Panel is the set of seats on a meet's referee panel. Every platform of a meet seats it.
+type Panel struct {
+	Referees []string // seats that vote, in display order
+	Jury     []string // seats that watch the decisions without voting
+	Chief    string   // referee seat that starts and controls the platform ready clock
+}
+
+// DefaultPanel is the three-referee panel, with the center referee running the clock.
+var DefaultPanel = Panel{Referees: []string{"left", "center", "right"}, Chief: "center"}
+
+// IsReferee reports whether position is one of the voting seats.
+func (p Panel) IsReferee(position string) bool {
+	for _, id := range p.Referees {
+		if id == position {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSeat reports whether position is any seat on the panel, voting or not.
+func (p Panel) IsSeat(position string) bool {
+	if p.IsReferee(position) {
+		return true
+	}
+	for _, id := range p.Jury {
+		if id == position {
+			return true
+		}
+	}
+	return false
+}
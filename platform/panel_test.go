@@ -0,0 +1,22 @@
+// file: platform/panel_test.go
+//go:build unit
+// +build unit
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanel_Seats(t *testing.T) {
+	singleReferee := Panel{Referees: []string{"referee"}, Jury: []string{"jury"}, Chief: "referee"}
+	assert.True(t, singleReferee.IsReferee("referee"))
+	assert.False(t, singleReferee.IsReferee("jury"))
+	assert.True(t, singleReferee.IsSeat("jury"))
+	assert.False(t, singleReferee.IsSeat("left"))
+
+	assert.True(t, DefaultPanel.IsReferee("center"))
+	assert.False(t, DefaultPanel.IsSeat("jury"))
+}
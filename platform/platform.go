@@ -0,0 +1,27 @@
+// Package platform names the lifting platforms of a meet and the referee panel seated
+// on each. It is shared by the models and the websocket layer and imports neither.
+// File: platform/platform.go
+package platform
+
+import "strings"
+
+// DefaultID is the platform of a meet that does not list its own.
+const DefaultID = "main"
+
+// Key is the key a platform's live state, connections and broadcasts are kept under.
+// The main platform uses the bare meet ID, so single-platform meets keep the state they
+// saved before meets had platforms; others are "<meetID>/<platformID>".
+func Key(meetID, platformID string) string {
+	if platformID == "" || platformID == DefaultID {
+		return meetID
+	}
+	return meetID + "/" + platformID
+}
+
+// SplitKey returns the meet and platform IDs of a Key.
+func SplitKey(key string) (meetID, platformID string) {
+	if i := strings.IndexByte(key, '/'); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return key, DefaultID
+}
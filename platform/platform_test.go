@@ -0,0 +1,25 @@
+// file: platform/platform_test.go
+//go:build unit
+// +build unit
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey_RoundTrips(t *testing.T) {
+	assert.Equal(t, "spring-open", Key("spring-open", ""))
+	assert.Equal(t, "spring-open", Key("spring-open", DefaultID))
+	assert.Equal(t, "spring-open/b", Key("spring-open", "b"))
+
+	meetID, platformID := SplitKey("spring-open")
+	assert.Equal(t, "spring-open", meetID)
+	assert.Equal(t, DefaultID, platformID)
+
+	meetID, platformID = SplitKey("spring-open/b")
+	assert.Equal(t, "spring-open", meetID)
+	assert.Equal(t, "b", platformID)
+}
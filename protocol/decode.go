@@ -0,0 +1,252 @@
+// Package protocol - protocol/decode.go
+// File: protocol/decode.go
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Error codes sent in "error" messages.
+const (
+	CodeInvalidJSON        = "invalidJson"        // the message is not a JSON object
+	CodeUnknownAction      = "unknownAction"      // the action is not one the client may send
+	CodeInvalidMessage     = "invalidMessage"     // a field is missing, of the wrong type or out of range
+	CodeUnsupportedVersion = "unsupportedVersion" // the connection's protocol version is not spoken
+	CodeRejected           = "rejected"           // the message was understood but not applied
+)
+
+// Error is the "error" message sent back to a client whose message could not be
+// decoded or validated. It is also the error Decode returns.
+type Error struct {
+	Header
+	Code      string `json:"code" enum:"invalidJson,unknownAction,invalidMessage,unsupportedVersion,rejected"`
+	Message   string `json:"message"`
+	Field     string `json:"field,omitempty"`     // offending field, when there is one
+	Requested string `json:"requested,omitempty"` // action of the refused message, when it has one
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// newError returns an "error" message.
+func newError(code, field, format string, args ...interface{}) *Error {
+	return &Error{
+		Header:  Header{Action: ActionError},
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Field:   field,
+	}
+}
+
+// Rejection returns the error sent when a valid message is not applied, such as one
+// for another platform.
+func Rejection(msg ClientMessage, err error) *Error {
+	e := newError(CodeRejected, "", "%v", err)
+	e.Requested = msg.Action
+	e.RequestID = msg.RequestID
+	return e
+}
+
+// invalid returns an invalidMessage error about a field.
+func invalid(field, format string, args ...interface{}) *Error {
+	return newError(CodeInvalidMessage, field, format, args...)
+}
+
+// ClientMessage is every client message flattened into one struct, so the server can
+// dispatch on Action without a type switch. Only the fields of the action's own struct
+// are set by a message that passed Decode.
+type ClientMessage struct {
+	Action         string          `json:"action"`
+	MeetName       string          `json:"meetName"`
+	Platform       string          `json:"platform,omitempty"` // Platform ID; defaults to the connection's
+	JudgeID        string          `json:"judgeId"`
+	Decision       string          `json:"decision"`
+	Cards          []string        `json:"cards,omitempty"`          // Reason cards for a red decision (red, blue, yellow)
+	AttemptID      int64           `json:"attemptId,omitempty"`      // Attempt the message is for
+	RequestID      string          `json:"requestId,omitempty"`      // Client's ID for the message, echoed in the reply
+	CurrentAttempt *AttemptContext `json:"currentAttempt,omitempty"` // Lifter context for "setCurrentAttempt"
+	Seconds        int             `json:"seconds,omitempty"`        // ±N seconds for "adjustTimer"
+	Verdict        string          `json:"verdict,omitempty"`        // Ruling for "amendDecision"
+	Reason         string          `json:"reason,omitempty"`         // Jury's reason for "amendDecision"
+}
+
+// validator is implemented by messages with rules beyond their fields' types and enums.
+type validator interface {
+	Validate() error
+}
+
+// Decode parses and validates a client message under a protocol version. Messages the
+// server cannot act on are refused with an *Error to send back to the client.
+func Decode(version int, data []byte) (ClientMessage, error) {
+	s, ok := versions[version]
+	if !ok {
+		return ClientMessage{}, newError(CodeUnsupportedVersion, "", "protocol version %d is not supported", version)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return ClientMessage{}, newError(CodeInvalidJSON, "", "message is not a JSON object")
+	}
+	// the reply to a refused message carries its action and requestId when it has them
+	var action, requestID string
+	_ = json.Unmarshal(fields["action"], &action)
+	_ = json.Unmarshal(fields["requestId"], &requestID)
+	refuse := func(e *Error) (ClientMessage, error) {
+		e.Requested = action
+		e.RequestID = requestID
+		return ClientMessage{}, e
+	}
+
+	if raw, ok := fields["action"]; ok && json.Unmarshal(raw, new(string)) != nil {
+		return refuse(invalid("action", "action must be a string"))
+	}
+	if action == "" {
+		return refuse(invalid("action", "action is required"))
+	}
+	m, ok := s.clientMessage(action)
+	if !ok {
+		return refuse(newError(CodeUnknownAction, "action", "unknown action %q", action))
+	}
+
+	typed := m.new()
+	err := json.Unmarshal(data, typed)
+	if err == nil {
+		err = checkEnums(reflect.ValueOf(typed).Elem(), "")
+	}
+	if err == nil {
+		if v, ok := typed.(validator); ok {
+			err = v.Validate()
+		}
+	}
+	if err == nil && len(requestID) > maxRequestIDLength {
+		err = invalid("requestId", "requestId must be at most %d characters", maxRequestIDLength)
+	}
+	if err != nil {
+		return refuse(asError(err))
+	}
+
+	var msg ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return refuse(asError(err))
+	}
+	return msg, nil
+}
+
+// clientMessage finds the client message with an action.
+func (s spec) clientMessage(action string) (message, bool) {
+	for _, m := range s.client {
+		if m.action == action {
+			return m, true
+		}
+	}
+	return message{}, false
+}
+
+// asError turns a decoding or validation failure into an "error" message.
+func asError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return invalid(typeErr.Field, "%s must be of type %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	return invalid("", "%v", err)
+}
+
+// checkEnums refuses string fields whose value is not in their enum tag. Empty
+// optional fields are left for the message's own validation.
+func checkEnums(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.Anonymous {
+			if err := checkEnums(value, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		name, omitEmpty := jsonName(field)
+		if name == "" {
+			continue
+		}
+		path := prefix + name
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		allowed := enumValues(field)
+		switch {
+		case value.Kind() == reflect.Struct && value.Type() != timeType:
+			if err := checkEnums(value, path+"."); err != nil {
+				return err
+			}
+		case allowed == nil:
+		case value.Kind() == reflect.String:
+			if value.String() == "" && omitEmpty {
+				continue
+			}
+			if !contains(allowed, value.String()) {
+				return invalid(path, "%s must be one of %s, got %q", path, strings.Join(allowed, ", "), value.String())
+			}
+		case value.Kind() == reflect.Slice:
+			for j := 0; j < value.Len(); j++ {
+				if item := value.Index(j).String(); !contains(allowed, item) {
+					return invalid(path, "%s may only hold %s, got %q", path, strings.Join(allowed, ", "), item)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonName returns a field's JSON name, or "" if it is not encoded, and whether it
+// is left out when empty.
+func jsonName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// enumValues returns the values a field's enum tag allows, or nil if it has none.
+func enumValues(field reflect.StructField) []string {
+	tag := field.Tag.Get("enum")
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// contains reports whether values holds s.
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
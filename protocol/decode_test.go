@@ -0,0 +1,121 @@
+// file: protocol/decode_test.go
+//go:build unit
+// +build unit
+
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode_AcceptsEveryClientAction(t *testing.T) {
+	messages := []string{
+		`{"action":"registerRef","judgeId":"lights","meetName":"cairns-cup"}`,
+		`{"action":"startTimer","meetName":"cairns-cup","attemptId":4}`,
+		`{"action":"resetLights"}`,
+		`{"action":"resetTimer"}`,
+		`{"action":"pauseTimer"}`,
+		`{"action":"resumeTimer"}`,
+		`{"action":"adjustTimer","seconds":-15}`,
+		`{"action":"submitDecision","judgeId":"left","decision":"red","cards":["blue","red"]}`,
+		`{"action":"amendDecision","verdict":"good lift","reason":"depth was fine on video"}`,
+		`{"action":"setCurrentAttempt","currentAttempt":{"lifterId":"abc","lift":"bench","attemptNumber":2,"weightKg":120}}`,
+	}
+	for _, raw := range messages {
+		msg, err := Decode(Version, []byte(raw))
+		if assert.NoError(t, err, raw) {
+			var head Header
+			require.NoError(t, json.Unmarshal([]byte(raw), &head))
+			assert.Equal(t, head.Action, msg.Action)
+		}
+	}
+
+	msg, err := Decode(Version, []byte(`{"action":"submitDecision","judgeId":"left","decision":"red","cards":["blue"],"attemptId":7}`))
+	require.NoError(t, err)
+	assert.Equal(t, "left", msg.JudgeID)
+	assert.Equal(t, []string{"blue"}, msg.Cards)
+	assert.Equal(t, int64(7), msg.AttemptID)
+}
+
+func TestDecode_RefusesInvalidMessages(t *testing.T) {
+	tests := []struct {
+		name, raw, code, field string
+	}{
+		{"not JSON", `{"action":`, CodeInvalidJSON, ""},
+		{"not an object", `[1,2]`, CodeInvalidJSON, ""},
+		{"no action", `{"judgeId":"left"}`, CodeInvalidMessage, "action"},
+		{"unknown action", `{"action":"displayResults"}`, CodeUnknownAction, "action"},
+		{"decision missing", `{"action":"submitDecision","judgeId":"left"}`, CodeInvalidMessage, "decision"},
+		{"judge missing", `{"action":"submitDecision","decision":"white"}`, CodeInvalidMessage, "judgeId"},
+		{"red without a card", `{"action":"submitDecision","judgeId":"left","decision":"red"}`, CodeInvalidMessage, "cards"},
+		{"unknown card", `{"action":"submitDecision","judgeId":"left","decision":"red","cards":["green"]}`, CodeInvalidMessage, "cards"},
+		{"attempt ID of the wrong type", `{"action":"startTimer","attemptId":"4"}`, CodeInvalidMessage, "attemptId"},
+		{"zero adjustment", `{"action":"adjustTimer","seconds":0}`, CodeInvalidMessage, "seconds"},
+		{"unknown verdict", `{"action":"amendDecision","verdict":"maybe","reason":"x"}`, CodeInvalidMessage, "verdict"},
+		{"no reason", `{"action":"amendDecision","verdict":"no lift"}`, CodeInvalidMessage, "reason"},
+		{"no lifter", `{"action":"setCurrentAttempt"}`, CodeInvalidMessage, "currentAttempt"},
+		{"unknown lift", `{"action":"setCurrentAttempt","currentAttempt":{"lifterId":"abc","lift":"clean","attemptNumber":1,"weightKg":100}}`, CodeInvalidMessage, "currentAttempt.lift"},
+		{"bad attempt number", `{"action":"setCurrentAttempt","currentAttempt":{"lifterId":"abc","lift":"squat","attemptNumber":5,"weightKg":100}}`, CodeInvalidMessage, "currentAttempt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(Version, []byte(tt.raw))
+			var e *Error
+			require.True(t, errors.As(err, &e), "Decode returns an *Error, got %v", err)
+			assert.Equal(t, ActionError, e.Action)
+			assert.Equal(t, tt.code, e.Code)
+			assert.Equal(t, tt.field, e.Field)
+			assert.NotEmpty(t, e.Message)
+		})
+	}
+}
+
+func TestDecode_NamesTheRefusedAction(t *testing.T) {
+	_, err := Decode(Version, []byte(`{"action":"adjustTimer"}`))
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, ActionAdjustTimer, e.Requested)
+
+	out, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"error","code":"invalidMessage","field":"seconds","requested":"adjustTimer",
+		"message":"seconds must be a non-zero number of seconds"}`, string(out))
+}
+
+func TestDecode_UnsupportedVersion(t *testing.T) {
+	_, err := Decode(99, []byte(`{"action":"registerRef"}`))
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, CodeUnsupportedVersion, e.Code)
+}
+
+func TestDecode_EchoesRequestID(t *testing.T) {
+	msg, err := Decode(Version, []byte(`{"action":"submitDecision","judgeId":"left","decision":"white","requestId":"left-1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "left-1", msg.RequestID)
+
+	_, err = Decode(Version, []byte(`{"action":"submitDecision","judgeId":"left","decision":"green","requestId":"left-2"}`))
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, "left-2", e.RequestID, "refusals carry the requestId of the refused message")
+
+	long := `{"action":"resetLights","requestId":"` + strings.Repeat("x", maxRequestIDLength+1) + `"}`
+	_, err = Decode(Version, []byte(long))
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, CodeInvalidMessage, e.Code)
+	assert.Equal(t, "requestId", e.Field)
+}
+
+func TestRejection(t *testing.T) {
+	e := Rejection(ClientMessage{Action: ActionStartTimer, RequestID: "center-3"}, errors.New("not now"))
+	out, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"error","code":"rejected","requested":"startTimer","requestId":"center-3",
+		"message":"not now"}`, string(out))
+}
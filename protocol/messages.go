@@ -0,0 +1,379 @@
+// Package protocol - protocol/messages.go
+// File: protocol/messages.go
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// Actions, named as they appear in the "action" field.
+const (
+	ActionRegisterRef             = "registerRef"
+	ActionStartTimer              = "startTimer"
+	ActionResetLights             = "resetLights"
+	ActionResetTimer              = "resetTimer"
+	ActionPauseTimer              = "pauseTimer"
+	ActionResumeTimer             = "resumeTimer"
+	ActionAdjustTimer             = "adjustTimer"
+	ActionSubmitDecision          = "submitDecision"
+	ActionAmendDecision           = "amendDecision"
+	ActionSetCurrentAttempt       = "setCurrentAttempt"
+	ActionOccupancyChanged        = "occupancyChanged"
+	ActionRefereeHealth           = "refereeHealth"
+	ActionUpdatePlatformReadyTime = "updatePlatformReadyTime"
+	ActionPlatformReadyPaused     = "platformReadyPaused"
+	ActionPlatformReadyResumed    = "platformReadyResumed"
+	ActionPlatformReadyExpired    = "platformReadyExpired"
+	ActionUpdateNextAttemptTime   = "updateNextAttemptTime"
+	ActionDecisionsOpen           = "decisionsOpen"
+	ActionCurrentAttempt          = "currentAttempt"
+	ActionJudgeSubmitted          = "judgeSubmitted"
+	ActionDisplayResults          = "displayResults"
+	ActionClearResults            = "clearResults"
+	ActionDecisionAmended         = "decisionAmended"
+	ActionStateSnapshot           = "stateSnapshot"
+	ActionDecisionRejected        = "decisionRejected"
+	ActionTimerActionRejected     = "timerActionRejected"
+	ActionAmendRejected           = "amendRejected"
+	ActionAck                     = "ack"
+	ActionError                   = "error"
+)
+
+// ------------------------ shared types ------------------------
+
+// AttemptContext describes the lifter currently on the platform, as supplied by OpenLifter.
+type AttemptContext struct {
+	LifterID      string  `json:"lifterId"`                         // OpenLifter lifter UUID
+	LifterName    string  `json:"lifterName,omitempty"`             // display name for the lights page
+	Lift          string  `json:"lift" enum:"squat,bench,deadlift"` // squat, bench or deadlift
+	AttemptNumber int     `json:"attemptNumber"`                    // 1-3, or 4 for a record attempt
+	WeightKg      float64 `json:"weightKg"`                         // weight on the bar
+}
+
+// validLifts is the set of lift types accepted in an AttemptContext.
+var validLifts = map[string]bool{"squat": true, "bench": true, "deadlift": true}
+
+// Validate checks that the attempt context is complete and sensible.
+func (a AttemptContext) Validate() error {
+	if a.LifterID == "" {
+		return fmt.Errorf("lifterId is required")
+	}
+	if !validLifts[a.Lift] {
+		return fmt.Errorf("unknown lift %q (expected squat, bench or deadlift)", a.Lift)
+	}
+	if a.AttemptNumber < 1 || a.AttemptNumber > 4 {
+		return fmt.Errorf("attemptNumber must be between 1 and 4, got %d", a.AttemptNumber)
+	}
+	if a.WeightKg <= 0 {
+		return fmt.Errorf("weightKg must be positive, got %v", a.WeightKg)
+	}
+	return nil
+}
+
+// NextAttemptTimer is the countdown a lifter has to submit their next attempt.
+type NextAttemptTimer struct {
+	ID        int       `json:"id"`        // unique ID for the timer
+	AttemptID int64     `json:"attemptId"` // attempt whose result started the timer
+	TimeLeft  int       `json:"timeLeft"`  // time remaining in seconds
+	Active    bool      `json:"active"`    // whether the timer is still counting down
+	EndTime   time.Time `json:"endTime"`   // when the timer runs out
+}
+
+// ------------------------ client messages ------------------------
+
+// RegisterRef announces a connection. Seated referees name their seat; displays may
+// name themselves (e.g. "lights"), but the seat always comes from the session.
+type RegisterRef struct {
+	Header
+	JudgeID string `json:"judgeId,omitempty"`
+}
+
+// StartTimer starts the platform ready clock and opens the next attempt. The server
+// sends it on to every client on the platform.
+type StartTimer struct {
+	Header
+}
+
+// ResetLights clears the lights; the server sends it on to every client on the platform.
+type ResetLights struct {
+	Header
+}
+
+// ResetTimer stops the platform ready clock; the server sends it on to every client on
+// the platform.
+type ResetTimer struct {
+	Header
+}
+
+// PauseTimer stops the platform ready clock without losing the time remaining.
+type PauseTimer struct {
+	Header
+}
+
+// ResumeTimer restarts a paused platform ready clock.
+type ResumeTimer struct {
+	Header
+}
+
+// AdjustTimer adds or removes seconds from the platform ready clock.
+type AdjustTimer struct {
+	Header
+	Seconds int `json:"seconds"` // non-zero; negative takes time off
+}
+
+// Validate checks the adjustment.
+func (m AdjustTimer) Validate() error {
+	if m.Seconds == 0 {
+		return invalid("seconds", "seconds must be a non-zero number of seconds")
+	}
+	return nil
+}
+
+// SubmitDecision is a referee's decision on the attempt under way.
+type SubmitDecision struct {
+	Header
+	JudgeID  string   `json:"judgeId"`
+	Decision string   `json:"decision" enum:"white,red"`
+	Cards    []string `json:"cards,omitempty" enum:"red,blue,yellow"` // reason cards for a red decision
+}
+
+// Validate checks the decision names its seat and gives a reason card with a red light.
+func (m SubmitDecision) Validate() error {
+	if m.JudgeID == "" {
+		return invalid("judgeId", "judgeId is required")
+	}
+	if m.Decision == "red" && len(m.Cards) == 0 {
+		return invalid("cards", "a red decision needs at least one reason card")
+	}
+	return nil
+}
+
+// AmendDecision is a jury ruling overturning the last decision on the platform.
+type AmendDecision struct {
+	Header
+	Verdict string `json:"verdict" enum:"good lift,no lift"`
+	Reason  string `json:"reason"`
+}
+
+// Validate checks the ruling gives a reason.
+func (m AmendDecision) Validate() error {
+	if m.Reason == "" {
+		return invalid("reason", "reason is required")
+	}
+	return nil
+}
+
+// SetCurrentAttempt names the lifter on the platform.
+type SetCurrentAttempt struct {
+	Header
+	CurrentAttempt *AttemptContext `json:"currentAttempt"`
+}
+
+// Validate checks the lifter context is present and complete.
+func (m SetCurrentAttempt) Validate() error {
+	if m.CurrentAttempt == nil {
+		return invalid("currentAttempt", "currentAttempt is required")
+	}
+	if err := m.CurrentAttempt.Validate(); err != nil {
+		return invalid("currentAttempt", "%v", err)
+	}
+	return nil
+}
+
+// ------------------------ server messages ------------------------
+
+// OccupancyChanged lists who holds each seat on a platform.
+type OccupancyChanged struct {
+	Header
+	Seats map[string]string `json:"seats"` // position ID -> occupant; vacant seats are absent
+}
+
+// RefereeHealth lists the seats connected to a platform.
+type RefereeHealth struct {
+	Header
+	ConnectedRefIDs   []string `json:"connectedRefIDs"`
+	ConnectedReferees int      `json:"connectedReferees"` // connected voting seats
+	RequiredReferees  int      `json:"requiredReferees"`  // voting seats on the panel
+}
+
+// UpdatePlatformReadyTime is a tick of the platform ready clock.
+type UpdatePlatformReadyTime struct {
+	Header
+	TimeLeft int `json:"timeLeft"` // seconds remaining
+	Index    int `json:"index"`
+}
+
+// PlatformReadyPaused says the platform ready clock was paused.
+type PlatformReadyPaused struct {
+	Header
+	TimeLeft int `json:"timeLeft"` // seconds remaining when paused
+}
+
+// PlatformReadyResumed says the platform ready clock was resumed.
+type PlatformReadyResumed struct {
+	Header
+	TimeLeft int `json:"timeLeft"` // seconds remaining when resumed
+}
+
+// PlatformReadyExpired says the platform ready clock ran out.
+type PlatformReadyExpired struct {
+	Header
+}
+
+// UpdateNextAttemptTime lists the platform's next attempt timers.
+type UpdateNextAttemptTime struct {
+	Header
+	Timers []NextAttemptTimer `json:"timers"`
+}
+
+// DecisionsOpen says which attempt the referees are deciding; they send its ID back
+// with their decisions.
+type DecisionsOpen struct {
+	Header
+	Source   string `json:"source" enum:"platform,clock,feed,results,restart"` // what opened the attempt
+	ChangeMs int64  `json:"changeMs"`                                          // how long a referee may change a decision
+}
+
+// CurrentAttempt names the lifter on the platform.
+type CurrentAttempt struct {
+	Header
+	CurrentAttempt AttemptContext `json:"currentAttempt"`
+}
+
+// JudgeSubmitted says a referee has decided, without saying what.
+type JudgeSubmitted struct {
+	Header
+	JudgeID string `json:"judgeId"`
+}
+
+// DisplayResults reveals the referees' decisions on an attempt.
+type DisplayResults struct {
+	Header
+	Referees       []string            `json:"referees"`       // voting seats, in display order
+	Decisions      map[string]string   `json:"decisions"`      // seat -> white or red
+	Cards          map[string][]string `json:"cards"`          // seat -> reason cards
+	CurrentAttempt *AttemptContext     `json:"currentAttempt"` // lifter, if a feed named one
+	Verdict        string              `json:"verdict" enum:"good lift,no lift"`
+	WhiteCount     int                 `json:"whiteCount"`
+	RedCount       int                 `json:"redCount"`
+}
+
+// ClearResults clears the lights.
+type ClearResults struct {
+	Header
+}
+
+// StateSnapshot is everything a client needs to draw a platform, sent to one client
+// when it connects and when it registers so it does not wait for the next event. The
+// header's attemptId is the attempt under way.
+type StateSnapshot struct {
+	Header
+	Seats             map[string]string  `json:"seats"`             // position ID -> occupant; vacant seats are absent
+	PlatformReady     PlatformReadyState `json:"platformReady"`     // the platform ready clock
+	NextAttemptTimers []NextAttemptTimer `json:"nextAttemptTimers"` // the platform's next attempt timers
+	Submitted         []string           `json:"submitted"`         // seats that have decided on the attempt, not what
+	CurrentAttempt    *AttemptContext    `json:"currentAttempt"`    // lifter on the platform, if a feed named one
+	LastResult        *DisplayResults    `json:"lastResult"`        // result still on the lights, null once cleared
+}
+
+// PlatformReadyState is the platform ready clock in a snapshot.
+type PlatformReadyState struct {
+	Active   bool `json:"active"`   // the clock is counting down or paused
+	Paused   bool `json:"paused"`   // the clock is stopped mid-countdown
+	TimeLeft int  `json:"timeLeft"` // seconds remaining
+}
+
+// DecisionAmended says the jury overturned a decision.
+type DecisionAmended struct {
+	Header
+	Attempt         int    `json:"attempt"` // sequence number in the decision history
+	OriginalVerdict string `json:"originalVerdict" enum:"good lift,no lift"`
+	Verdict         string `json:"verdict" enum:"good lift,no lift"`
+	JuryMember      string `json:"juryMember"`
+	Reason          string `json:"reason"`
+}
+
+// DecisionRejected tells a referee why their decision was not counted.
+type DecisionRejected struct {
+	Header
+	JudgeID string `json:"judgeId"`
+	Message string `json:"message"`
+}
+
+// TimerActionRejected tells a client why its clock control was not applied.
+type TimerActionRejected struct {
+	Header
+	Requested string `json:"requested"` // action that was refused
+	Message   string `json:"message"`
+}
+
+// AmendRejected tells a jury member why their ruling was not applied.
+type AmendRejected struct {
+	Header
+	Message string `json:"message"`
+}
+
+// Ack tells a client that a message carrying a requestId was applied. A message that
+// is not applied is answered with an error (or the rejection for its action) instead.
+type Ack struct {
+	Header
+	Requested string `json:"requested"` // action of the acknowledged message
+}
+
+// ------------------------ versions ------------------------
+
+// message describes one action a side may send.
+type message struct {
+	action string
+	doc    string
+	new    func() interface{} // returns a pointer to a zero message
+}
+
+// spec lists the messages each side may send in one protocol version.
+type spec struct {
+	client []message
+	server []message
+}
+
+// versions holds every protocol version the server speaks.
+var versions = map[int]spec{
+	1: {
+		client: []message{
+			{ActionRegisterRef, "Announces a connection; sent on every (re)connect.", func() interface{} { return &RegisterRef{} }},
+			{ActionStartTimer, "Starts the platform ready clock and opens the next attempt (chief referee or admin).", func() interface{} { return &StartTimer{} }},
+			{ActionResetLights, "Clears the lights on every client (chief referee or admin).", func() interface{} { return &ResetLights{} }},
+			{ActionResetTimer, "Stops the platform ready clock (chief referee or admin).", func() interface{} { return &ResetTimer{} }},
+			{ActionPauseTimer, "Pauses the platform ready clock (chief referee or admin).", func() interface{} { return &PauseTimer{} }},
+			{ActionResumeTimer, "Resumes a paused platform ready clock (chief referee or admin).", func() interface{} { return &ResumeTimer{} }},
+			{ActionAdjustTimer, "Adds or removes seconds from the platform ready clock (chief referee or admin).", func() interface{} { return &AdjustTimer{} }},
+			{ActionSubmitDecision, "A referee's decision on the attempt under way.", func() interface{} { return &SubmitDecision{} }},
+			{ActionAmendDecision, "A jury ruling overturning the last decision.", func() interface{} { return &AmendDecision{} }},
+			{ActionSetCurrentAttempt, "Names the lifter on the platform and opens the next attempt (admin).", func() interface{} { return &SetCurrentAttempt{} }},
+		},
+		server: []message{
+			{ActionOccupancyChanged, "Who holds each seat on the platform.", func() interface{} { return &OccupancyChanged{} }},
+			{ActionRefereeHealth, "Which seats are connected.", func() interface{} { return &RefereeHealth{} }},
+			{ActionStartTimer, "The platform ready clock was started.", func() interface{} { return &StartTimer{} }},
+			{ActionResetLights, "Clear the lights.", func() interface{} { return &ResetLights{} }},
+			{ActionResetTimer, "The platform ready clock was stopped.", func() interface{} { return &ResetTimer{} }},
+			{ActionUpdatePlatformReadyTime, "A tick of the platform ready clock.", func() interface{} { return &UpdatePlatformReadyTime{} }},
+			{ActionPlatformReadyPaused, "The platform ready clock was paused.", func() interface{} { return &PlatformReadyPaused{} }},
+			{ActionPlatformReadyResumed, "The platform ready clock was resumed.", func() interface{} { return &PlatformReadyResumed{} }},
+			{ActionPlatformReadyExpired, "The platform ready clock ran out.", func() interface{} { return &PlatformReadyExpired{} }},
+			{ActionUpdateNextAttemptTime, "The platform's next attempt timers.", func() interface{} { return &UpdateNextAttemptTime{} }},
+			{ActionDecisionsOpen, "An attempt is open for decisions.", func() interface{} { return &DecisionsOpen{} }},
+			{ActionCurrentAttempt, "The lifter on the platform.", func() interface{} { return &CurrentAttempt{} }},
+			{ActionJudgeSubmitted, "A referee has decided.", func() interface{} { return &JudgeSubmitted{} }},
+			{ActionDisplayResults, "The referees' decisions on an attempt.", func() interface{} { return &DisplayResults{} }},
+			{ActionClearResults, "Clear the result from the lights.", func() interface{} { return &ClearResults{} }},
+			{ActionDecisionAmended, "The jury overturned a decision.", func() interface{} { return &DecisionAmended{} }},
+			{ActionStateSnapshot, "Sent to one client on connecting or registering: the platform as it stands.", func() interface{} { return &StateSnapshot{} }},
+			{ActionDecisionRejected, "Sent to one referee: their decision was not counted.", func() interface{} { return &DecisionRejected{} }},
+			{ActionTimerActionRejected, "Sent to one client: its clock control was not applied.", func() interface{} { return &TimerActionRejected{} }},
+			{ActionAmendRejected, "Sent to one jury member: their ruling was not applied.", func() interface{} { return &AmendRejected{} }},
+			{ActionAck, "Sent to one client: its message with a requestId was applied.", func() interface{} { return &Ack{} }},
+			{ActionError, "Sent to one client: its message was malformed, not understood or not applied.", func() interface{} { return &Error{} }},
+		},
+	},
+}
@@ -0,0 +1,85 @@
+// Package protocol defines the messages exchanged over the /referee-updates websocket:
+// one typed struct per action, the protocol versions the server speaks, validation of
+// client messages and a JSON Schema for client authors.
+// File: protocol/protocol.go
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the protocol version the server speaks by default. Clients that do not
+// ask for a version when they connect get this one.
+const Version = 1
+
+// CloseServedElsewhere is the websocket close code an instance sends, right after the
+// upgrade, to a client whose platform another instance serves. Clients should
+// reconnect promptly: the load balancer may send the next attempt to the right instance.
+const CloseServedElsewhere = 4409
+
+// maxRequestIDLength is the longest requestId a client may send.
+const maxRequestIDLength = 64
+
+// subprotocolPrefix is followed by the version number in the websocket subprotocol
+// names clients offer, e.g. "reflights.v1".
+const subprotocolPrefix = "reflights.v"
+
+// Header is the part every message shares, whichever side sends it.
+type Header struct {
+	Action    string `json:"action"`
+	MeetName  string `json:"meetName,omitempty"`  // meet ID (or platform state key) the message is about
+	Platform  string `json:"platform,omitempty"`  // platform ID; clients may leave it out for their own
+	AttemptID int64  `json:"attemptId,omitempty"` // attempt the message is about; added by the server when left out
+	RequestID string `json:"requestId,omitempty"` // client's ID for a message, echoed in the server's reply to it
+}
+
+// NewHeader returns the header of an action about a meet.
+func NewHeader(action, meetName string) Header {
+	return Header{Action: action, MeetName: meetName}
+}
+
+// SupportedVersions lists the protocol versions the server speaks, newest first.
+func SupportedVersions() []int {
+	supported := make([]int, 0, len(versions))
+	for v := range versions {
+		supported = append(supported, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(supported)))
+	return supported
+}
+
+// Supported reports whether the server speaks a protocol version.
+func Supported(version int) bool {
+	_, ok := versions[version]
+	return ok
+}
+
+// Subprotocol returns the websocket subprotocol name of a version.
+func Subprotocol(version int) string {
+	return subprotocolPrefix + strconv.Itoa(version)
+}
+
+// Negotiate picks the protocol version for a connection from the subprotocols the
+// client offered, preferring the newest the server speaks. A client that offers none
+// gets Version; one that offers only versions the server does not speak is refused.
+func Negotiate(offered []string) (int, error) {
+	if len(offered) == 0 {
+		return Version, nil
+	}
+	for _, version := range SupportedVersions() {
+		for _, name := range offered {
+			if name == Subprotocol(version) {
+				return version, nil
+			}
+		}
+	}
+	names := make([]string, 0, len(versions))
+	for _, version := range SupportedVersions() {
+		names = append(names, Subprotocol(version))
+	}
+	return 0, fmt.Errorf("unsupported protocol %s (the server speaks %s)",
+		strings.Join(offered, ", "), strings.Join(names, ", "))
+}
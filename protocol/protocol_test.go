@@ -0,0 +1,33 @@
+// file: protocol/protocol_test.go
+//go:build unit
+// +build unit
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	version, err := Negotiate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Version, version, "clients that offer nothing get the current version")
+
+	version, err = Negotiate([]string{"chat", Subprotocol(1)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	_, err = Negotiate([]string{"reflights.v99"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reflights.v1", "the refusal names the versions the server speaks")
+}
+
+func TestSupportedVersions(t *testing.T) {
+	assert.Contains(t, SupportedVersions(), Version)
+	assert.True(t, Supported(Version))
+	assert.False(t, Supported(0))
+	assert.Equal(t, "reflights.v1", Subprotocol(1))
+}
@@ -0,0 +1,131 @@
+// Package protocol - protocol/schema.go
+// File: protocol/schema.go
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timeType is encoded as an RFC 3339 string rather than an object.
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema returns a JSON Schema (draft 2020-12) describing every message of a protocol
+// version. Messages a client may send are under #/$defs/ClientMessage and messages
+// the server sends under #/$defs/ServerMessage; each is told apart by its "action".
+func Schema(version int) ([]byte, error) {
+	s, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("protocol version %d is not supported", version)
+	}
+
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+	g.defs["ClientMessage"] = g.oneOf(s.client)
+	g.defs["ServerMessage"] = g.oneOf(s.server)
+
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         Subprotocol(version),
+		"title":       fmt.Sprintf("RefLights websocket protocol, version %d", version),
+		"description": fmt.Sprintf("Offer the %q websocket subprotocol to speak this version.", Subprotocol(version)),
+		"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/$defs/ClientMessage"},
+			map[string]interface{}{"$ref": "#/$defs/ServerMessage"},
+		},
+		"$defs": g.defs,
+	}, "", "  ")
+}
+
+// schemaGenerator builds schemas from message structs, collecting named types in defs.
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// oneOf describes the messages one side may send. Message types sent by both sides
+// are defined once, with the description of whichever side is generated first.
+func (g *schemaGenerator) oneOf(messages []message) map[string]interface{} {
+	refs := make([]interface{}, 0, len(messages))
+	for _, m := range messages {
+		t := reflect.TypeOf(m.new()).Elem()
+		name := t.Name()
+		if _, shared := g.defs[name]; shared {
+			refs = append(refs, map[string]interface{}{"$ref": "#/$defs/" + name})
+			continue
+		}
+		def := g.object(t)
+		def["title"] = m.action
+		def["description"] = m.doc
+		def["properties"].(map[string]interface{})["action"] = map[string]interface{}{"const": m.action}
+		g.defs[name] = def
+		refs = append(refs, map[string]interface{}{"$ref": "#/$defs/" + name})
+	}
+	return map[string]interface{}{"oneOf": refs}
+}
+
+// object describes a struct, flattening embedded structs such as Header.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	g.fields(t, properties, &required)
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// fields adds the schema of each encoded field of t to properties.
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			g.fields(field.Type, properties, required)
+			continue
+		}
+		name, omitEmpty := jsonName(field)
+		if name == "" {
+			continue
+		}
+		schema := g.typeSchema(field.Type)
+		if allowed := enumValues(field); allowed != nil {
+			if field.Type.Kind() == reflect.Slice {
+				schema["items"].(map[string]interface{})["enum"] = allowed
+			} else {
+				schema["enum"] = allowed
+			}
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// typeSchema describes a Go type as JSON. Slices, maps and pointers may be null.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Ptr:
+		return map[string]interface{}{"anyOf": []interface{}{g.typeSchema(t.Elem()), map[string]interface{}{"type": "null"}}}
+	case t.Kind() == reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = g.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case t.Kind() == reflect.Slice:
+		return map[string]interface{}{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]interface{}{"type": []string{"object", "null"}, "additionalProperties": g.typeSchema(t.Elem())}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "integer"}
+	}
+}
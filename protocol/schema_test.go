@@ -0,0 +1,76 @@
+// file: protocol/schema_test.go
+//go:build unit
+// +build unit
+
+package protocol
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaDefs returns the $defs of the schema for a version.
+func schemaDefs(t *testing.T, version int) map[string]map[string]interface{} {
+	raw, err := Schema(version)
+	require.NoError(t, err)
+	var doc struct {
+		Schema string                            `json:"$schema"`
+		Defs   map[string]map[string]interface{} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	assert.True(t, strings.Contains(doc.Schema, "2020-12"))
+	return doc.Defs
+}
+
+// refs returns the definition names a oneOf refers to.
+func refs(def map[string]interface{}) []string {
+	var names []string
+	for _, ref := range def["oneOf"].([]interface{}) {
+		names = append(names, strings.TrimPrefix(ref.(map[string]interface{})["$ref"].(string), "#/$defs/"))
+	}
+	return names
+}
+
+func TestSchema_DescribesEveryMessage(t *testing.T) {
+	defs := schemaDefs(t, Version)
+	s := versions[Version]
+
+	client := refs(defs["ClientMessage"])
+	server := refs(defs["ServerMessage"])
+	assert.Len(t, client, len(s.client))
+	assert.Len(t, server, len(s.server))
+	for _, name := range append(client, server...) {
+		assert.Contains(t, defs, name, "every message has a definition")
+	}
+	assert.Contains(t, server, "Error")
+	assert.Contains(t, server, "StartTimer", "messages both sides send are listed on both")
+}
+
+func TestSchema_MessageDefinition(t *testing.T) {
+	def := schemaDefs(t, Version)["SubmitDecision"]
+	props := def["properties"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{"const": "submitDecision"}, props["action"])
+	assert.Equal(t, []interface{}{"white", "red"}, props["decision"].(map[string]interface{})["enum"])
+	cards := props["cards"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"red", "blue", "yellow"}, cards["items"].(map[string]interface{})["enum"])
+	assert.ElementsMatch(t, []interface{}{"action", "judgeId", "decision"}, def["required"],
+		"fields left out when empty are optional")
+}
+
+func TestSchema_NextAttemptTimerUsesCamelCase(t *testing.T) {
+	props := schemaDefs(t, Version)["NextAttemptTimer"]["properties"].(map[string]interface{})
+	for _, key := range []string{"id", "attemptId", "timeLeft", "active", "endTime"} {
+		assert.Contains(t, props, key)
+	}
+	assert.Equal(t, "date-time", props["endTime"].(map[string]interface{})["format"])
+}
+
+func TestSchema_UnsupportedVersion(t *testing.T) {
+	_, err := Schema(99)
+	assert.Error(t, err)
+}
@@ -11,6 +11,7 @@ from aws_cdk import (
     aws_ecs_patterns as ecs_patterns,
     aws_elasticloadbalancingv2 as elbv2,
     aws_certificatemanager as acm,
+    aws_secretsmanager as secretsmanager,
     Duration,
     Stack,
     RemovalPolicy,
@@ -132,6 +133,16 @@ class RefereeLightsCdkStack(Stack):
             execution_role=execution_role
         )
 
+        # generated cookie-signing secret (the app refuses to start in production without one)
+        session_secret = secretsmanager.Secret(
+            self,
+            "RefereeLightsSessionSecret",
+            generate_secret_string=secretsmanager.SecretStringGenerator(
+                exclude_punctuation=True,
+                password_length=64,
+            ),
+        )
+
         # add container to task definition
         container = task_definition.add_container(
             "RefereeLightsContainer",
@@ -154,6 +165,9 @@ class RefereeLightsCdkStack(Stack):
                 "HOST": "0.0.0.0",
                 "PORT": "8080"
             },
+            secrets={
+                "SESSION_SECRET": ecs.Secret.from_secrets_manager(session_secret),
+            },
             health_check=None,
             # ecs.HealthCheck(
             #     command=["CMD-SHELL", "curl -f http://0.0.0.0:8080/health || exit 1"],
@@ -0,0 +1,339 @@
+// Package services handles the business logic of the application, including the meet registry.
+// File: services/meet_registry.go
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"golang.org/x/crypto/bcrypt"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/models"
+)
+
+// SudoMeetName is the meet picker entry superusers choose; it is not a real meet.
+const SudoMeetName = "Sudo"
+
+// PasswordHashCost is the minimum bcrypt cost accepted for meet accounts.
+const PasswordHashCost = 12
+
+// reloadDebounce coalesces the burst of events an editor or atomic rename produces.
+const reloadDebounce = 250 * time.Millisecond
+
+// ErrMeetNotFound is returned by lookups for a meet the registry does not know.
+var ErrMeetNotFound = errors.New("meet not found")
+
+// ValidPasswordHash accepts bcrypt hashes of at least PasswordHashCost, whether
+// written by hash_creds.py ($2b$) or by the superuser meet editor ($2a$).
+func ValidPasswordHash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost >= PasswordHashCost
+}
+
+// MeetRegistry is the single source of meets: credentials from the creds file and
+// the picker order from the meets file, loaded together, cross-checked, and kept
+// in memory. A failed reload keeps the last good snapshot.
+type MeetRegistry struct {
+	credsPath string
+	meetsPath string
+
+	mu     sync.RWMutex
+	creds  *models.MeetCreds
+	byID   map[string]*models.Meet
+	bySlug map[string]*models.Meet
+	listed []string // meet IDs in picker order
+
+	watcher *fsnotify.Watcher
+}
+
+// NewMeetRegistry loads and validates both files.
+func NewMeetRegistry(credsPath, meetsPath string) (*MeetRegistry, error) {
+	r := &MeetRegistry{credsPath: credsPath, meetsPath: meetsPath}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ------------------------ loading -----------------------
+
+// Reload re-reads both files and swaps them in if they validate. On error the
+// registry keeps serving the previous snapshot.
+func (r *MeetRegistry) Reload() error {
+	var creds models.MeetCreds
+	if err := readJSON(r.credsPath, &creds); err != nil {
+		return err
+	}
+	var listing models.MeetCreds
+	if err := readJSON(r.meetsPath, &listing); err != nil {
+		return err
+	}
+
+	creds.AssignIdentities()
+	index, err := r.validate(&creds, &listing)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.creds, r.byID, r.bySlug, r.listed = &creds, index.byID, index.bySlug, index.listed
+	r.mu.Unlock()
+
+	logger.Info.Printf("[MeetRegistry] Loaded %d meets (%d listed)", len(creds.Meets), len(index.listed))
+	return nil
+}
+
+// readJSON decodes one of the registry files.
+func readJSON(path string, into interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("meet registry: %w", err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("meet registry: failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// registryIndex is what validate builds for the lookups.
+type registryIndex struct {
+	byID   map[string]*models.Meet
+	bySlug map[string]*models.Meet
+	listed []string
+}
+
+// validate checks every meet and cross-reference, reporting all problems at once.
+// IDs and slugs must already be assigned.
+func (r *MeetRegistry) validate(creds, listing *models.MeetCreds) (registryIndex, error) {
+	var problems []error
+	credsFile, meetsFile := filepath.Base(r.credsPath), filepath.Base(r.meetsPath)
+
+	index := registryIndex{
+		byID:   make(map[string]*models.Meet, len(creds.Meets)),
+		bySlug: make(map[string]*models.Meet, len(creds.Meets)),
+	}
+	byName := make(map[string]*models.Meet, len(creds.Meets))
+	for i := range creds.Meets {
+		meet := &creds.Meets[i]
+		if err := meet.Validate(); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", credsFile, err))
+			continue
+		}
+		if meet.Name == SudoMeetName {
+			problems = append(problems, fmt.Errorf("%s: '%s' is reserved for the superuser", credsFile, SudoMeetName))
+			continue
+		}
+		if _, dup := byName[meet.Name]; dup {
+			problems = append(problems, fmt.Errorf("%s: meet '%s' is defined more than once", credsFile, meet.Name))
+			continue
+		}
+		if other, dup := index.byID[meet.ID]; dup {
+			problems = append(problems, fmt.Errorf("%s: meets '%s' and '%s' share the id %q", credsFile, other.Name, meet.Name, meet.ID))
+			continue
+		}
+		if other, dup := index.bySlug[meet.Slug]; dup {
+			problems = append(problems, fmt.Errorf("%s: meets '%s' and '%s' share the slug %q", credsFile, other.Name, meet.Name, meet.Slug))
+			continue
+		}
+		byName[meet.Name] = meet
+		index.byID[meet.ID] = meet
+		index.bySlug[meet.Slug] = meet
+
+		for _, account := range append([]models.Admin{meet.Admin}, meet.SecondaryAdmins...) {
+			if !ValidPasswordHash(account.Password) {
+				problems = append(problems, fmt.Errorf("%s: meet '%s': user '%s' needs a bcrypt password hash of cost %d or more",
+					credsFile, meet.Name, account.Username, PasswordHashCost))
+			}
+		}
+		if err := checkLogo(meet.Logo); err != nil {
+			problems = append(problems, fmt.Errorf("%s: meet '%s': %w", credsFile, meet.Name, err))
+		}
+	}
+	if su := creds.Superuser; su != nil && !ValidPasswordHash(su.Password) {
+		problems = append(problems, fmt.Errorf("%s: superuser '%s' needs a bcrypt password hash of cost %d or more",
+			credsFile, su.Username, PasswordHashCost))
+	}
+
+	for _, entry := range listing.Meets {
+		name := strings.TrimSpace(entry.Name)
+		if name == SudoMeetName {
+			continue // the picker always offers Sudo
+		}
+		// entries written by the meet editor carry the ID; older ones only the name
+		meet, ok := index.byID[entry.ID]
+		if !ok {
+			meet, ok = byName[name]
+		}
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: meet '%s' has no credentials in %s", meetsFile, name, credsFile))
+			continue
+		}
+		if !meet.Archived {
+			index.listed = append(index.listed, meet.ID)
+		}
+	}
+
+	if len(problems) > 0 {
+		return registryIndex{}, fmt.Errorf("meet registry is invalid:\n%w", errors.Join(problems...))
+	}
+	return index, nil
+}
+
+// checkLogo requires local logos to exist, relative to the working directory as the
+// static route serves them; remote URLs are left to the browser.
+func checkLogo(logo string) error {
+	if logo == "" || strings.HasPrefix(logo, "http://") || strings.HasPrefix(logo, "https://") {
+		return nil
+	}
+	if _, err := os.Stat(logo); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(strings.TrimPrefix(logo, "/")); err == nil {
+		return nil // "/static/..." written as a URL path
+	}
+	return fmt.Errorf("logo %q not found", logo)
+}
+
+// ------------------------ lookups -----------------------
+
+// Meet returns a copy of the meet with the given ID, archived meets included.
+func (r *MeetRegistry) Meet(id string) (models.Meet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meet, ok := r.byID[id]
+	if !ok {
+		return models.Meet{}, fmt.Errorf("%w: %q", ErrMeetNotFound, id)
+	}
+	return cloneMeet(*meet), nil
+}
+
+// Resolve finds a meet by ID, slug or display name, in that order. It serves
+// links made before meets had slugs, and callers that only know the name.
+func (r *MeetRegistry) Resolve(ref string) (models.Meet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if meet, ok := r.byID[ref]; ok {
+		return cloneMeet(*meet), nil
+	}
+	if meet, ok := r.bySlug[ref]; ok {
+		return cloneMeet(*meet), nil
+	}
+	name := strings.TrimSpace(ref)
+	for _, meet := range r.byID {
+		if strings.TrimSpace(meet.Name) == name {
+			return cloneMeet(*meet), nil
+		}
+	}
+	return models.Meet{}, fmt.Errorf("%w: %q", ErrMeetNotFound, ref)
+}
+
+// Listed returns the meets offered in the meet picker, in file order.
+func (r *MeetRegistry) Listed() []models.Meet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meets := make([]models.Meet, 0, len(r.listed))
+	for _, id := range r.listed {
+		meets = append(meets, cloneMeet(*r.byID[id]))
+	}
+	return meets
+}
+
+// Creds returns a deep copy of the credentials, safe for read-modify-write.
+func (r *MeetRegistry) Creds() *models.MeetCreds {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	copied := &models.MeetCreds{Meets: make([]models.Meet, 0, len(r.creds.Meets))}
+	for _, m := range r.creds.Meets {
+		copied.Meets = append(copied.Meets, cloneMeet(m))
+	}
+	if r.creds.Superuser != nil {
+		su := *r.creds.Superuser
+		copied.Superuser = &su
+	}
+	return copied
+}
+
+// cloneMeet copies the slices so callers cannot modify the registry.
+func cloneMeet(m models.Meet) models.Meet {
+	m.SecondaryAdmins = append([]models.Admin(nil), m.SecondaryAdmins...)
+	m.TimerProfiles = append([]models.TimerProfile(nil), m.TimerProfiles...)
+	m.Platforms = append([]models.Platform(nil), m.Platforms...)
+	m.Positions = append([]models.Position(nil), m.Positions...)
+	if m.Schedule != nil {
+		m.Schedule = m.Schedule.Clone()
+	}
+	return m
+}
+
+// ------------------------ hot reload -----------------------
+
+// Watch reloads the registry whenever either file changes. The directories are
+// watched rather than the files, so atomic renames and editor swaps are seen.
+func (r *MeetRegistry) Watch() error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("meet registry: failed to start watcher: %w", err)
+	}
+	dirs := map[string]bool{filepath.Dir(r.credsPath): true, filepath.Dir(r.meetsPath): true}
+	for dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			_ = w.Close()
+			return fmt.Errorf("meet registry: failed to watch %s: %w", dir, err)
+		}
+	}
+
+	r.mu.Lock()
+	r.watcher = w
+	r.mu.Unlock()
+	go r.watchLoop(w)
+	return nil
+}
+
+// Close stops watching for changes.
+func (r *MeetRegistry) Close() error {
+	r.mu.Lock()
+	w := r.watcher
+	r.watcher = nil
+	r.mu.Unlock()
+	if w == nil {
+		return nil
+	}
+	return w.Close()
+}
+
+func (r *MeetRegistry) watchLoop(w *fsnotify.Watcher) {
+	watched := map[string]bool{filepath.Clean(r.credsPath): true, filepath.Clean(r.meetsPath): true}
+	var pending *time.Timer
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if !watched[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if pending != nil {
+				pending.Stop()
+			}
+			pending = time.AfterFunc(reloadDebounce, func() {
+				if err := r.Reload(); err != nil {
+					logger.Error.Printf("[MeetRegistry] Reload after file change failed; keeping previous meets: %v", err)
+				}
+			})
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			logger.Warn.Printf("[MeetRegistry] Watcher error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// file: services/meet_registry_test.go
+
+//go:build unit
+// +build unit
+
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/models"
+)
+
+// testHash is a cost-12 bcrypt hash, as hash_creds.py writes them.
+const testHash = "$2b$12$KFKBzEMcKJhuGh6Q7R/GZOBBS4S6EoAWxrML2jv1Zl9Fwf0P0ylVC"
+
+// registryFiles writes a creds and meets file into a temp dir and returns their paths.
+func registryFiles(t *testing.T, creds models.MeetCreds, listing string) (string, string) {
+	dir := t.TempDir()
+	credsPath, meetsPath := filepath.Join(dir, "meet_creds.json"), filepath.Join(dir, "meets.json")
+	writeCreds(t, credsPath, creds)
+	require.NoError(t, os.WriteFile(meetsPath, []byte(listing), 0644))
+	return credsPath, meetsPath
+}
+
+func writeCreds(t *testing.T, path string, creds models.MeetCreds) {
+	data, err := json.Marshal(creds)
+	require.NoError(t, err)
+	// write then rename, as the meet editor does
+	require.NoError(t, os.WriteFile(path+".tmp", data, 0600))
+	require.NoError(t, os.Rename(path+".tmp", path))
+}
+
+func validMeet(name string) models.Meet {
+	return models.Meet{Name: name, Date: "March 22", Admin: models.Admin{Username: name + "_md", Password: testHash, IsAdmin: true}}
+}
+
+func TestMeetRegistry_LoadsAndLooksUp(t *testing.T) {
+	archived := validMeet("Old Meet")
+	archived.Archived = true
+	credsPath, meetsPath := registryFiles(t,
+		models.MeetCreds{Meets: []models.Meet{validMeet("Cairns Cup"), validMeet("Dragon Cup"), archived}},
+		`{"meets":[{"name":"Dragon Cup"},{"name":"Cairns Cup "},{"name":"Old Meet"},{"name":"Sudo"}]}`)
+
+	r, err := NewMeetRegistry(credsPath, meetsPath)
+	require.NoError(t, err)
+
+	meet, err := r.Meet("cairns-cup")
+	require.NoError(t, err)
+	assert.Equal(t, "Cairns Cup_md", meet.Admin.Username)
+	assert.Equal(t, "cairns-cup", meet.Slug, "files without IDs get them from the name")
+
+	_, err = r.Meet("Nope")
+	assert.True(t, errors.Is(err, ErrMeetNotFound))
+
+	var names []string
+	for _, m := range r.Listed() {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"Dragon Cup", "Cairns Cup"}, names, "picker order, archived and Sudo left out")
+
+	// copies cannot change the registry
+	creds := r.Creds()
+	creds.Meets[0].Admin.Username = "changed"
+	meet, _ = r.Meet(creds.Meets[0].ID)
+	assert.NotEqual(t, "changed", meet.Admin.Username)
+}
+
+func TestMeetRegistry_ReportsEveryProblem(t *testing.T) {
+	noHash := validMeet("Plain")
+	noHash.Admin.Password = "plaintext"
+	badLogo := validMeet("Logo Meet")
+	badLogo.Logo = "static/images/missing.png"
+	credsPath, meetsPath := registryFiles(t,
+		models.MeetCreds{Meets: []models.Meet{noHash, badLogo, validMeet("Sudo")}},
+		`{"meets":[{"name":"Ghost Meet"}]}`)
+
+	_, err := NewMeetRegistry(credsPath, meetsPath)
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, "meet 'Plain': user 'Plain_md' needs a bcrypt password hash")
+	assert.Contains(t, msg, `logo "static/images/missing.png" not found`)
+	assert.Contains(t, msg, "'Sudo' is reserved")
+	assert.Contains(t, msg, "meets.json: meet 'Ghost Meet' has no credentials in meet_creds.json")
+}
+
+func TestMeetRegistry_ResolvesIDSlugAndName(t *testing.T) {
+	nsw := validMeet("New South Wales State Championships")
+	nsw.ID, nsw.Slug = "nsw-2025", "nsw"
+	credsPath, meetsPath := registryFiles(t,
+		models.MeetCreds{Meets: []models.Meet{nsw}}, `{"meets":[{"id":"nsw-2025","name":"NSW (renamed in the picker)"}]}`)
+	r, err := NewMeetRegistry(credsPath, meetsPath)
+	require.NoError(t, err)
+	require.Len(t, r.Listed(), 1, "listing entries match by ID first")
+
+	for _, ref := range []string{"nsw-2025", "nsw", "New South Wales State Championships "} {
+		meet, err := r.Resolve(ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, "nsw-2025", meet.ID)
+	}
+	_, err = r.Meet("nsw")
+	assert.True(t, errors.Is(err, ErrMeetNotFound), "Meet only takes IDs")
+	_, err = r.Resolve("qld")
+	assert.True(t, errors.Is(err, ErrMeetNotFound))
+}
+
+func TestMeetRegistry_RejectsDuplicateSlugs(t *testing.T) {
+	first, second := validMeet("Cairns Cup"), validMeet("Cairns Cup!")
+	credsPath, meetsPath := registryFiles(t, models.MeetCreds{Meets: []models.Meet{first, second}}, `{"meets":[]}`)
+	_, err := NewMeetRegistry(credsPath, meetsPath)
+	assert.ErrorContains(t, err, `meets 'Cairns Cup' and 'Cairns Cup!' share the id "cairns-cup"`)
+}
+
+func TestMeetRegistry_ReloadKeepsLastGoodSnapshot(t *testing.T) {
+	credsPath, meetsPath := registryFiles(t,
+		models.MeetCreds{Meets: []models.Meet{validMeet("Cairns Cup")}}, `{"meets":[{"name":"Cairns Cup"}]}`)
+	r, err := NewMeetRegistry(credsPath, meetsPath)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(credsPath, []byte("{not json"), 0600))
+	assert.ErrorContains(t, r.Reload(), "failed to parse")
+	_, err = r.Meet("cairns-cup")
+	assert.NoError(t, err, "the previous meets are still served")
+}
+
+func TestMeetRegistry_WatchReloadsOnChange(t *testing.T) {
+	credsPath, meetsPath := registryFiles(t,
+		models.MeetCreds{Meets: []models.Meet{validMeet("Cairns Cup")}}, `{"meets":[{"name":"Cairns Cup"}]}`)
+	r, err := NewMeetRegistry(credsPath, meetsPath)
+	require.NoError(t, err)
+	require.NoError(t, r.Watch())
+	defer r.Close()
+
+	writeCreds(t, credsPath, models.MeetCreds{Meets: []models.Meet{validMeet("Cairns Cup"), validMeet("Spring Open")}})
+
+	assert.Eventually(t, func() bool {
+		_, err := r.Meet("spring-open")
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestValidPasswordHash(t *testing.T) {
+	assert.True(t, ValidPasswordHash(testHash))
+	assert.False(t, ValidPasswordHash("$2a$10$abcdefghijklmnopqrstuuKXb5d0gpZ3E4tX0l3L2y1y5J8x9z1Wu"))
+	assert.False(t, ValidPasswordHash(""))
+}
@@ -13,24 +13,29 @@ type MockOccupancyService struct {
 }
 
 // GetOccupancy is a mocked function that returns a mock Occupancy struct
-func (m *MockOccupancyService) GetOccupancy(meetName string) Occupancy {
-	args := m.Called(meetName)
+func (m *MockOccupancyService) GetOccupancy(meetID, platformID string) Occupancy {
+	args := m.Called(meetID, platformID)
 	return args.Get(0).(Occupancy)
 }
 
 // SetPosition is a mocked function that returns an error
-func (m *MockOccupancyService) SetPosition(meetName, position, userEmail string) error {
-	args := m.Called(meetName, position, userEmail)
+func (m *MockOccupancyService) SetPosition(meetID, platformID, position, userEmail string) error {
+	args := m.Called(meetID, platformID, position, userEmail)
 	return args.Error(0)
 }
 
 // UnsetPosition removes a user's position from the occupancy service (mocked)
-func (m *MockOccupancyService) UnsetPosition(meetName, position, user string) error {
-	args := m.Called(meetName, position, user)
+func (m *MockOccupancyService) UnsetPosition(meetID, platformID, position, user string) error {
+	args := m.Called(meetID, platformID, position, user)
 	return args.Error(0)
 }
 
+// ResetOccupancyForPlatform is a mocked function that resets the occupancy for one platform
+func (m *MockOccupancyService) ResetOccupancyForPlatform(meetID, platformID string) {
+	m.Called(meetID, platformID)
+}
+
 // ResetOccupancyForMeet is a mocked function that resets the occupancy for a given meet
-func (m *MockOccupancyService) ResetOccupancyForMeet(meetName string) {
-	m.Called(meetName)
+func (m *MockOccupancyService) ResetOccupancyForMeet(meetID string) {
+	m.Called(meetID)
 }
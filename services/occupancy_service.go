@@ -2,37 +2,91 @@
 package services
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"go-ref-lights/logger"
+	"go-ref-lights/models"
+	"go-ref-lights/storage"
 )
 
+// occupancyBucket is the storage bucket holding one Occupancy per platform, keyed by
+// models.PlatformKey.
+const occupancyBucket = "occupancy"
+
 // Global mutex + map remain the same
 var occupancyMutex sync.Mutex
 var occupancyMap = make(map[string]*Occupancy)
 
-// Occupancy holds occupant data for each position plus a timestamp.
+// Occupancy holds the occupant of each seat on a platform plus a timestamp.
 type Occupancy struct {
-	LeftUser    string
-	CenterUser  string
-	RightUser   string
+	Seats       map[string]string // position ID -> occupant; vacant seats are absent
 	LastUpdated time.Time
 }
 
-// OccupancyServiceInterface defines the methods for managing occupancy states.
+// UnmarshalJSON also reads seats saved before positions were configurable, which were
+// stored as LeftUser, CenterUser and RightUser.
+func (o *Occupancy) UnmarshalJSON(data []byte) error {
+	var saved struct {
+		Seats                           map[string]string
+		LeftUser, CenterUser, RightUser string
+		LastUpdated                     time.Time
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	o.Seats, o.LastUpdated = saved.Seats, saved.LastUpdated
+	for position, user := range map[string]string{"left": saved.LeftUser, "center": saved.CenterUser, "right": saved.RightUser} {
+		if user != "" {
+			o.seat(position, user)
+		}
+	}
+	return nil
+}
+
+// UserAt returns the occupant of the given position, or "" if it is vacant or unknown.
+func (o Occupancy) UserAt(position string) string {
+	return o.Seats[position]
+}
+
+// seat puts user in position.
+func (o *Occupancy) seat(position, user string) {
+	if o.Seats == nil {
+		o.Seats = make(map[string]string)
+	}
+	o.Seats[position] = user
+}
+
+// copySeats returns the occupancy with its own copy of the seats, so callers cannot
+// modify the service's map.
+func (o Occupancy) copySeats() Occupancy {
+	seats := make(map[string]string, len(o.Seats))
+	for position, user := range o.Seats {
+		seats[position] = user
+	}
+	o.Seats = seats
+	return o
+}
+
+// OccupancyServiceInterface defines the methods for managing occupancy states. Each
+// platform of a meet has its own seats.
 type OccupancyServiceInterface interface {
-	GetOccupancy(meetName string) Occupancy
-	SetPosition(meetName, position, userEmail string) error
-	UnsetPosition(meetName, position, userEmail string) error
-	ResetOccupancyForMeet(meetName string)
+	GetOccupancy(meetID, platformID string) Occupancy
+	SetPosition(meetID, platformID, position, userEmail string) error
+	UnsetPosition(meetID, platformID, position, userEmail string) error
+	ResetOccupancyForPlatform(meetID, platformID string)
+	ResetOccupancyForMeet(meetID string)
 }
 
 // OccupancyService is a concrete implementation of OccupancyServiceInterface.
 type OccupancyService struct {
 	mu        sync.Mutex
 	occupancy map[string]*Occupancy
+	store     storage.Store                         // optional; seats are persisted here so they survive a restart
+	positions func(meetID string) []models.Position // the meet's seats; nil means models.DefaultPositions
 }
 
 // NewOccupancyService returns a pointer to a new OccupancyService.
@@ -42,147 +96,253 @@ func NewOccupancyService() *OccupancyService {
 	}
 }
 
-// GetOccupancy retrieves the occupancy state for a given meetName,
-// creating a new empty Occupancy if it doesn’t exist yet.
-func (s *OccupancyService) GetOccupancy(meetName string) Occupancy {
+// NewOccupancyServiceWithStore returns an OccupancyService that keeps its seats in
+// store, after restoring the seats previously saved there. Every instance sharing the
+// store reads the seats from it and claims them atomically there, so two instances
+// cannot seat different users in one position.
+func NewOccupancyServiceWithStore(store storage.Store) (*OccupancyService, error) {
+	s := NewOccupancyService()
+	s.store = store
+	if err := s.restore(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetPositionLookup wires where each meet's seats come from (the meet configuration).
+// Until it is called every meet has the default three-referee panel.
+func (s *OccupancyService) SetPositionLookup(lookup func(meetID string) []models.Position) {
+	s.positions = lookup
+}
+
+// positionsFor returns the seats of a meet.
+func (s *OccupancyService) positionsFor(meetID string) []models.Position {
+	if s.positions == nil {
+		return models.DefaultPositions
+	}
+	return s.positions(meetID)
+}
+
+// restore loads saved seat assignments into the occupancy map, which serves reads
+// while the store cannot be reached.
+func (s *OccupancyService) restore() error {
+	saved, err := s.store.List(occupancyBucket)
+	if err != nil {
+		return fmt.Errorf("failed to load saved occupancy: %w", err)
+	}
+
 	occupancyMutex.Lock()
 	defer occupancyMutex.Unlock()
+	for meetName, raw := range saved {
+		var occ Occupancy
+		if err := json.Unmarshal(raw, &occ); err != nil {
+			logger.Warn.Printf("[restore] Skipping unreadable occupancy for meet=%s: %v", meetName, err)
+			continue
+		}
+		occupancyMap[meetName] = &occ
+		logger.Info.Printf("[restore] Restored occupancy for meet=%s: %+v", meetName, occ)
+	}
+	return nil
+}
 
+// load reads a platform's occupancy from the store into the occupancy map. If the
+// store cannot be read the map keeps what it last saw. Caller must hold occupancyMutex.
+func (s *OccupancyService) load(meetName string) *Occupancy {
+	if s.store != nil {
+		raw, err := s.store.Get(occupancyBucket, meetName)
+		switch {
+		case err != nil:
+			logger.Warn.Printf("[load] Using last known occupancy for meet=%s: %v", meetName, err)
+		case raw == nil:
+			occupancyMap[meetName] = &Occupancy{}
+		default:
+			var occ Occupancy
+			if err := json.Unmarshal(raw, &occ); err != nil {
+				logger.Warn.Printf("[load] Ignoring unreadable occupancy for meet=%s: %v", meetName, err)
+				break
+			}
+			occupancyMap[meetName] = &occ
+		}
+	}
 	occ, exists := occupancyMap[meetName]
 	if !exists {
 		occ = &Occupancy{}
 		occupancyMap[meetName] = occ
 	}
+	return occ
+}
+
+// change applies a seat change to a platform's occupancy. With a store the change is
+// made there, atomically with every other instance's changes, and the occupancy map
+// mirrors the result. apply must check before it modifies, as a refused change is
+// not saved. Caller must hold occupancyMutex.
+func (s *OccupancyService) change(meetName string, apply func(occ *Occupancy) error) error {
+	if s.store == nil {
+		occ, exists := occupancyMap[meetName]
+		if !exists {
+			occ = &Occupancy{}
+			occupancyMap[meetName] = occ
+		}
+		return apply(occ)
+	}
+
+	var updated Occupancy
+	err := s.store.Update(occupancyBucket, meetName, func(current json.RawMessage) (interface{}, error) {
+		updated = Occupancy{}
+		if current != nil {
+			if err := json.Unmarshal(current, &updated); err != nil {
+				return nil, fmt.Errorf("failed to read saved occupancy: %w", err)
+			}
+		}
+		if err := apply(&updated); err != nil {
+			return nil, err
+		}
+		return updated, nil
+	})
+	if err != nil {
+		return err
+	}
+	occupancyMap[meetName] = &updated
+	return nil
+}
+
+// GetOccupancy retrieves the occupancy state for a platform,
+// creating a new empty Occupancy if it doesn’t exist yet.
+func (s *OccupancyService) GetOccupancy(meetID, platformID string) Occupancy {
+	meetName := models.PlatformKey(meetID, platformID)
+	occupancyMutex.Lock()
+	defer occupancyMutex.Unlock()
+
+	occ := s.load(meetName)
 	logger.Debug.Printf("[GetOccupancy] meet=%s -> %+v", meetName, occ)
-	return *occ
+	return occ.copySeats()
 }
 
 // SetPosition seats a user at a given position, allowing them to re-enter the seat if they’re already occupant.
-func (s *OccupancyService) SetPosition(meetName, position, userEmail string) error {
+func (s *OccupancyService) SetPosition(meetID, platformID, position, userEmail string) error {
+	meetName := models.PlatformKey(meetID, platformID)
 	occupancyMutex.Lock()
 	defer occupancyMutex.Unlock()
 
-	occ, exists := occupancyMap[meetName]
-	if !exists {
-		occ = &Occupancy{}
-		occupancyMap[meetName] = occ
-	}
-
 	logger.Info.Printf("[SetPosition] Attempting to assign position=%s to user=%s for meet=%s", position, userEmail, meetName)
 
-	// Validate position
-	validPositions := map[string]bool{"left": true, "center": true, "right": true}
-	if !validPositions[position] {
-		err := errors.New("invalid position selected, please choose left, center, or right")
+	// Validate position against the meet's panel
+	valid := false
+	for _, p := range s.positionsFor(meetID) {
+		valid = valid || p.ID == position
+	}
+	if !valid {
+		err := fmt.Errorf("invalid position %q selected for this meet", position)
 		logger.Error.Printf("[SetPosition] Failed for meet=%s: %v", meetName, err)
 		return err
 	}
 
-	// If occupant is "", or occupant == userEmail => allow
-	// If occupant is another user => error
-	switch position {
-	case "left":
-		if occ.LeftUser != "" && occ.LeftUser != userEmail {
-			err := errors.New("left position is already taken")
-			logger.Error.Printf("[SetPosition] Failed for meet=%s: %v", meetName, err)
-			return err
-		}
-	case "center":
-		if occ.CenterUser != "" && occ.CenterUser != userEmail {
-			err := errors.New("center position is already taken")
-			logger.Error.Printf("[SetPosition] Failed for meet=%s: %v", meetName, err)
-			return err
+	err := s.change(meetName, func(occ *Occupancy) error {
+		// If occupant is "", or occupant == userEmail => allow
+		// If occupant is another user => error
+		if occupant := occ.Seats[position]; occupant != "" && occupant != userEmail {
+			return fmt.Errorf("%s position is already taken", position)
 		}
-	case "right":
-		if occ.RightUser != "" && occ.RightUser != userEmail {
-			err := errors.New("right position is already taken")
-			logger.Error.Printf("[SetPosition] Failed for meet=%s: %v", meetName, err)
-			return err
-		}
-	}
 
-	// Remove the user from other positions if they're currently seated
-	if occ.LeftUser == userEmail {
-		occ.LeftUser = ""
-	}
-	if occ.CenterUser == userEmail {
-		occ.CenterUser = ""
-	}
-	if occ.RightUser == userEmail {
-		occ.RightUser = ""
-	}
+		// Remove the user from other positions if they're currently seated
+		for seat, occupant := range occ.Seats {
+			if occupant == userEmail {
+				delete(occ.Seats, seat)
+			}
+		}
 
-	// Now seat them in the chosen position
-	switch position {
-	case "left":
-		occ.LeftUser = userEmail
-	case "center":
-		occ.CenterUser = userEmail
-	case "right":
-		occ.RightUser = userEmail
+		// Now seat them in the chosen position
+		occ.seat(position, userEmail)
+		occ.LastUpdated = time.Now()
+		return nil
+	})
+	if err != nil {
+		logger.Error.Printf("[SetPosition] Failed for meet=%s: %v", meetName, err)
+		return err
 	}
 
-	// Touch activity to update LastUpdated
-	s.TouchActivity(meetName)
 	logger.Info.Printf("[SetPosition] Position=%s assigned to user=%s for meet=%s. Current occupancy: %+v",
-		position, userEmail, meetName, occ)
+		position, userEmail, meetName, occupancyMap[meetName])
 	return nil
 }
 
 // UnsetPosition removes the occupant from a specified position (if the occupant matches userEmail).
-func (s *OccupancyService) UnsetPosition(meetName, position, userEmail string) error {
+func (s *OccupancyService) UnsetPosition(meetID, platformID, position, userEmail string) error {
+	meetName := models.PlatformKey(meetID, platformID)
 	occupancyMutex.Lock()
 	defer occupancyMutex.Unlock()
 
-	occ, exists := occupancyMap[meetName]
-	if !exists {
+	if _, exists := occupancyMap[meetName]; !exists && s.store == nil {
 		logger.Warn.Printf("[UnsetPosition] No occupancy record for meet=%s", meetName)
 		return errors.New("no occupancy found for that meet")
 	}
 
-	switch position {
-	case "left":
-		if occ.LeftUser == userEmail {
-			logger.Info.Printf("[UnsetPosition] Clearing left position for user=%s in meet=%s", userEmail, meetName)
-			occ.LeftUser = ""
-		} else {
+	err := s.change(meetName, func(occ *Occupancy) error {
+		occupant, seated := occ.Seats[position]
+		if !seated || occupant != userEmail {
 			return errors.New("user does not hold this position")
 		}
-	case "center":
-		if occ.CenterUser == userEmail {
-			logger.Info.Printf("[UnsetPosition] Clearing center position for user=%s in meet=%s", userEmail, meetName)
-			occ.CenterUser = ""
-		} else {
-			return errors.New("user does not hold this position")
-		}
-	case "right":
-		if occ.RightUser == userEmail {
-			logger.Info.Printf("[UnsetPosition] Clearing right position for user=%s in meet=%s", userEmail, meetName)
-			occ.RightUser = ""
-		} else {
-			return errors.New("user does not hold this position")
-		}
-	default:
-		err := errors.New("invalid position")
-		logger.Error.Printf("[UnsetPosition] %v", err)
+		logger.Info.Printf("[UnsetPosition] Clearing %s position for user=%s in meet=%s", position, userEmail, meetName)
+		delete(occ.Seats, position)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
 	logger.Info.Printf("[UnsetPosition] Position=%s was vacated by user=%s for meet=%s. Current occupancy: %+v",
-		position, userEmail, meetName, occ)
+		position, userEmail, meetName, occupancyMap[meetName])
 	return nil
 }
 
-// ResetOccupancyForMeet clears all occupant fields for the specified meet.
-func (s *OccupancyService) ResetOccupancyForMeet(meetName string) {
+// ResetOccupancyForPlatform clears all occupant fields for one platform of a meet.
+func (s *OccupancyService) ResetOccupancyForPlatform(meetID, platformID string) {
+	meetName := models.PlatformKey(meetID, platformID)
 	occupancyMutex.Lock()
 	defer occupancyMutex.Unlock()
 
-	logger.Info.Printf("[ResetOccupancyForMeet] Clearing all positions for meet=%s", meetName)
-	if occ, exists := occupancyMap[meetName]; exists {
-		occ.LeftUser = ""
-		occ.CenterUser = ""
-		occ.RightUser = ""
+	logger.Info.Printf("[ResetOccupancyForPlatform] Clearing all positions for meet=%s", meetName)
+	s.clear(meetName)
+}
+
+// ResetOccupancyForMeet clears all occupant fields on every platform of the specified
+// meet, including platforms only other instances have seated.
+func (s *OccupancyService) ResetOccupancyForMeet(meetID string) {
+	occupancyMutex.Lock()
+	defer occupancyMutex.Unlock()
+
+	logger.Info.Printf("[ResetOccupancyForMeet] Clearing all positions for meet=%s", meetID)
+	platforms := make(map[string]bool)
+	for meetName := range occupancyMap {
+		platforms[meetName] = true
+	}
+	if s.store != nil {
+		saved, err := s.store.List(occupancyBucket)
+		if err != nil {
+			logger.Error.Printf("[ResetOccupancyForMeet] Failed to list saved occupancy: %v", err)
+		}
+		for meetName := range saved {
+			platforms[meetName] = true
+		}
+	}
+	for meetName := range platforms {
+		if owner, _ := models.SplitPlatformKey(meetName); owner == meetID {
+			s.clear(meetName)
+		}
+	}
+}
+
+// clear empties the seats of one platform. Caller must hold occupancyMutex.
+func (s *OccupancyService) clear(meetName string) {
+	if _, exists := occupancyMap[meetName]; !exists && s.store == nil {
+		return
+	}
+	err := s.change(meetName, func(occ *Occupancy) error {
+		occ.Seats = nil
+		return nil
+	})
+	if err != nil {
+		logger.Error.Printf("[clear] Failed to clear occupancy for meet=%s: %v", meetName, err)
 	}
 }
 
@@ -6,9 +6,12 @@
 package services
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go-ref-lights/models"
+	"go-ref-lights/storage"
 	"go-ref-lights/websocket"
 )
 
@@ -18,11 +21,11 @@ func TestGetOccupancy_NewMeet(t *testing.T) {
 	meetName := "APL State Championship"
 
 	// expect an empty occupancy state for a new meet
-	occupancy := service.GetOccupancy(meetName)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
 
-	assert.Empty(t, occupancy.LeftUser)
-	assert.Empty(t, occupancy.CenterUser)
-	assert.Empty(t, occupancy.RightUser)
+	assert.Empty(t, occupancy.UserAt("left"))
+	assert.Empty(t, occupancy.UserAt("center"))
+	assert.Empty(t, occupancy.UserAt("right"))
 }
 
 func TestSetPosition_Success(t *testing.T) {
@@ -31,12 +34,12 @@ func TestSetPosition_Success(t *testing.T) {
 	meetName := "APL Nationals"
 
 	// assign a user to the left position
-	err := service.SetPosition(meetName, "left", "referee1@example.com")
+	err := service.SetPosition(meetName, models.DefaultPlatformID, "left", "referee1@example.com")
 	assert.NoError(t, err)
 
 	// verify that the position is correctly assigned
-	occupancy := service.GetOccupancy(meetName)
-	assert.Equal(t, "referee1@example.com", occupancy.LeftUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Equal(t, "referee1@example.com", occupancy.UserAt("left"))
 }
 
 func TestSetPosition_FailsIfTaken(t *testing.T) {
@@ -45,16 +48,16 @@ func TestSetPosition_FailsIfTaken(t *testing.T) {
 	meetName := "APL Regionals"
 
 	// first referee takes the left position
-	_ = service.SetPosition(meetName, "left", "ref1@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref1@example.com")
 
 	// second referee should be blocked from taking the same position
-	err := service.SetPosition(meetName, "left", "ref2@example.com")
+	err := service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref2@example.com")
 	assert.Error(t, err)
 	assert.Equal(t, "left position is already taken", err.Error())
 
 	// ensure the original assignment is unchanged
-	occupancy := service.GetOccupancy(meetName)
-	assert.Equal(t, "ref1@example.com", occupancy.LeftUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Equal(t, "ref1@example.com", occupancy.UserAt("left"))
 }
 
 func TestSetPosition_ClearsOldSeatBeforeAssigningNewOne(t *testing.T) {
@@ -63,16 +66,16 @@ func TestSetPosition_ClearsOldSeatBeforeAssigningNewOne(t *testing.T) {
 	meetName := "APL Qualifiers"
 
 	// assign user to left
-	_ = service.SetPosition(meetName, "left", "ref1@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref1@example.com")
 
 	// move the same user to center
-	err := service.SetPosition(meetName, "center", "ref1@example.com")
+	err := service.SetPosition(meetName, models.DefaultPlatformID, "center", "ref1@example.com")
 	assert.NoError(t, err)
 
 	// verify they moved
-	occupancy := service.GetOccupancy(meetName)
-	assert.Empty(t, occupancy.LeftUser) // Old position should be empty
-	assert.Equal(t, "ref1@example.com", occupancy.CenterUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Empty(t, occupancy.UserAt("left")) // Old position should be empty
+	assert.Equal(t, "ref1@example.com", occupancy.UserAt("center"))
 }
 
 func TestResetOccupancyForMeet(t *testing.T) {
@@ -81,17 +84,40 @@ func TestResetOccupancyForMeet(t *testing.T) {
 	meetName := "APL Open"
 
 	// assign positions
-	_ = service.SetPosition(meetName, "left", "ref1@example.com")
-	_ = service.SetPosition(meetName, "center", "ref2@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref1@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "center", "ref2@example.com")
 
 	// reset occupancy
 	service.ResetOccupancyForMeet(meetName)
 
 	// expect an empty occupancy state
-	occupancy := service.GetOccupancy(meetName)
-	assert.Empty(t, occupancy.LeftUser)
-	assert.Empty(t, occupancy.CenterUser)
-	assert.Empty(t, occupancy.RightUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Empty(t, occupancy.UserAt("left"))
+	assert.Empty(t, occupancy.UserAt("center"))
+	assert.Empty(t, occupancy.UserAt("right"))
+}
+
+func TestOccupancy_PlatformsAreSeparate(t *testing.T) {
+	websocket.InitTest()
+	service := &OccupancyService{}
+	meetName := "APL Two Platforms"
+
+	// the same seat on two platforms goes to two referees
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref1@example.com"))
+	assert.NoError(t, service.SetPosition(meetName, "b", "left", "ref2@example.com"))
+	assert.Equal(t, "ref1@example.com", service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"))
+	assert.Equal(t, "ref2@example.com", service.GetOccupancy(meetName, "b").UserAt("left"))
+
+	// resetting one platform leaves the other seated
+	service.ResetOccupancyForPlatform(meetName, "b")
+	assert.Empty(t, service.GetOccupancy(meetName, "b").UserAt("left"))
+	assert.Equal(t, "ref1@example.com", service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"))
+
+	// resetting the meet clears every platform
+	assert.NoError(t, service.SetPosition(meetName, "b", "right", "ref2@example.com"))
+	service.ResetOccupancyForMeet(meetName)
+	assert.Empty(t, service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"))
+	assert.Empty(t, service.GetOccupancy(meetName, "b").UserAt("right"))
 }
 
 func TestUnsetPosition(t *testing.T) {
@@ -100,15 +126,15 @@ func TestUnsetPosition(t *testing.T) {
 	meetName := "APL Grand Finals"
 
 	// assign a user to right
-	_ = service.SetPosition(meetName, "right", "ref3@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "right", "ref3@example.com")
 
 	// unset the position
-	err := service.UnsetPosition(meetName, "right", "ref3@example.com")
+	err := service.UnsetPosition(meetName, models.DefaultPlatformID, "right", "ref3@example.com")
 	assert.NoError(t, err)
 
 	// verify position is cleared
-	occupancy := service.GetOccupancy(meetName)
-	assert.Empty(t, occupancy.RightUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Empty(t, occupancy.UserAt("right"))
 }
 
 func TestUnsetPosition_FailsIfPositionDoesNotMatchUser(t *testing.T) {
@@ -117,16 +143,120 @@ func TestUnsetPosition_FailsIfPositionDoesNotMatchUser(t *testing.T) {
 	meetName := "APL Regionals"
 
 	// assign a user to a position
-	_ = service.SetPosition(meetName, "center", "ref2@example.com")
+	_ = service.SetPosition(meetName, models.DefaultPlatformID, "center", "ref2@example.com")
 
 	// attempt to unset the position with a different user (should fail)
-	err := service.UnsetPosition(meetName, "center", "wronguser@example.com")
+	err := service.UnsetPosition(meetName, models.DefaultPlatformID, "center", "wronguser@example.com")
 
 	// expect an error
 	assert.Error(t, err, "Expected an error when an incorrect user tries to unset a position")
 	assert.Equal(t, "user does not hold this position", err.Error())
 
 	// ensure the original assignment remains unchanged
-	occupancy := service.GetOccupancy(meetName)
-	assert.Equal(t, "ref2@example.com", occupancy.CenterUser)
+	occupancy := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Equal(t, "ref2@example.com", occupancy.UserAt("center"))
+}
+
+func TestOccupancy_UserAt(t *testing.T) {
+	occ := Occupancy{Seats: map[string]string{"left": "ref1", "center": "ref2", "right": "ref3"}}
+	assert.Equal(t, "ref1", occ.UserAt("left"))
+	assert.Equal(t, "ref2", occ.UserAt("center"))
+	assert.Equal(t, "ref3", occ.UserAt("right"))
+	assert.Empty(t, occ.UserAt("jury"))
+}
+
+func TestOccupancyService_PersistsAndRestoresSeats(t *testing.T) {
+	websocket.InitTest()
+	store := storage.NewMemoryStore()
+	meetName := "Persisted Meet"
+
+	service, err := NewOccupancyServiceWithStore(store)
+	assert.NoError(t, err)
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "left", "left@example.com"))
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "center", "center@example.com"))
+	assert.NoError(t, service.UnsetPosition(meetName, models.DefaultPlatformID, "center", "center@example.com"))
+
+	// simulate a restart: forget everything in memory, then restore from the store
+	occupancyMutex.Lock()
+	occupancyMap = make(map[string]*Occupancy)
+	occupancyMutex.Unlock()
+
+	restored, err := NewOccupancyServiceWithStore(store)
+	assert.NoError(t, err)
+	occ := restored.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Equal(t, "left@example.com", occ.UserAt("left"))
+	assert.Empty(t, occ.UserAt("center"))
+
+	restored.ResetOccupancyForMeet(meetName)
+	occupancyMutex.Lock()
+	occupancyMap = make(map[string]*Occupancy)
+	occupancyMutex.Unlock()
+
+	again, err := NewOccupancyServiceWithStore(store)
+	assert.NoError(t, err)
+	assert.Empty(t, again.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"))
+}
+
+func TestOccupancyService_ReadsAndClaimsThroughStore(t *testing.T) {
+	websocket.InitTest()
+	store := storage.NewMemoryStore()
+	meetName := "Shared Meet"
+	key := models.PlatformKey(meetName, models.DefaultPlatformID)
+
+	service, err := NewOccupancyServiceWithStore(store)
+	assert.NoError(t, err)
+	assert.Empty(t, service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"))
+
+	// another instance seats a referee after this one started
+	assert.NoError(t, store.Put("occupancy", key, Occupancy{Seats: map[string]string{"left": "other@example.com"}}))
+	assert.Equal(t, "other@example.com", service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("left"),
+		"seats are read from the store, not from what this instance saw at startup")
+
+	err = service.SetPosition(meetName, models.DefaultPlatformID, "left", "me@example.com")
+	assert.EqualError(t, err, "left position is already taken", "a seat taken elsewhere cannot be claimed")
+
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "right", "me@example.com"))
+	raw, err := store.Get("occupancy", key)
+	assert.NoError(t, err)
+	var saved Occupancy
+	assert.NoError(t, json.Unmarshal(raw, &saved))
+	assert.Equal(t, map[string]string{"left": "other@example.com", "right": "me@example.com"}, saved.Seats,
+		"a claim keeps the seats other instances hold")
+
+	// a reset also clears platforms only another instance has seated
+	other := models.PlatformKey(meetName, "b")
+	assert.NoError(t, store.Put("occupancy", other, Occupancy{Seats: map[string]string{"center": "b@example.com"}}))
+	service.ResetOccupancyForMeet(meetName)
+	assert.Empty(t, service.GetOccupancy(meetName, "b").Seats)
+	assert.Empty(t, service.GetOccupancy(meetName, models.DefaultPlatformID).Seats)
+}
+
+func TestOccupancy_ReadsSeatsSavedBeforePanels(t *testing.T) {
+	var occ Occupancy
+	err := json.Unmarshal([]byte(`{"LeftUser":"ref1","CenterUser":"","RightUser":"ref3"}`), &occ)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"left": "ref1", "right": "ref3"}, occ.Seats)
+}
+
+func TestSetPosition_FollowsTheMeetPanel(t *testing.T) {
+	websocket.InitTest()
+	service := &OccupancyService{}
+	service.SetPositionLookup(func(meetID string) []models.Position {
+		return []models.Position{{ID: "referee", Name: "Referee"}, {ID: "jury", Name: "Jury", Kind: models.SeatJury}}
+	})
+	meetName := "APL Single Referee"
+
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "referee", "ref1@example.com"))
+	assert.NoError(t, service.SetPosition(meetName, models.DefaultPlatformID, "jury", "jury@example.com"))
+
+	// the default seats are not on this panel
+	err := service.SetPosition(meetName, models.DefaultPlatformID, "left", "ref2@example.com")
+	assert.EqualError(t, err, `invalid position "left" selected for this meet`)
+
+	occ := service.GetOccupancy(meetName, models.DefaultPlatformID)
+	assert.Equal(t, map[string]string{"referee": "ref1@example.com", "jury": "jury@example.com"}, occ.Seats)
+
+	// callers get their own copy of the seats
+	occ.Seats["referee"] = "someone-else"
+	assert.Equal(t, "ref1@example.com", service.GetOccupancy(meetName, models.DefaultPlatformID).UserAt("referee"))
 }
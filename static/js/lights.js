@@ -38,41 +38,72 @@ const multiNextAttemptTimers = document.getElementById("multiNextAttemptTimers")
 
 window.addEventListener("DOMContentLoaded", function () {
 
-    const leftCircle = document.getElementById("leftCircle");
-    const centerCircle = document.getElementById("centerCircle");
-    const rightCircle = document.getElementById("rightCircle");
+    // one light, indicator and card row per referee seat, keyed by judge ID
+    function byJudge(selector) {
+        const els = {};
+        document.querySelectorAll(selector).forEach(el => { els[el.dataset.judgeId] = el; });
+        return els;
+    }
+    const circles = byJudge(".circle[data-judge-id]");
+    const indicators = byJudge(".indicator[data-judge-id]");
+    const cardRows = byJudge(".light-cards[data-judge-id]");
+
+    function resetCircles() {
+        Object.values(circles).forEach(el => { el.style.backgroundColor = "black"; });
+    }
+
+    // Helper function to render the reason cards under a light
+    function renderCards(container, cards) {
+        if (!container) return;
+        container.innerHTML = "";
+        (cards || []).forEach(card => {
+            const cardEl = document.createElement("div");
+            cardEl.classList.add("light-card", `card-${card}`);
+            cardEl.title = card;
+            container.appendChild(cardEl);
+        });
+    }
+
+    function clearCards() {
+        Object.values(cardRows).forEach(el => renderCards(el, []));
+    }
 
-    const leftIndicator = document.getElementById("leftIndicator");
-    const centerIndicator = document.getElementById("centerIndicator");
-    const rightIndicator = document.getElementById("rightIndicator");
+    // Helper function to show the lifter currently on the platform
+    const currentLifterEl = document.getElementById("currentLifter");
+    function renderCurrentAttempt(attempt) {
+        if (!currentLifterEl || !attempt) return;
+        const name = attempt.lifterName || attempt.lifterId;
+        const lift = attempt.lift.charAt(0).toUpperCase() + attempt.lift.slice(1);
+        currentLifterEl.innerText = `${name} — ${lift} #${attempt.attemptNumber} — ${attempt.weightKg}kg`;
+    }
 
     // helper function to get a consistent meet name from the DOM/URL/sessionStorage
-    function getMeetName() {
-        let elem = document.getElementById("meetName");
-        let meetName = elem ? elem.dataset.meetName : null;
-        if (!meetName) {
-            meetName = sessionStorage.getItem("meetName")
-                || new URLSearchParams(window.location.search).get("meetName");
+    function getMeetID() {
+        let elem = document.getElementById("meetID");
+        let meetID = elem ? elem.dataset.meetId : null;
+        if (!meetID) {
+            meetID = sessionStorage.getItem("meetID")
+                || new URLSearchParams(window.location.search).get("meet");
         }
-        if (meetName) {
-            sessionStorage.setItem("meetName", meetName);
-            log(`✅ Meet name set: ${meetName}`, "info");
+        if (meetID) {
+            sessionStorage.setItem("meetID", meetID);
+            log(`✅ Meet set: ${meetID}`, "info");
         } else {
-            log("⚠️ Meet name is missing! Redirecting to meet selection.", "warn");
+            log("⚠️ Meet is missing! Redirecting to meet selection.", "warn");
             alert("Error: No meet selected. Redirecting.");
             window.location.href = "/meets";
         }
-        return meetName;
+        return meetID;
     }
 
     // Helper function to update the platform ready timer UI
     function updatePlatformReadyTimer(timer) {
-        log(`Updating Platform Ready Timer: ${timer.TimeLeft}s`, "debug");
+        log(`Updating Platform Ready Timer: ${timer.timeLeft}s`, "debug");
         if (timerDisplay) {
-            timerDisplay.innerText = `${timer.TimeLeft}s`;
+            timerDisplay.innerText = `${timer.timeLeft}s`;
         }
         // Hide the container if the timer ran out
-        if (timer.TimeLeft <= 0 && platformReadyTimerContainer) {
+        if (timer.timeLeft <= 0 && platformReadyTimerContainer) {
             platformReadyTimerContainer.classList.add("hidden");
         } else if (platformReadyTimerContainer) {
             platformReadyTimerContainer.classList.remove("hidden");
@@ -82,21 +113,21 @@ window.addEventListener("DOMContentLoaded", function () {
     // Helper function to update a next attempt timer UI element
     function updateNextAttemptTimer(timer, container, timersMap) {
         // If time is up, remove the timer element
-        if (timer.TimeLeft <= 0) {
-            if (timersMap[timer.ID]) {
-                container.removeChild(timersMap[timer.ID]);
-                delete timersMap[timer.ID];
+        if (timer.timeLeft <= 0) {
+            if (timersMap[timer.id]) {
+                container.removeChild(timersMap[timer.id]);
+                delete timersMap[timer.id];
             }
         } else {
             // Create the timer element if it doesn't exist
-            if (!timersMap[timer.ID]) {
+            if (!timersMap[timer.id]) {
                 let newRow = document.createElement("div");
                 newRow.classList.add("timer");
                 container.insertBefore(newRow, container.firstChild);
-                timersMap[timer.ID] = newRow;
+                timersMap[timer.id] = newRow;
             }
             // Update the timer element's text
-            timersMap[timer.ID].textContent = `Next Attempt: ${timer.TimeLeft}s`;
+            timersMap[timer.id].textContent = `Next Attempt: ${timer.timeLeft}s`;
             container.classList.remove("hidden");
         }
     }
@@ -105,7 +136,7 @@ window.addEventListener("DOMContentLoaded", function () {
     function handleUpdateNextAttemptTime(data) {
         if (data.timers && Array.isArray(data.timers)) {
             data.timers.forEach(timer => {
-                if (timer.ID === 1) {
+                if (timer.id === 1) {
                     if (resultsDisplayed) {
                         // When results are displayed, treat timer ID 1 as a next attempt timer
                         updateNextAttemptTimer(timer, multiNextAttemptTimers, nextAttemptTimers);
@@ -122,18 +153,23 @@ window.addEventListener("DOMContentLoaded", function () {
     }
 
     // constants
-    const meetName = getMeetName();
-    if (!meetName) return;
+    const meetID = getMeetID();
+    if (!meetID) return;
     const judgeId = "lights";
 
     // Build your WebSocket URL
     const scheme = (window.location.protocol === "https:") ? "wss" : "ws";
-    const wsUrl = `${scheme}://${window.location.host}/referee-updates?meetName=${meetName}`;
+    // the platform this page follows; empty means the meet's first platform
+    const platformElem = document.getElementById("meetID");
+    const platformID = platformElem ? platformElem.dataset.platformId : "";
+    let wsUrl = `${scheme}://${window.location.host}/referee-updates?meet=${encodeURIComponent(meetID)}`;
+    if (platformID) wsUrl += `&platform=${encodeURIComponent(platformID)}`;
 
     // -------------------------------------------------------------
     // NEW: Use ReconnectingWebSocket instead of native WebSocket
     // -------------------------------------------------------------
-    socket = new ReconnectingWebSocket(wsUrl, null, {
+    // ask for the protocol version this page speaks (see /protocol/schema.json)
+    socket = new ReconnectingWebSocket(wsUrl, "reflights.v1", {
         reconnectInterval: 2000,   // 2 seconds
         maxReconnectAttempts: null // infinite
     });
@@ -143,10 +179,39 @@ window.addEventListener("DOMContentLoaded", function () {
     const healthEl = document.getElementById("healthStatus");
     const platformReadyTimerContainer = document.getElementById('platformReadyTimerContainer');
     const statusEl = document.getElementById("connectionStatus");
+    const timerTitle = document.getElementById("title");
+
+    // show or clear the paused state of the platform ready timer
+    function setPlatformReadyPaused(paused) {
+        if (platformReadyTimerContainer) {
+            platformReadyTimerContainer.classList.toggle("paused", paused);
+        }
+        if (timerTitle) {
+            timerTitle.innerText = paused ? "Platform Ready (paused):" : "Platform Ready:";
+        }
+    }
+
+    // clock controls are only rendered for meet admins
+    document.querySelectorAll(".clock-button").forEach(btn => {
+        btn.addEventListener("click", () => {
+            const msg = { action: btn.dataset.clockAction, meetName: meetID };
+            if (btn.dataset.seconds) msg.seconds = parseInt(btn.dataset.seconds, 10);
+            socket.send(JSON.stringify(msg));
+            log(`Sent ${msg.action} from lights page`, "info");
+        });
+    });
+
+    // ID of the attempt under way, from the attemptId on the server's messages; a
+    // reconnect starts afresh in case the server was restarted or the meet reset
+    let attemptId = 0;
+
+    // messages about one attempt, dropped when they arrive after a later attempt's
+    const attemptScoped = new Set(["displayResults", "clearResults", "judgeSubmitted", "decisionsOpen"]);
 
     // socket onopen
     socket.onopen = function () {
         log("✅ WebSocket connection established (Lights).", "info");
+        attemptId = 0;
         if (statusEl) {
             statusEl.innerText = "Connected";
             statusEl.style.color = "green";
@@ -154,18 +219,31 @@ window.addEventListener("DOMContentLoaded", function () {
         const registerMsg = {
             action: "registerRef",
             judgeId: judgeId,  // "lights"
-            meetName: meetName
+            meetName: meetID
         };
         socket.send(JSON.stringify(registerMsg));
-        log(`Sent registerRef for lights with meetName=${meetName}`, "info");
+        log(`Sent registerRef for lights with meet=${meetID}`, "info");
+    };
+
+    // an instance that does not serve this platform closes with 4409 (see
+    // protocol.CloseServedElsewhere); retry at the base interval instead of backing off,
+    // so the load balancer soon sends us to the instance that does
+    let servedElsewhere = false;
+    socket.onconnecting = function (event) {
+        servedElsewhere = event.code === 4409;
+        if (servedElsewhere) socket.reconnectAttempts = 0;
     };
 
     // socket onclose
     socket.onclose = function (event) {
-        log(`⚠️ WebSocket connection closed (Lights): ${event.code} - ${event.reason}`, "warn");
+        if (servedElsewhere) {
+            log("Platform is served by another instance; retrying (Lights).", "info");
+        } else {
+            log(`⚠️ WebSocket connection closed (Lights): ${event.code} - ${event.reason}`, "warn");
+        }
         if (statusEl) {
-            statusEl.innerText = "Disconnected";
-            statusEl.style.color = "red";
+            statusEl.innerText = servedElsewhere ? "Finding platform server..." : "Disconnected";
+            statusEl.style.color = servedElsewhere ? "orange" : "red";
         }
     };
 
@@ -185,7 +263,49 @@ window.addEventListener("DOMContentLoaded", function () {
             return;
         }
 
+        if (data.attemptId) {
+            if (data.attemptId < attemptId && attemptScoped.has(data.action)) {
+                log(`Dropping ${data.action} for attempt ${data.attemptId}; attempt ${attemptId} is under way`, "debug");
+                return;
+            }
+            attemptId = Math.max(attemptId, data.attemptId);
+        }
+        handleMessage(data);
+    };
+
+    // draws the platform as the server has it, after connecting or reconnecting
+    function applyStateSnapshot(data) {
+        handleMessage({ action: "clearResults" });
+        if (data.lastResult) handleMessage(data.lastResult);
+        (data.submitted || []).forEach(id => handleMessage({ action: "judgeSubmitted", judgeId: id }));
+        if (data.currentAttempt) handleMessage({ action: "currentAttempt", currentAttempt: data.currentAttempt });
+
+        const clock = data.platformReady || {};
+        setPlatformReadyPaused(!!clock.paused);
+        if (clock.active) {
+            updatePlatformReadyTimer(clock);
+        } else if (platformReadyTimerContainer) {
+            platformReadyTimerContainer.classList.add("hidden");
+        }
+        // drop timers that ran out while the page was disconnected
+        const live = new Set((data.nextAttemptTimers || []).map(timer => timer.id));
+        Object.keys(nextAttemptTimers).forEach(id => {
+            if (!live.has(Number(id)) && multiNextAttemptTimers) {
+                multiNextAttemptTimers.removeChild(nextAttemptTimers[id]);
+                delete nextAttemptTimers[id];
+            }
+        });
+        handleUpdateNextAttemptTime({ timers: data.nextAttemptTimers || [] });
+    }
+
+    // handleMessage updates the display for one message from the server
+    function handleMessage(data) {
         switch (data.action) {
+            case "stateSnapshot":
+                log(`[lights.js] State snapshot for attempt ${data.attemptId}`, "debug");
+                applyStateSnapshot(data);
+                break;
+
             case "refereeHealth": {
                 const isConnected = data.connectedRefIDs.includes(judgeId);
                 if (healthEl) {
@@ -198,9 +318,14 @@ window.addEventListener("DOMContentLoaded", function () {
                 alert(data.message);
                 break;
 
+            case "error":
+                log(`Server could not handle ${data.requested || "a message"}: ${data.message} (${data.code})`, "warn");
+                break;
+
             case "startTimer":
                 log("🔵 Received startTimer from server, starting Platform Ready Timer countdown");
                 resultsDisplayed = false;
+                setPlatformReadyPaused(false);
 
                 if (platformReadyInterval) {
                     clearInterval(platformReadyInterval);
@@ -243,38 +368,58 @@ window.addEventListener("DOMContentLoaded", function () {
                 }
                 break;
 
+            case "platformReadyPaused":
+                log(`⏸️ Platform Ready Timer paused with ${data.timeLeft}s left`);
+                setPlatformReadyPaused(true);
+                if (timerDisplay) {
+                    timerDisplay.innerText = `${data.timeLeft}s`;
+                }
+                break;
+
+            case "platformReadyResumed":
+                log(`▶️ Platform Ready Timer resumed with ${data.timeLeft}s left`);
+                setPlatformReadyPaused(false);
+                break;
+
+            case "timerActionRejected":
+                log(`Clock control ${data.requested} rejected: ${data.message}`, "warn");
+                alert(`Clock not changed: ${data.message}`);
+                break;
+
             case "updateNextAttemptTime":
                 log("✅ Entering handleUpdateNextAttemptTime", "debug");
                 handleUpdateNextAttemptTime(data);
                 break;
 
+            case "currentAttempt":
+                log(`[lights.js] Current attempt: ${JSON.stringify(data.currentAttempt)}`);
+                renderCurrentAttempt(data.currentAttempt);
+                break;
+
             case "judgeSubmitted":
                 log(`[lights.js] Judge ${data.judgeId} has submitted a decision.`);
-                if (data.judgeId === "left") {
-                    leftIndicator.style.backgroundColor = "green";
-                } else if (data.judgeId === "center") {
-                    centerIndicator.style.backgroundColor = "green";
-                } else if (data.judgeId === "right") {
-                    rightIndicator.style.backgroundColor = "green";
+                if (indicators[data.judgeId]) {
+                    indicators[data.judgeId].style.backgroundColor = "green";
                 }
                 break;
 
             case "displayResults":
-                log(`Final decisions: L=${data.leftDecision}, C=${data.centerDecision}, R=${data.rightDecision}`);
-                log(`[lights.js] displayResults received: left=${data.leftDecision}, center=${data.centerDecision}, right=${data.rightDecision}`);
+                log(`[lights.js] displayResults received: ${JSON.stringify(data.decisions)}`);
 
-                leftCircle.style.backgroundColor   = (data.leftDecision   === "white") ? "white" : "red";
-                centerCircle.style.backgroundColor = (data.centerDecision === "white") ? "white" : "red";
-                rightCircle.style.backgroundColor  = (data.rightDecision  === "white") ? "white" : "red";
-
-                let whiteCount = 0;
-                let redCount = 0;
-                [data.leftDecision, data.centerDecision, data.rightDecision].forEach(decision => {
-                    if (decision === "white") { whiteCount++; } else { redCount++; }
+                (data.referees || []).forEach(id => {
+                    const decision = (data.decisions || {})[id];
+                    if (circles[id]) {
+                        circles[id].style.backgroundColor = (decision === "white") ? "white" : "red";
+                    }
+                    renderCards(cardRows[id], (data.cards || {})[id]);
                 });
+                renderCurrentAttempt(data.currentAttempt);
+
+                // the server computes the verdict so every display applies the same rule
+                log(`[lights.js] verdict=${data.verdict} (white=${data.whiteCount}, red=${data.redCount})`);
 
                 const messageEl = document.getElementById("message");
-                if (whiteCount >= 2) {
+                if (data.verdict === "good lift") {
                     messageEl.innerText = "Good Lift";
                     messageEl.style.color = "green";
                 } else {
@@ -292,8 +437,23 @@ window.addEventListener("DOMContentLoaded", function () {
                 resultsDisplayed = true;
                 break;
 
+            case "decisionAmended": {
+                // the jury overturned the last attempt; show the corrected result
+                log(`[lights.js] Attempt ${data.attempt} amended by the jury: ${data.originalVerdict} -> ${data.verdict}`);
+                const amendedEl = document.getElementById("message");
+                amendedEl.innerText = (data.verdict === "good lift") ? "Good Lift (Jury)" : "No Lift (Jury)";
+                amendedEl.style.color = (data.verdict === "good lift") ? "green" : "red";
+                amendedEl.classList.add("flash");
+                setTimeout(() => {
+                    amendedEl.innerText = "";
+                    amendedEl.classList.remove("flash");
+                }, 15000);
+                break;
+            }
+
             case "platformReadyExpired":
                 log("⏰ Platform Ready Timer Expired!");
+                setPlatformReadyPaused(false);
                 if (platformReadyTimerContainer) {
                     platformReadyTimerContainer.classList.add("hidden");
                 }
@@ -301,12 +461,9 @@ window.addEventListener("DOMContentLoaded", function () {
 
             case "clearResults":
                 log("Clearing results from Lights UI (white vs red circles, judge indicators).");
-                leftCircle.style.backgroundColor   = "black";
-                centerCircle.style.backgroundColor = "black";
-                rightCircle.style.backgroundColor  = "black";
-                leftIndicator.style.backgroundColor   = "grey";
-                centerIndicator.style.backgroundColor = "grey";
-                rightIndicator.style.backgroundColor  = "grey";
+                resetCircles();
+                Object.values(indicators).forEach(el => { el.style.backgroundColor = "grey"; });
+                clearCards();
 
                 const msgEl = document.getElementById("message");
                 if (msgEl) {
@@ -316,15 +473,18 @@ window.addEventListener("DOMContentLoaded", function () {
                 resultsDisplayed = false;
                 break;
 
+            case "decisionsOpen":
+                log(`[lights.js] Attempt ${data.attemptId} open (${data.source})`);
+                break;
+
             case "resetLights":
                 log("🌀 Resetting lights to black");
-                leftCircle.style.backgroundColor   = "black";
-                centerCircle.style.backgroundColor = "black";
-                rightCircle.style.backgroundColor  = "black";
+                resetCircles();
+                clearCards();
                 break;
 
             default:
                 log(`⚠️ Unknown action: ${data.action}`, "warn");
         }
-    };
+    }
 });
@@ -32,44 +32,49 @@ function log(message, level = 'debug') {
     }).catch(error => console.error('Failed to send log to server:', error));
 }
 
-// We assume that each referee page sets 'judgeId' in <script> above this file:
-//   <script> let judgeId = "center"; </script>
+// We assume that the seat page sets 'judgeId' and 'isChief' in <script> above this file:
+//   <script> let judgeId = "center"; let isChief = true; </script>
 // Then loads this JS.
 
 document.addEventListener('DOMContentLoaded', function() {
 
-    // helper to get meetName from <div id="meetName" data-meet-name="foo">
-    function getMeetName() {
-        const elem = document.getElementById("meetName");
-        let meetName = elem ? elem.dataset.meetName : null;
+    // helper to get the meet ID from <div id="meetID" data-meet-id="cairns-cup">
+    function getMeetID() {
+        const elem = document.getElementById("meetID");
+        let meetID = elem ? elem.dataset.meetId : null;
 
         // fallback to sessionStorage or URL query param
-        if (!meetName) {
-            meetName = sessionStorage.getItem("meetName") ||
-                new URLSearchParams(window.location.search).get("meetName");
+        if (!meetID) {
+            meetID = sessionStorage.getItem("meetID") ||
+                new URLSearchParams(window.location.search).get("meet");
         }
-        if (meetName) {
-            sessionStorage.setItem("meetName", meetName);
-            log(`✅ Meet name set: ${meetName}`, "info");
+        if (meetID) {
+            sessionStorage.setItem("meetID", meetID);
+            log(`✅ Meet set: ${meetID}`, "info");
         } else {
-            log("⚠️ Meet name is missing! Redirecting to /meets.", "warn");
+            log("⚠️ Meet is missing! Redirecting to /meets.", "warn");
             alert("Error: No meet selected. Redirecting.");
             window.location.href = "/meets";
         }
-        return meetName;
+        return meetID;
     }
 
-    // retrieve meetName
-    const meetName = getMeetName();
-    if (!meetName) return; // bail if no meet
+    // retrieve the meet ID
+    const meetID = getMeetID();
+    if (!meetID) return; // bail if no meet
 
     // build WebSocket URL (with correct scheme)
     const scheme = (window.location.protocol === "https:") ? "wss" : "ws";
-    const wsUrl = `${scheme}://${window.location.host}/referee-updates?meetName=${meetName}`;
+    // the platform this page follows; empty means the meet's first platform
+    const platformElem = document.getElementById("meetID");
+    const platformID = platformElem ? platformElem.dataset.platformId : "";
+    let wsUrl = `${scheme}://${window.location.host}/referee-updates?meet=${encodeURIComponent(meetID)}`;
+    if (platformID) wsUrl += `&platform=${encodeURIComponent(platformID)}`;
 
     // create Reconnecting WebSocket
     // (Requires reconnecting-websocket.min.js to be loaded first in the HTML)
-    socket = new ReconnectingWebSocket(wsUrl, null, {
+    // ask for the protocol version this page speaks (see /protocol/schema.json)
+    socket = new ReconnectingWebSocket(wsUrl, "reflights.v1", {
         reconnectInterval: 2000,   // 2 seconds
         maxReconnectAttempts: null // infinite
     });
@@ -80,24 +85,48 @@ document.addEventListener('DOMContentLoaded', function() {
     const redButton     = document.getElementById('redButton');
     const startTimerBtn = document.getElementById('startTimerButton');
     const platformReadyButton = document.getElementById('platformReadyButton');
+    const clockStatusEl = document.getElementById('clockStatus');
 
     // If you want a visible timer in referee page:
     const platformReadyTimerContainer = document.getElementById('platformReadyTimerContainer');
     const timerDisplay = document.getElementById('timer');
 
-    // This might be for occupant display:
-    const leftUserEl   = document.getElementById("leftUser");
-    const centerUserEl = document.getElementById("centerUser");
-    const rightUserEl  = document.getElementById("rightUser");
+    // jury seats show the panel's decisions instead of voting
+    const panelDecisionsEl = document.getElementById("panelDecisions");
+
+    // shows whether the server has received this referee's decision
+    const decisionStatusEl = document.getElementById("decisionStatus");
+
+    // requestId of the decision awaiting the server's ack, if any
+    let pendingDecision = null;
+
+    function setDecisionStatus(state, text) {
+        if (!decisionStatusEl) return;
+        decisionStatusEl.className = state ? `decision-status ${state}` : "decision-status";
+        decisionStatusEl.innerText = text || "";
+    }
+
+    // ID of the attempt under way, from the attemptId on the server's messages; a
+    // reconnect starts afresh in case the server was restarted or the meet reset
+    let attemptId = 0;
+
+    // messages about one attempt, dropped when they arrive after a later attempt's
+    const attemptScoped = new Set(["displayResults", "clearResults", "judgeSubmitted", "decisionsOpen"]);
 
     // onopen
     socket.onopen = function() {
         log(`WebSocket connected for judgeId: ${judgeId}`, "info");
+        attemptId = 0;
+        // a decision sent before the connection dropped may not have arrived
+        if (pendingDecision) {
+            pendingDecision = null;
+            setDecisionStatus("failed", "Not confirmed - please decide again");
+        }
         // send a register message
         const registerMsg = {
             action: "registerRef",
             judgeId: judgeId,
-            meetName: meetName
+            meetName: meetID
         };
         socket.send(JSON.stringify(registerMsg));
     };
@@ -112,14 +141,39 @@ document.addEventListener('DOMContentLoaded', function() {
             return;
         }
 
+        if (data.attemptId) {
+            if (data.attemptId < attemptId && attemptScoped.has(data.action)) {
+                log(`Dropping ${data.action} for attempt ${data.attemptId}; attempt ${attemptId} is under way`, "debug");
+                return;
+            }
+            attemptId = Math.max(attemptId, data.attemptId);
+        }
+
         switch (data.action) {
 
+            // the platform as the server has it, after connecting or reconnecting
+            case "stateSnapshot": {
+                log(`State snapshot for attempt ${data.attemptId}: submitted=${(data.submitted || []).join(",")}`, "debug");
+                const clock = data.platformReady || {};
+                if (platformReadyTimerContainer) platformReadyTimerContainer.classList.toggle("hidden", !clock.active);
+                if (timerDisplay && clock.active) timerDisplay.textContent = clock.timeLeft + "s";
+                if (clockStatusEl) clockStatusEl.innerText = clock.paused ? `Clock paused (${clock.timeLeft}s)` : "";
+                if (panelDecisionsEl) {
+                    const result = data.lastResult;
+                    panelDecisionsEl.innerText = result
+                        ? (result.referees || []).map(id => `${id}: ${(result.decisions || {})[id] || "-"}`).join("  ")
+                        : "Waiting for decisions";
+                }
+                // a decision the server already holds counts as received, e.g. one sent just before a reconnect
+                if (!pendingDecision && (data.submitted || []).includes(judgeId)) {
+                    setDecisionStatus("received", "Decision received");
+                }
+                break;
+            }
+
             // existing occupant / seat info
             case "occupancyChanged":
-                log(`occupancyChanged: L=${data.leftUser} C=${data.centerUser} R=${data.rightUser}`, "debug");
-                if (leftUserEl)   leftUserEl.innerText   = data.leftUser   || "Vacant";
-                if (centerUserEl) centerUserEl.innerText = data.centerUser || "Vacant";
-                if (rightUserEl)  rightUserEl.innerText  = data.rightUser  || "Vacant";
+                log(`occupancyChanged: ${JSON.stringify(data.seats || {})}`, "debug");
                 break;
 
             case "refereeHealth": {
@@ -136,6 +190,48 @@ document.addEventListener('DOMContentLoaded', function() {
                 alert(data.message);
                 break;
 
+            case "ack":
+                log(`Server applied ${data.requested} (request ${data.requestId})`, "debug");
+                if (pendingDecision && data.requestId === pendingDecision) {
+                    pendingDecision = null;
+                    setDecisionStatus("received", "Decision received");
+                }
+                break;
+
+            case "error":
+                log(`Server could not handle ${data.requested || "a message"}: ${data.message} (${data.code})`, "warn");
+                if (data.requested === "submitDecision") {
+                    decisionFailed(data);
+                }
+                break;
+
+            case "platformReadyPaused":
+                log(`⏸️ Platform Ready Timer paused with ${data.timeLeft}s left`, "debug");
+                if (clockStatusEl) clockStatusEl.innerText = `Clock paused (${data.timeLeft}s)`;
+                break;
+
+            case "platformReadyResumed":
+                log(`▶️ Platform Ready Timer resumed with ${data.timeLeft}s left`, "debug");
+                if (clockStatusEl) clockStatusEl.innerText = "";
+                break;
+
+            case "timerActionRejected":
+                log(`Clock control ${data.requested} rejected: ${data.message}`, "warn");
+                alert(`Clock not changed: ${data.message}`);
+                break;
+
+            case "decisionsOpen":
+                // decisions carry the attempt ID so a late tap cannot count towards the next attempt
+                log(`Attempt ${data.attemptId} open (${data.source}, change window ${data.changeMs}ms)`, "debug");
+                pendingDecision = null;
+                setDecisionStatus();
+                break;
+
+            case "decisionRejected":
+                log(`Decision rejected by server: ${data.message}`, "warn");
+                decisionFailed(data);
+                break;
+
             // ------------------------------
             // *** The ones previously "Unhandled" ***
             // ------------------------------
@@ -144,7 +240,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 log("🔵 Received startTimer in referee-common.js; clearing results, show timer if needed", "debug");
                 // If you want to show a timer on the referee page:
                 if (platformReadyTimerContainer) platformReadyTimerContainer.classList.remove("hidden");
-                // Possibly reset some local state
+                if (clockStatusEl) clockStatusEl.innerText = "";
                 break;
 
             case "updatePlatformReadyTime":
@@ -166,6 +262,9 @@ document.addEventListener('DOMContentLoaded', function() {
 
             case "clearResults":
                 log("RefereeCommon: clearing results UI. (If referee page shows lights, do it here)", "debug");
+                if (panelDecisionsEl) panelDecisionsEl.innerText = "Waiting for decisions";
+                pendingDecision = null;
+                setDecisionStatus();
                 // In your referee page, maybe you don't do anything; or you could revert local state.
 
                 // example:
@@ -175,17 +274,26 @@ document.addEventListener('DOMContentLoaded', function() {
 
             case "judgeSubmitted":
                 log(`RefereeCommon: Another judge submitted a decision: judgeId=${data.judgeId}`, "debug");
-                // If you want to show a UI indicator that left/center/right has submitted, handle it here
+                // If you want to show a UI indicator that a seat has submitted, handle it here
                 break;
 
             case "displayResults":
-                log(`RefereeCommon: final decisions => L=${data.leftDecision}, C=${data.centerDecision}, R=${data.rightDecision}`, "debug");
-                // If the referee page wants to see the final results, do so:
-                // document.getElementById("someEl").innerText = data.leftDecision + data.centerDecision + data.rightDecision;
+                log(`RefereeCommon: final decisions => ${JSON.stringify(data.decisions || {})}`, "debug");
+                if (panelDecisionsEl) {
+                    panelDecisionsEl.innerText = (data.referees || [])
+                        .map(id => `${id}: ${(data.decisions || {})[id] || "-"}`)
+                        .join("  ");
+                }
+                break;
+
+            case "decisionAmended":
+                log(`RefereeCommon: jury amended attempt ${data.attempt}: ${data.originalVerdict} -> ${data.verdict}`, "info");
+                if (panelDecisionsEl) panelDecisionsEl.innerText = `Jury ruling: ${data.verdict} (${data.reason})`;
                 break;
 
             case "platformReadyExpired":
                 log("RefereeCommon: Platform Ready Timer Expired", "debug");
+                if (clockStatusEl) clockStatusEl.innerText = "";
                 if (platformReadyTimerContainer) {
                     platformReadyTimerContainer.classList.add("hidden");
                 }
@@ -205,41 +313,109 @@ document.addEventListener('DOMContentLoaded', function() {
         log(`WebSocket error (${judgeId}): ${error}`, "error");
     };
 
+    // an instance that does not serve this platform closes with 4409 (see
+    // protocol.CloseServedElsewhere); retry at the base interval instead of backing off,
+    // so the load balancer soon sends us to the instance that does
+    let servedElsewhere = false;
+    socket.onconnecting = function(event) {
+        servedElsewhere = event.code === 4409;
+        if (servedElsewhere) socket.reconnectAttempts = 0;
+    };
+
     // handle close (the ReconnectingWebSocket will attempt reconnect automatically)
     socket.onclose = function(event) {
-        log(`WebSocket closed (${judgeId}): ${event.code} - ${event.reason}`, "info");
+        if (servedElsewhere) {
+            log(`Platform is served by another instance; retrying (${judgeId})`, "info");
+        } else {
+            log(`WebSocket closed (${judgeId}): ${event.code} - ${event.reason}`, "info");
+        }
         if (healthEl) {
-            healthEl.innerText = "Disconnected";
-            healthEl.style.color = "red";
+            healthEl.innerText = servedElsewhere ? "Finding platform server..." : "Disconnected";
+            healthEl.style.color = servedElsewhere ? "orange" : "red";
         }
     };
 
-    // convenience function for sending JSON messages
+    // a refused decision is shown to the referee; refusals of earlier taps are only logged
+    function decisionFailed(data) {
+        if (data.requestId && data.requestId !== pendingDecision) return;
+        pendingDecision = null;
+        setDecisionStatus("failed", "Not counted");
+        alert(`Your decision was not counted: ${data.message}`);
+    }
+
+    // numbers this page's messages so the server's ack or error can be matched to them
+    let requestCount = 0;
+
+    // convenience function for sending JSON messages, tagged with the attempt they are
+    // for and a requestId; returns the requestId, or null if the socket is not open
     function sendMessage(obj) {
+        if (attemptId) obj.attemptId = attemptId;
+        obj.requestId = `${judgeId}-${Date.now().toString(36)}-${++requestCount}`;
         if (socket.readyState === WebSocket.OPEN) {
             const msgStr = JSON.stringify(obj);
             socket.send(msgStr);
             log(`Sent message: ${msgStr}`, "info");
+            return obj.requestId;
+        }
+        log(`Cannot send message; socket not open (readyState = ${socket.readyState})`, "warn");
+        return null;
+    }
+
+    // sends a decision and shows it as pending until the server acks it
+    function sendDecision(msg) {
+        pendingDecision = sendMessage(msg);
+        if (pendingDecision) {
+            setDecisionStatus("pending", "Sending...");
         } else {
-            log(`Cannot send message; socket not open (readyState = ${socket.readyState})`, "warn");
+            setDecisionStatus("failed", "Not sent - not connected");
         }
     }
 
-    // If your "Platform Ready" button is on the center referee page:
-    if (judgeId === "center" && platformReadyButton) {
+    // The "Platform Ready" button is only on the chief referee's page:
+    if (isChief && platformReadyButton) {
         platformReadyButton.addEventListener("click", () => {
             log("'Platform Ready' button clicked; sending startTimer", "debug");
-            sendMessage({ action: "startTimer", meetName: meetName });
+            sendMessage({ action: "startTimer", meetName: meetID });
+        });
+    }
+
+    // clock controls (pause, resume, ±N seconds) on the chief referee's page
+    if (isChief) {
+        document.querySelectorAll(".clock-button").forEach(btn => {
+            btn.addEventListener("click", () => {
+                const msg = { action: btn.dataset.clockAction, meetName: meetID };
+                if (btn.dataset.seconds) msg.seconds = parseInt(btn.dataset.seconds, 10);
+                sendMessage(msg);
+            });
+        });
+    }
+
+    // reason card toggles (red/blue/yellow) that accompany a red decision
+    const cardButtons = document.querySelectorAll('.card-button');
+    cardButtons.forEach(btn => {
+        btn.addEventListener('click', function() {
+            btn.classList.toggle('selected');
         });
+    });
+
+    function selectedCards() {
+        return Array.from(cardButtons)
+            .filter(btn => btn.classList.contains('selected'))
+            .map(btn => btn.dataset.card);
+    }
+
+    function clearSelectedCards() {
+        cardButtons.forEach(btn => btn.classList.remove('selected'));
     }
 
     // handle White/Red button clicks
     const whiteBtn = document.getElementById('whiteButton');
     if (whiteBtn) {
         whiteBtn.addEventListener('click', function() {
-            sendMessage({
+            clearSelectedCards();
+            sendDecision({
                 action: "submitDecision",
-                meetName: meetName,
+                meetName: meetID,
                 judgeId: judgeId,
                 decision: "white"
             });
@@ -249,13 +425,21 @@ document.addEventListener('DOMContentLoaded', function() {
     const redBtn = document.getElementById('redButton');
     if (redBtn) {
         redBtn.addEventListener('click', function() {
-            sendMessage({
+            const cards = selectedCards();
+            // a no lift needs at least one reason card (IPF rules); the server refuses one without
+            if (cards.length === 0) {
+                setDecisionStatus("failed", "Pick a reason card first");
+                return;
+            }
+            sendDecision({
                 action: "submitDecision",
-                meetName: meetName,
+                meetName: meetID,
                 judgeId: judgeId,
-                decision: "red"
+                decision: "red",
+                cards: cards
             });
-            log(`[RefereeCommon] Judge '${judgeId}' clicked NO LIFT (red).`, "info");
+            clearSelectedCards();
+            log(`[RefereeCommon] Judge '${judgeId}' clicked NO LIFT (red) with cards=${cards.join(",")}.`, "info");
         });
     }
 });
@@ -0,0 +1,210 @@
+// Package storage - Redis implementation, used when several instances share state.
+// File: storage/redis_store.go
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisPrefix namespaces the keys written by RedisStore.
+const DefaultRedisPrefix = "referee-lights:"
+
+// redisTimeout bounds every Redis round trip so a stalled server can't hang a request.
+const redisTimeout = 3 * time.Second
+
+// maxUpdateTries bounds how often Update retries after another instance changed the
+// bucket between its read and its write.
+const maxUpdateTries = 20
+
+// leaseScript takes key for the holder in ARGV[1] for ARGV[2] milliseconds, or extends
+// the lease that holder already has. It returns 0 while someone else holds key.
+var leaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 1
+end
+return 0
+`)
+
+// releaseScript deletes key only if the holder in ARGV[1] still holds it.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisStore keeps each bucket in a Redis hash, so every instance behind the load
+// balancer sees the same seats and timers, and hands out leases that every instance
+// respects.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects to the Redis server at url (e.g. redis://localhost:6379/0)
+// and checks it is reachable.
+func NewRedisStore(url, prefix string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if prefix == "" {
+		prefix = DefaultRedisPrefix
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to reach Redis: %w", err)
+	}
+	return &RedisStore{client: client, prefix: prefix}, nil
+}
+
+// Put implements Store.
+func (s *RedisStore) Put(bucket, key string, value interface{}) error {
+	if bucket == "" || key == "" {
+		return errors.New("bucket and key must not be empty")
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s/%s: %w", bucket, key, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return s.client.HSet(ctx, s.prefix+bucket, key, raw).Err()
+}
+
+// Delete implements Store.
+func (s *RedisStore) Delete(bucket, key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return s.client.HDel(ctx, s.prefix+bucket, key).Err()
+}
+
+// List implements Store.
+func (s *RedisStore) List(bucket string) (map[string]json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	all, err := s.client.HGetAll(ctx, s.prefix+bucket).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", bucket, err)
+	}
+	out := make(map[string]json.RawMessage, len(all))
+	for k, v := range all {
+		out[k] = json.RawMessage(v)
+	}
+	return out, nil
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(bucket, key string) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	raw, err := s.client.HGet(ctx, s.prefix+bucket, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s/%s: %w", bucket, key, err)
+	}
+	return raw, nil
+}
+
+// Update implements Store. It watches the bucket's hash while reading the key and
+// writes in a transaction, starting over if another instance changed the hash in
+// between.
+func (s *RedisStore) Update(bucket, key string, update func(json.RawMessage) (interface{}, error)) error {
+	if bucket == "" || key == "" {
+		return errors.New("bucket and key must not be empty")
+	}
+	hash := s.prefix + bucket
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	var updateErr error
+	apply := func(tx *redis.Tx) error {
+		current, err := tx.HGet(ctx, hash, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			current = nil
+		} else if err != nil {
+			return err
+		}
+		value, err := update(current)
+		if err != nil {
+			updateErr = err
+			return err
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			updateErr = fmt.Errorf("failed to marshal %s/%s: %w", bucket, key, err)
+			return updateErr
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, hash, key, raw)
+			return nil
+		})
+		return err
+	}
+
+	for try := 0; try < maxUpdateTries; try++ {
+		updateErr = nil
+		err := s.client.Watch(ctx, apply, hash)
+		switch {
+		case updateErr != nil:
+			return updateErr
+		case errors.Is(err, redis.TxFailedErr):
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to update %s/%s: %w", bucket, key, err)
+		default:
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update %s/%s: changed by other instances %d times in a row", bucket, key, maxUpdateTries)
+}
+
+// Lease implements Store. Each lease is a Redis key holding its holder's name that
+// expires with the lease.
+func (s *RedisStore) Lease(key, holder string, ttl time.Duration) (bool, error) {
+	if key == "" || holder == "" {
+		return false, errors.New("lease key and holder must not be empty")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	n, err := leaseScript.Run(ctx, s.client, []string{s.leaseKey(key)}, holder, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to lease %s: %w", key, err)
+	}
+	return n == 1, nil
+}
+
+// Release implements Store.
+func (s *RedisStore) Release(key, holder string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if err := releaseScript.Run(ctx, s.client, []string{s.leaseKey(key)}, holder).Err(); err != nil {
+		return fmt.Errorf("failed to release %s: %w", key, err)
+	}
+	return nil
+}
+
+// leaseKey is the Redis key holding the lease on key.
+func (s *RedisStore) leaseKey(key string) string {
+	return s.prefix + "lease:" + key
+}
+
+// Close releases the Redis connection pool.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
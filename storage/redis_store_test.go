@@ -0,0 +1,109 @@
+// file: storage/redis_store_test.go
+//go:build unit
+// +build unit
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	srv := miniredis.RunT(t)
+	s, err := NewRedisStore("redis://"+srv.Addr(), "")
+	require.NoError(t, err)
+	defer s.Close()
+
+	exerciseStore(t, s)
+}
+
+func TestRedisStore_SharedBetweenInstances(t *testing.T) {
+	srv := miniredis.RunT(t)
+	a, err := NewRedisStore("redis://"+srv.Addr(), "test:")
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := NewRedisStore("redis://"+srv.Addr(), "test:")
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, a.Put("occupancy", "Meet A", doc{Name: "left"}))
+
+	all, err := b.List("occupancy")
+	require.NoError(t, err)
+	var got doc
+	require.NoError(t, json.Unmarshal(all["Meet A"], &got))
+	assert.Equal(t, "left", got.Name)
+	assert.True(t, srv.Exists("test:occupancy"))
+}
+
+func TestRedisStore_UpdateIsAtomicBetweenInstances(t *testing.T) {
+	srv := miniredis.RunT(t)
+	stores := make([]*RedisStore, 4)
+	for i := range stores {
+		s, err := NewRedisStore("redis://"+srv.Addr(), "test:")
+		require.NoError(t, err)
+		defer s.Close()
+		stores[i] = s
+	}
+
+	// every instance tries to take the same seat; exactly one may
+	taken := errors.New("taken")
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	for i, s := range stores {
+		wg.Add(1)
+		go func(name string, s *RedisStore) {
+			defer wg.Done()
+			err := s.Update("occupancy", "Meet A", func(current json.RawMessage) (interface{}, error) {
+				if current != nil {
+					return nil, taken
+				}
+				return doc{Name: name}, nil
+			})
+			if err == nil {
+				wins.Add(1)
+			} else {
+				assert.ErrorIs(t, err, taken)
+			}
+		}(fmt.Sprintf("instance-%d", i), s)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), wins.Load(), "only one instance takes the seat")
+}
+
+func TestRedisStore_LeaseExpires(t *testing.T) {
+	srv := miniredis.RunT(t)
+	a, err := NewRedisStore("redis://"+srv.Addr(), "test:")
+	require.NoError(t, err)
+	defer a.Close()
+	b, err := NewRedisStore("redis://"+srv.Addr(), "test:")
+	require.NoError(t, err)
+	defer b.Close()
+
+	ok, err := a.Lease("platform", "a", 10*time.Second)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = b.Lease("platform", "b", 10*time.Second)
+	require.NoError(t, err)
+	assert.False(t, ok, "instances sharing Redis see each other's leases")
+
+	srv.FastForward(11 * time.Second)
+	ok, err = b.Lease("platform", "b", 10*time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok, "an expired lease can be taken")
+}
+
+func TestNewRedisStore_Unreachable(t *testing.T) {
+	_, err := NewRedisStore("redis://127.0.0.1:1", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,318 @@
+// Package storage persists small pieces of runtime state (referee seats, running timers)
+// so that a restart in the middle of a flight does not log everyone out.
+// File: storage/store.go
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// ------------------------ store interface ------------------------
+
+// Store is a bucketed key/value store of JSON documents. Implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Put stores value (marshalled to JSON) under bucket/key, replacing any previous value.
+	Put(bucket, key string, value interface{}) error
+	// Delete removes bucket/key. Deleting a missing key is not an error.
+	Delete(bucket, key string) error
+	// List returns every document in a bucket, keyed by key.
+	List(bucket string) (map[string]json.RawMessage, error)
+	// Get returns the document at bucket/key, or nil if there is none.
+	Get(bucket, key string) (json.RawMessage, error)
+	// Update replaces bucket/key with what update returns for its current document (nil
+	// if there is none), atomically with every other Update of the key, including those
+	// of other instances sharing the store. update may run more than once and must not
+	// use the store. If it returns an error nothing is written and Update returns it.
+	Update(bucket, key string, update func(current json.RawMessage) (interface{}, error)) error
+	// Lease gives key to holder for ttl, or extends the lease holder already has. It
+	// reports false while another holder's lease is live, so only one of the
+	// instances sharing a store acts on key at a time.
+	Lease(key, holder string, ttl time.Duration) (bool, error)
+	// Release ends holder's lease on key. A lease held by someone else is left alone.
+	Release(key, holder string) error
+}
+
+// buckets is the in-memory layout shared by both implementations.
+type buckets map[string]map[string]json.RawMessage
+
+func (b buckets) put(bucket, key string, value interface{}) error {
+	if bucket == "" || key == "" {
+		return errors.New("bucket and key must not be empty")
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s/%s: %w", bucket, key, err)
+	}
+	if b[bucket] == nil {
+		b[bucket] = make(map[string]json.RawMessage)
+	}
+	b[bucket][key] = raw
+	return nil
+}
+
+func (b buckets) get(bucket, key string) json.RawMessage {
+	raw, ok := b[bucket][key]
+	if !ok {
+		return nil
+	}
+	return append(json.RawMessage(nil), raw...)
+}
+
+func (b buckets) update(bucket, key string, update func(json.RawMessage) (interface{}, error)) error {
+	if bucket == "" || key == "" {
+		return errors.New("bucket and key must not be empty")
+	}
+	value, err := update(b.get(bucket, key))
+	if err != nil {
+		return err
+	}
+	return b.put(bucket, key, value)
+}
+
+func (b buckets) list(bucket string) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage, len(b[bucket]))
+	for k, v := range b[bucket] {
+		out[k] = append(json.RawMessage(nil), v...)
+	}
+	return out
+}
+
+// lease is one holder's claim on a key until it expires.
+type lease struct {
+	holder  string
+	expires time.Time
+}
+
+// leases is the in-process lease table shared by both implementations. Leases are
+// not written to the snapshot file; only one process uses these stores.
+type leases map[string]lease
+
+func (l leases) take(key, holder string, ttl time.Duration) (bool, error) {
+	if key == "" || holder == "" {
+		return false, errors.New("lease key and holder must not be empty")
+	}
+	now := time.Now()
+	if cur, ok := l[key]; ok && cur.holder != holder && now.Before(cur.expires) {
+		return false, nil
+	}
+	l[key] = lease{holder: holder, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (l leases) release(key, holder string) {
+	if cur, ok := l[key]; ok && cur.holder == holder {
+		delete(l, key)
+	}
+}
+
+// ------------------------ in-memory implementation ------------------------
+
+// MemoryStore keeps everything in memory. State is lost on restart; use it for
+// tests and single-shot development runs.
+type MemoryStore struct {
+	mu     sync.Mutex
+	data   buckets
+	leases leases
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: make(buckets), leases: make(leases)}
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(bucket, key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.put(bucket, key, value)
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data[bucket], key)
+	return nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(bucket string) (map[string]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.list(bucket), nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(bucket, key string) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.get(bucket, key), nil
+}
+
+// Update implements Store.
+func (s *MemoryStore) Update(bucket, key string, update func(json.RawMessage) (interface{}, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.update(bucket, key, update)
+}
+
+// Lease implements Store.
+func (s *MemoryStore) Lease(key, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leases.take(key, holder, ttl)
+}
+
+// Release implements Store.
+func (s *MemoryStore) Release(key, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases.release(key, holder)
+	return nil
+}
+
+// ------------------------ file implementation ------------------------
+
+// FileStore keeps everything in memory and rewrites a single JSON file on every
+// change. Writes go to a temporary file that is synced and renamed into place,
+// so a crash mid-write leaves the previous snapshot intact.
+type FileStore struct {
+	path   string
+	mu     sync.Mutex
+	data   buckets
+	leases leases
+}
+
+// NewFileStore opens (or creates) the snapshot file at path.
+func NewFileStore(path string) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("state file path must not be empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	s := &FileStore{path: path, data: make(buckets), leases: make(leases)}
+	raw, err := os.ReadFile(path) // #nosec G304
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logger.Info.Printf("[NewFileStore] No state file at %s; starting empty", path)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	case len(raw) > 0:
+		if err := json.Unmarshal(raw, &s.data); err != nil {
+			return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+		}
+		logger.Info.Printf("[NewFileStore] Loaded state from %s", path)
+	}
+	return s, nil
+}
+
+// Put implements Store.
+func (s *FileStore) Put(bucket, key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.data.put(bucket, key, value); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[bucket][key]; !ok {
+		return nil
+	}
+	delete(s.data[bucket], key)
+	return s.flush()
+}
+
+// List implements Store.
+func (s *FileStore) List(bucket string) (map[string]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.list(bucket), nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(bucket, key string) (json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.get(bucket, key), nil
+}
+
+// Update implements Store.
+func (s *FileStore) Update(bucket, key string, update func(json.RawMessage) (interface{}, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.data.update(bucket, key, update); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// Lease implements Store.
+func (s *FileStore) Lease(key, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leases.take(key, holder, ttl)
+}
+
+// Release implements Store.
+func (s *FileStore) Release(key, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases.release(key, holder)
+	return nil
+}
+
+// flush writes the snapshot atomically. Caller must hold s.mu.
+func (s *FileStore) flush() error {
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return WriteFileAtomic(s.path, raw, 0600)
+}
+
+// WriteFileAtomic writes data to a temporary file in the same directory, syncs it
+// and renames it over path, so readers never observe a partially written file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to chmod temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// file: storage/store_test.go
+//go:build unit
+// +build unit
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type doc struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func exerciseStore(t *testing.T, s Store) {
+	require.NoError(t, s.Put("things", "a", doc{Name: "a", Count: 1}))
+	require.NoError(t, s.Put("things", "b", doc{Name: "b", Count: 2}))
+	require.NoError(t, s.Put("things", "a", doc{Name: "a", Count: 3}))
+	require.NoError(t, s.Put("other", "a", doc{Name: "other"}))
+
+	all, err := s.List("things")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	var a doc
+	require.NoError(t, json.Unmarshal(all["a"], &a))
+	assert.Equal(t, 3, a.Count, "Put should replace the previous value")
+
+	require.NoError(t, s.Delete("things", "a"))
+	require.NoError(t, s.Delete("things", "missing"))
+	all, err = s.List("things")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Contains(t, all, "b")
+
+	empty, err := s.List("nothing-here")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	assert.Error(t, s.Put("", "a", doc{}))
+	assert.Error(t, s.Put("things", "", doc{}))
+
+	exerciseGetAndUpdate(t, s)
+	exerciseLeases(t, s)
+}
+
+func exerciseGetAndUpdate(t *testing.T, s Store) {
+	raw, err := s.Get("counters", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, raw)
+
+	increment := func(current json.RawMessage) (interface{}, error) {
+		var d doc
+		if current != nil {
+			if err := json.Unmarshal(current, &d); err != nil {
+				return nil, err
+			}
+		}
+		d.Count++
+		return d, nil
+	}
+	require.NoError(t, s.Update("counters", "c", increment))
+	require.NoError(t, s.Update("counters", "c", increment))
+
+	raw, err = s.Get("counters", "c")
+	require.NoError(t, err)
+	var got doc
+	require.NoError(t, json.Unmarshal(raw, &got))
+	assert.Equal(t, 2, got.Count)
+
+	refused := errors.New("refused")
+	err = s.Update("counters", "c", func(json.RawMessage) (interface{}, error) { return nil, refused })
+	assert.ErrorIs(t, err, refused)
+	raw, err = s.Get("counters", "c")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &got))
+	assert.Equal(t, 2, got.Count, "a refused update writes nothing")
+
+	assert.Error(t, s.Update("", "c", increment))
+}
+
+func exerciseLeases(t *testing.T, s Store) {
+	ok, err := s.Lease("platform", "one", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = s.Lease("platform", "two", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "a live lease cannot be taken by another holder")
+	ok, err = s.Lease("platform", "one", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "the holder can renew its lease")
+
+	require.NoError(t, s.Release("platform", "two"))
+	ok, err = s.Lease("platform", "two", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "only the holder can release a lease")
+
+	require.NoError(t, s.Release("platform", "one"))
+	ok, err = s.Lease("platform", "two", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "a released lease can be taken")
+
+	_, err = s.Lease("", "one", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	exerciseStore(t, NewMemoryStore())
+}
+
+func TestMemoryStore_LeaseExpires(t *testing.T) {
+	s := NewMemoryStore()
+	ok, err := s.Lease("platform", "one", 10*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+	ok, err = s.Lease("platform", "two", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "an expired lease can be taken")
+}
+
+func TestFileStore(t *testing.T) {
+	exerciseStore(t, mustFileStore(t, filepath.Join(t.TempDir(), "state.json")))
+}
+
+func TestFileStore_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	s := mustFileStore(t, path)
+	require.NoError(t, s.Put("occupancy", "Meet A", doc{Name: "left", Count: 1}))
+
+	reopened := mustFileStore(t, path)
+	all, err := reopened.List("occupancy")
+	require.NoError(t, err)
+	var got doc
+	require.NoError(t, json.Unmarshal(all["Meet A"], &got))
+	assert.Equal(t, doc{Name: "left", Count: 1}, got)
+}
+
+func TestFileStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0600))
+
+	_, err := NewFileStore(path)
+	assert.Error(t, err)
+}
+
+func TestWriteFileAtomic_LeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.json")
+	require.NoError(t, WriteFileAtomic(path, []byte("one"), 0600))
+	require.NoError(t, WriteFileAtomic(path, []byte("two"), 0600))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func mustFileStore(t *testing.T, path string) *FileStore {
+	t.Helper()
+	s, err := NewFileStore(path)
+	require.NoError(t, err)
+	return s
+}
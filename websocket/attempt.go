@@ -0,0 +1,121 @@
+// Package websocket - websocket/attempt.go
+// file: websocket/attempt.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// AttemptSource says what opened an attempt.
+type AttemptSource string
+
+const (
+	AttemptFromPlatform AttemptSource = "platform" // the platform's first attempt since it was created or reset
+	AttemptFromClock    AttemptSource = "clock"    // the platform ready clock was started
+	AttemptFromFeed     AttemptSource = "feed"     // an external attempt feed such as OpenLifter named the next lifter
+	AttemptFromResults  AttemptSource = "results"  // the previous result cleared from the lights
+	AttemptFromRestart  AttemptSource = "restart"  // the server restarted, losing any decisions in progress
+)
+
+// Attempt is one lift on a platform, from when it is called until its result clears.
+// Every websocket message to or from the platform carries the ID of the attempt it is
+// about, so either side can drop messages for an attempt that has moved on.
+type Attempt struct {
+	ID        int64         `json:"id"`     // increases by one with every attempt on the platform
+	Source    AttemptSource `json:"source"` // what opened the attempt
+	Phase     DecisionPhase `json:"phase"`  // where the attempt's decisions stand
+	StartedAt time.Time     `json:"startedAt"`
+}
+
+// retiredAttemptIDs keeps the last attempt ID of platforms whose state was cleared, so
+// IDs keep increasing when the platform is used again.
+var retiredAttemptIDs = make(map[string]int64)
+
+// attempt returns the attempt under way, opening the platform's first attempt for
+// states not created by GetMeetState. Callers hold decisionMu.
+func (ms *MeetState) attempt() *Attempt {
+	if ms.Attempt == nil {
+		ms.Attempt = &Attempt{ID: 1, Source: AttemptFromPlatform, Phase: PhaseOpen, StartedAt: time.Now()}
+	}
+	return ms.Attempt
+}
+
+// nextAttempt opens the attempt after the current one and clears the decisions.
+// Callers hold decisionMu.
+func (ms *MeetState) nextAttempt(source AttemptSource) *Attempt {
+	var id int64 = 1
+	if ms.Attempt != nil {
+		id = ms.Attempt.ID + 1
+	}
+	ms.resetDecisions()
+	ms.Attempt = &Attempt{ID: id, Source: source, Phase: PhaseOpen, StartedAt: time.Now()}
+	return ms.Attempt
+}
+
+// startAttempt opens a new attempt on a platform and tells its clients.
+func startAttempt(meetState *MeetState, source AttemptSource) *Attempt {
+	meetState.decisionMu.Lock()
+	attempt := meetState.nextAttempt(source)
+	meetState.decisionMu.Unlock()
+
+	logger.Info.Printf("[startAttempt] meet=%s attempt=%d opened by %s", meetState.MeetName, attempt.ID, source)
+	saveMeetState(meetState)
+	announceAttempt(meetState, attempt)
+	return attempt
+}
+
+// currentAttemptID returns the ID of the attempt under way on a platform, or zero if
+// the platform has no state.
+func currentAttemptID(meetName string) int64 {
+	meetsMutex.Lock()
+	state, ok := meets[meetName]
+	meetsMutex.Unlock()
+	if !ok {
+		return 0
+	}
+	state.decisionMu.Lock()
+	defer state.decisionMu.Unlock()
+	if state.Attempt == nil {
+		return 0
+	}
+	return state.Attempt.ID
+}
+
+// checkAttempt refuses a client message sent for an attempt other than the current one.
+// Messages without an attempt ID are accepted, for clients that predate attempts.
+func checkAttempt(meetName string, attemptID int64) error {
+	if attemptID == 0 {
+		return nil
+	}
+	if current := currentAttemptID(meetName); attemptID != current {
+		return fmt.Errorf("%w (sent for attempt %d, the platform is on attempt %d)", ErrStaleAttempt, attemptID, current)
+	}
+	return nil
+}
+
+// stampAttempt adds the platform's current attempt ID to an outbound JSON object that
+// does not already name the attempt it is about.
+func stampAttempt(meetName string, msg []byte) []byte {
+	id := currentAttemptID(meetName)
+	if id == 0 {
+		return msg
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return msg
+	}
+	if _, ok := fields["attemptId"]; ok {
+		return msg
+	}
+	fields["attemptId"], _ = json.Marshal(id)
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return msg
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+// file: websocket/attempt_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureMeetBroadcasts records the messages sent through broadcastToMeet until the test ends.
+func captureMeetBroadcasts(t *testing.T) *[]map[string]interface{} {
+	var sent []map[string]interface{}
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(message, &msg))
+		sent = append(sent, msg)
+	}
+	t.Cleanup(func() { broadcastToMeet = origBroadcast })
+	return &sent
+}
+
+func TestStartAttempt_IDsIncrease(t *testing.T) {
+	meetState := lifecycleState(t, "AttemptMeet")
+	sent := captureMeetBroadcasts(t)
+	first := meetState.Attempt
+	assert.Equal(t, AttemptFromPlatform, first.Source)
+
+	meetState.JudgeDecisions["left"] = DecisionWhite
+	next := startAttempt(meetState, AttemptFromClock)
+	assert.Equal(t, first.ID+1, next.ID)
+	assert.Equal(t, AttemptFromClock, next.Source)
+	assert.Equal(t, PhaseOpen, next.Phase)
+	assert.Empty(t, meetState.JudgeDecisions)
+
+	require.Len(t, *sent, 1)
+	assert.Equal(t, "decisionsOpen", (*sent)[0]["action"])
+	assert.EqualValues(t, next.ID, (*sent)[0]["attemptId"])
+	assert.Equal(t, "clock", (*sent)[0]["source"])
+
+	// a cleared platform carries on from its last ID
+	ClearMeetState("AttemptMeet")
+	assert.Equal(t, next.ID+1, GetMeetState("AttemptMeet").Attempt.ID)
+}
+
+func TestSetCurrentAttempt_OpensAttemptUnlessDeciding(t *testing.T) {
+	meetState := lifecycleState(t, "AttemptMeet")
+	captureMeetBroadcasts(t)
+	id := meetState.Attempt.ID
+
+	require.NoError(t, SetCurrentAttempt("AttemptMeet", validAttempt()))
+	assert.Equal(t, id+1, meetState.Attempt.ID)
+	assert.Equal(t, AttemptFromFeed, meetState.Attempt.Source)
+
+	// a correction while the referees are deciding keeps their attempt
+	_, err := acceptDecision(meetState, DecisionMessage{JudgeID: "left", Decision: DecisionWhite}, []string{}, meetState.Attempt.StartedAt)
+	require.NoError(t, err)
+	require.NoError(t, SetCurrentAttempt("AttemptMeet", validAttempt()))
+	assert.Equal(t, id+1, meetState.Attempt.ID)
+	assert.Equal(t, DecisionWhite, meetState.JudgeDecisions["left"])
+}
+
+func TestStampAttempt(t *testing.T) {
+	meetState := lifecycleState(t, "AttemptMeet")
+	id := meetState.Attempt.ID
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(stampAttempt("AttemptMeet", []byte(`{"action":"updatePlatformReadyTime","timeLeft":42}`)), &msg))
+	assert.EqualValues(t, id, msg["attemptId"])
+	assert.EqualValues(t, 42, msg["timeLeft"])
+
+	own := []byte(`{"action":"displayResults","attemptId":3}`)
+	assert.Equal(t, own, stampAttempt("AttemptMeet", own), "messages about a given attempt keep its ID")
+	assert.Equal(t, []byte("not json"), stampAttempt("AttemptMeet", []byte("not json")))
+	assert.Equal(t, own, stampAttempt("NoSuchPlatform", own))
+}
+
+func TestHandleIncoming_DropsStaleClockActions(t *testing.T) {
+	meetState := lifecycleState(t, "AttemptMeet")
+	captureMeetBroadcasts(t)
+
+	chief := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AttemptMeet", judgeID: "center"}
+	handleIncoming(chief, DecisionMessage{Action: "startTimer", AttemptID: meetState.Attempt.ID - 1})
+
+	require.Len(t, chief.send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-chief.send, &frame))
+	assert.Equal(t, "timerActionRejected", frame["action"])
+	assert.Contains(t, frame["message"], ErrStaleAttempt.Error())
+	assert.False(t, meetState.PlatformReadyActive, "a stale startTimer must not start the clock")
+}
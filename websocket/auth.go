@@ -0,0 +1,117 @@
+// Package websocket - websocket/auth.go
+// file: websocket/auth.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
+)
+
+// Identity is who a connection belongs to. It is established by the HTTP layer from
+// the session during the upgrade and never taken from client messages.
+type Identity struct {
+	MeetName string // ID of the meet the connection may read and act on
+	Platform string // platform within the meet; empty for the main platform
+	User     string // session user, if logged in
+	JudgeID  string // seat held by User (a position on the meet's panel); empty for displays and admins
+	Admin    bool   // session role grants control_timer
+	Jury     bool   // JudgeID is a jury seat and the session role grants amend_decision
+}
+
+// seatLookup resolves who is seated in a position on a platform, given its state key
+// (injected to avoid importing services).
+var seatLookup func(meetName, position string) string
+
+// SetSeatLookup wires the function used to check seat assignments.
+func SetSeatLookup(lookup func(meetName, position string) string) {
+	seatLookup = lookup
+}
+
+// authorizeDecision checks a submitDecision message against the seat bound to the connection.
+func authorizeDecision(c *Connection, dm DecisionMessage) error {
+	if c.judgeID == "" {
+		return fmt.Errorf("connection is not seated as a referee")
+	}
+	if dm.JudgeID != c.judgeID {
+		return fmt.Errorf("decision for %q does not match seat %q", dm.JudgeID, c.judgeID)
+	}
+	if !panelFor(c.meetName).IsReferee(c.judgeID) {
+		return fmt.Errorf("seat %q does not vote", c.judgeID)
+	}
+	// the seat may have been vacated or reassigned since the connection was opened
+	if seatLookup != nil && c.user != "" && seatLookup(c.meetName, c.judgeID) != c.user {
+		return fmt.Errorf("seat %q is no longer held by %s", c.judgeID, c.user)
+	}
+	return nil
+}
+
+// authorizeTimerControl allows only the chief referee (the center referee on the
+// usual panel) or an admin to start, stop, pause, resume or adjust the platform ready
+// clock and to clear the lights.
+func authorizeTimerControl(c *Connection) error {
+	if c.isAdmin {
+		return nil
+	}
+	if c.judgeID == "" || c.judgeID != panelFor(c.meetName).Chief {
+		return fmt.Errorf("only the chief referee or an admin can control the clock")
+	}
+	if seatLookup != nil && c.user != "" && seatLookup(c.meetName, c.judgeID) != c.user {
+		return fmt.Errorf("seat %q is no longer held by %s", c.judgeID, c.user)
+	}
+	return nil
+}
+
+// authorizeCurrentAttempt allows only an admin (a session role granting control_timer)
+// to name the lifter on the platform, since doing so opens a new attempt and clears the
+// decisions in progress. OpenLifter names lifters through its own bearer-protected endpoint.
+func authorizeCurrentAttempt(c *Connection) error {
+	if !c.isAdmin {
+		return fmt.Errorf("only an admin can set the current attempt")
+	}
+	return nil
+}
+
+// sendToConnection queues a message for one connection only.
+func sendToConnection(c *Connection, msg interface{}) {
+	out, err := json.Marshal(msg)
+	if err != nil {
+		logger.Error.Printf("[sendToConnection] Error marshalling message: %v", err)
+		return
+	}
+	out = stampAttempt(c.meetName, out)
+	select {
+	case c.send <- out:
+	default:
+		logger.Warn.Printf("[sendToConnection] Dropping message for connection %v", c.conn.RemoteAddr())
+	}
+}
+
+// rejectDecision tells the sender why its decision was not counted.
+func rejectDecision(c *Connection, dm DecisionMessage, err error) {
+	logger.Warn.Printf("Rejected decision from %v (judgeId=%s, meet=%s): %v",
+		c.conn.RemoteAddr(), dm.JudgeID, c.meetName, err)
+	msg := protocol.DecisionRejected{
+		Header:  protocol.NewHeader(protocol.ActionDecisionRejected, c.meetName),
+		JudgeID: dm.JudgeID,
+		Message: err.Error(),
+	}
+	msg.RequestID = dm.RequestID
+	sendToConnection(c, msg)
+}
+
+// rejectTimerAction tells the sender why its clock control was not applied.
+func rejectTimerAction(c *Connection, dm DecisionMessage, err error) {
+	logger.Warn.Printf("Rejected %s from %v (judgeId=%q, meet=%s): %v",
+		dm.Action, c.conn.RemoteAddr(), c.judgeID, c.meetName, err)
+	msg := protocol.TimerActionRejected{
+		Header:    protocol.NewHeader(protocol.ActionTimerActionRejected, c.meetName),
+		Requested: dm.Action,
+		Message:   err.Error(),
+	}
+	msg.RequestID = dm.RequestID
+	sendToConnection(c, msg)
+}
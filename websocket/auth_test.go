@@ -0,0 +1,218 @@
+// file: websocket/auth_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-ref-lights/protocol"
+)
+
+func TestAuthorizeDecision(t *testing.T) {
+	defer SetSeatLookup(nil)
+	SetSeatLookup(func(meetName, position string) string {
+		if position == "left" {
+			return "ref1"
+		}
+		return ""
+	})
+
+	seated := &Connection{meetName: "AuthMeet", judgeID: "left", user: "ref1"}
+	assert.NoError(t, authorizeDecision(seated, DecisionMessage{JudgeID: "left"}))
+	assert.Error(t, authorizeDecision(seated, DecisionMessage{JudgeID: "right"}), "cannot vote for another seat")
+
+	display := &Connection{meetName: "AuthMeet"}
+	assert.Error(t, authorizeDecision(display, DecisionMessage{JudgeID: "left"}), "displays cannot vote")
+
+	vacated := &Connection{meetName: "AuthMeet", judgeID: "center", user: "ref2"}
+	assert.Error(t, authorizeDecision(vacated, DecisionMessage{JudgeID: "center"}), "seat no longer held")
+}
+
+func TestAuthorizeTimerControl(t *testing.T) {
+	defer SetSeatLookup(nil)
+	SetSeatLookup(func(meetName, position string) string {
+		if position == "center" {
+			return "headref"
+		}
+		return "sideref"
+	})
+
+	assert.NoError(t, authorizeTimerControl(&Connection{meetName: "AuthMeet", judgeID: "center", user: "headref"}))
+	assert.NoError(t, authorizeTimerControl(&Connection{meetName: "AuthMeet", isAdmin: true}))
+	assert.Error(t, authorizeTimerControl(&Connection{meetName: "AuthMeet", judgeID: "left", user: "sideref"}),
+		"side referees cannot control the clock")
+	assert.Error(t, authorizeTimerControl(&Connection{meetName: "AuthMeet"}), "displays cannot control the clock")
+	assert.Error(t, authorizeTimerControl(&Connection{meetName: "AuthMeet", judgeID: "center", user: "former"}),
+		"a vacated center seat loses control")
+}
+
+func TestAuthorize_ConfiguredPanel(t *testing.T) {
+	defer SetSeatLookup(nil)
+	defer SetPanelLookup(nil)
+	SetSeatLookup(func(meetName, position string) string { return position + "-user" })
+	SetPanelLookup(func(meetID string) (Panel, bool) {
+		return Panel{Referees: []string{"referee"}, Jury: []string{"jury"}, Chief: "referee"}, true
+	})
+
+	referee := &Connection{meetName: "AuthMeet", judgeID: "referee", user: "referee-user"}
+	assert.NoError(t, authorizeDecision(referee, DecisionMessage{JudgeID: "referee"}))
+	assert.NoError(t, authorizeTimerControl(referee), "the only referee is the chief")
+
+	jury := &Connection{meetName: "AuthMeet", judgeID: "jury", user: "jury-user"}
+	assert.Error(t, authorizeDecision(jury, DecisionMessage{JudgeID: "jury"}), "jury seats do not vote")
+	assert.Error(t, authorizeTimerControl(jury), "jury seats do not run the clock")
+}
+
+func TestHandleIncoming_RejectsTimerControlFromSideReferee(t *testing.T) {
+	InitTest()
+	ClearMeetState("AuthMeet")
+	meetState := GetMeetState("AuthMeet")
+	meetState.PlatformReadyActive = true
+	meetState.PlatformReadyEnd = time.Now().Add(30 * time.Second)
+	defer ClearMeetState("AuthMeet")
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AuthMeet", judgeID: "left"}
+	handleIncoming(conn, DecisionMessage{Action: "pauseTimer", MeetName: "AuthMeet"})
+
+	assert.False(t, meetState.platformReadyPaused(), "clock must keep running")
+	require.Len(t, conn.send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-conn.send, &frame))
+	assert.Equal(t, "timerActionRejected", frame["action"])
+	assert.Equal(t, "pauseTimer", frame["requested"])
+}
+
+func TestHandleIncoming_RejectsClockAndLightsFromViewer(t *testing.T) {
+	InitTest()
+	ClearMeetState("AuthMeet")
+	defer ClearMeetState("AuthMeet")
+	defer CancelPlatformReadyTimer("AuthMeet")
+	meetState := GetMeetState("AuthMeet")
+
+	var broadcasts []string
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) { broadcasts = append(broadcasts, string(message)) }
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	viewer := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AuthMeet"}
+	for _, action := range []string{"startTimer", "resetLights", "resetTimer"} {
+		handleIncoming(viewer, DecisionMessage{Action: action, MeetName: "AuthMeet"})
+
+		require.Len(t, viewer.send, 1, action)
+		var frame map[string]interface{}
+		require.NoError(t, json.Unmarshal(<-viewer.send, &frame))
+		assert.Equal(t, "timerActionRejected", frame["action"])
+		assert.Equal(t, action, frame["requested"])
+	}
+	assert.False(t, meetState.PlatformReadyActive, "a viewer cannot start the clock")
+	assert.Empty(t, broadcasts, "a viewer cannot clear the lights or stop the clock")
+
+	admin := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AuthMeet", isAdmin: true}
+	handleIncoming(admin, DecisionMessage{Action: "resetLights", MeetName: "AuthMeet"})
+	assert.Empty(t, admin.send)
+	assert.Len(t, broadcasts, 1)
+}
+
+func TestHandleIncoming_RejectsMismatchedDecision(t *testing.T) {
+	InitTest()
+	ClearMeetState("AuthMeet")
+	meetState := GetMeetState("AuthMeet")
+
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AuthMeet", judgeID: "left"}
+	handleIncoming(conn, DecisionMessage{
+		Action:   "submitDecision",
+		MeetName: "AuthMeet",
+		JudgeID:  "right",
+		Decision: DecisionWhite,
+	})
+
+	assert.Empty(t, meetState.JudgeDecisions, "spoofed decision must not be counted")
+	require.Len(t, conn.send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-conn.send, &frame))
+	assert.Equal(t, "decisionRejected", frame["action"])
+	assert.Equal(t, "right", frame["judgeId"])
+
+	handleIncoming(conn, DecisionMessage{
+		Action:   "submitDecision",
+		MeetName: "AuthMeet",
+		JudgeID:  "left",
+		Decision: DecisionWhite,
+	})
+	assert.Equal(t, DecisionWhite, meetState.JudgeDecisions["left"])
+}
+
+func TestHandleIncoming_RejectsOtherMeet(t *testing.T) {
+	InitTest()
+	ClearMeetState("Other Meet")
+	other := GetMeetState("Other Meet")
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "AuthMeet", judgeID: "left"}
+	handleIncoming(conn, DecisionMessage{
+		Action:    "submitDecision",
+		MeetName:  "Other Meet",
+		JudgeID:   "left",
+		Decision:  DecisionWhite,
+		RequestID: "left-1",
+	})
+
+	assert.Empty(t, other.JudgeDecisions)
+	require.Len(t, conn.send, 1)
+	var frame protocol.Error
+	require.NoError(t, json.Unmarshal(<-conn.send, &frame))
+	assert.Equal(t, protocol.CodeRejected, frame.Code)
+	assert.Equal(t, "submitDecision", frame.Requested)
+	assert.Equal(t, "left-1", frame.RequestID)
+}
+
+func TestHandleIncoming_AcknowledgesRequests(t *testing.T) {
+	InitTest()
+	ClearMeetState("AuthMeet")
+	defer ClearMeetState("AuthMeet")
+
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 2), meetName: "AuthMeet", judgeID: "left"}
+	handleIncoming(conn, DecisionMessage{Action: "submitDecision", JudgeID: "left", Decision: DecisionWhite, RequestID: "left-1"})
+	require.Len(t, conn.send, 1)
+	var ack protocol.Ack
+	require.NoError(t, json.Unmarshal(<-conn.send, &ack))
+	assert.Equal(t, protocol.ActionAck, ack.Action)
+	assert.Equal(t, "submitDecision", ack.Requested)
+	assert.Equal(t, "left-1", ack.RequestID)
+
+	handleIncoming(conn, DecisionMessage{Action: "submitDecision", JudgeID: "left", Decision: DecisionRed, Cards: []string{CardRed}})
+	assert.Empty(t, conn.send, "messages without a requestId are not acknowledged")
+
+	handleIncoming(conn, DecisionMessage{Action: "submitDecision", JudgeID: "right", Decision: DecisionWhite, RequestID: "left-2"})
+	require.Len(t, conn.send, 1)
+	var rejected map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-conn.send, &rejected))
+	assert.Equal(t, "decisionRejected", rejected["action"])
+	assert.Equal(t, "left-2", rejected["requestId"], "rejections answer the request instead of an ack")
+}
+
+func TestHandleIncoming_RegisterRefDoesNotChangeSeat(t *testing.T) {
+	InitTest()
+	origHealth := broadcastRefereeHealth
+	broadcastRefereeHealth = func(meetName string) {}
+	defer func() { broadcastRefereeHealth = origHealth }()
+
+	conn := &Connection{conn: &fakeConn{}, meetName: "AuthMeet"}
+	handleIncoming(conn, DecisionMessage{Action: "registerRef", MeetName: "AuthMeet", JudgeID: "left"})
+
+	assert.Empty(t, conn.judgeID, "the seat is bound from the session, not registerRef")
+}
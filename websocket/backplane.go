@@ -0,0 +1,110 @@
+// Package websocket - websocket/backplane.go
+// file: websocket/backplane.go
+
+package websocket
+
+import (
+	"errors"
+	"sync"
+
+	"go-ref-lights/logger"
+)
+
+// Backplane fans outbound messages out to every server instance. Each instance
+// subscribes once and delivers what it receives to its own connections. A platform's
+// clients all connect to the instance serving it (see claimPlatform), so the backplane
+// carries messages raised on other instances, such as an admin action, to them.
+type Backplane interface {
+	// Publish sends msg to the connections of one meet on every instance.
+	Publish(meetName string, msg []byte) error
+	// Subscribe calls deliver for every message published by any instance.
+	// It blocks until the backplane is closed, returning nil, or until the
+	// subscription fails, returning the error so the caller can subscribe again.
+	Subscribe(deliver func(meetName string, msg []byte)) error
+	// Close releases the backplane's resources and ends Subscribe.
+	Close() error
+}
+
+// activeBackplane is the backplane used for all outbound traffic.
+var (
+	activeBackplane   Backplane = newLocalBackplane()
+	activeBackplaneMu sync.RWMutex
+)
+
+// SetBackplane replaces the backplane used for outbound traffic. It must be called
+// before HandleMessages starts.
+func SetBackplane(b Backplane) {
+	activeBackplaneMu.Lock()
+	defer activeBackplaneMu.Unlock()
+	activeBackplane = b
+}
+
+// currentBackplane returns the backplane in use.
+func currentBackplane() Backplane {
+	activeBackplaneMu.RLock()
+	defer activeBackplaneMu.RUnlock()
+	return activeBackplane
+}
+
+// publish hands a message to the backplane, logging rather than failing on error.
+// Messages that do not name their attempt are stamped with the current one.
+func publish(meetName string, msg []byte) {
+	if err := currentBackplane().Publish(meetName, stampAttempt(meetName, msg)); err != nil {
+		logger.Error.Printf("[publish] Failed to publish message for meet=%s: %v", meetName, err)
+	}
+}
+
+// errNoMeet is returned when a message is published without a meet to address it to.
+var errNoMeet = errors.New("message has no meet to deliver to")
+
+// deliverLocal writes a message to this instance's connections for the given meet.
+func deliverLocal(meetName string, msg []byte) {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+
+	for c := range connections {
+		if c.meetName != meetName {
+			continue
+		}
+		select {
+		case c.send <- msg:
+		default:
+			logger.Warn.Printf("[deliverLocal] Dropping message for connection %v", c.conn.RemoteAddr())
+		}
+	}
+}
+
+// ------------------------ in-process implementation ------------------------
+
+// localBackplane delivers straight to this instance's connections. It is the
+// default and is all a single-instance deployment needs.
+type localBackplane struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func newLocalBackplane() *localBackplane {
+	return &localBackplane{done: make(chan struct{})}
+}
+
+// Publish implements Backplane.
+func (b *localBackplane) Publish(meetName string, msg []byte) error {
+	if meetName == "" {
+		return errNoMeet
+	}
+	deliverLocal(meetName, msg)
+	return nil
+}
+
+// Subscribe implements Backplane. Messages are already delivered by Publish, so
+// this only waits for Close.
+func (b *localBackplane) Subscribe(deliver func(meetName string, msg []byte)) error {
+	<-b.done
+	return nil
+}
+
+// Close implements Backplane.
+func (b *localBackplane) Close() error {
+	b.once.Do(func() { close(b.done) })
+	return nil
+}
@@ -0,0 +1,118 @@
+// file: websocket/backplane_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func registerFakeConnection(meetName string) *Connection {
+	c := &Connection{conn: &fakeConn{}, send: make(chan []byte, 5), meetName: meetName}
+	connectionsMu.Lock()
+	connections[c] = true
+	connectionsMu.Unlock()
+	return c
+}
+
+func unregisterFakeConnection(c *Connection) {
+	connectionsMu.Lock()
+	delete(connections, c)
+	connectionsMu.Unlock()
+}
+
+func TestLocalBackplane_DeliversToMeetOnly(t *testing.T) {
+	a := registerFakeConnection("Meet A")
+	b := registerFakeConnection("Meet B")
+	defer unregisterFakeConnection(a)
+	defer unregisterFakeConnection(b)
+
+	bp := newLocalBackplane()
+	assert.NoError(t, bp.Publish("Meet A", []byte(`{"action":"judgeSubmitted"}`)))
+
+	assert.Len(t, a.send, 1)
+	assert.Len(t, b.send, 0)
+}
+
+func TestLocalBackplane_RejectsUnaddressedMessages(t *testing.T) {
+	a := registerFakeConnection("Meet A")
+	defer unregisterFakeConnection(a)
+
+	bp := newLocalBackplane()
+	assert.ErrorIs(t, bp.Publish("", []byte(`{"action":"clearResults"}`)), errNoMeet)
+	assert.Len(t, a.send, 0)
+}
+
+func TestLocalBackplane_CloseEndsSubscribe(t *testing.T) {
+	bp := newLocalBackplane()
+	done := make(chan error, 1)
+	go func() { done <- bp.Subscribe(func(string, []byte) {}) }()
+
+	assert.NoError(t, bp.Close())
+	assert.NoError(t, bp.Close(), "closing twice must be safe")
+	assert.NoError(t, <-done)
+}
+
+func TestBroadcastToMeet_UsesBackplane(t *testing.T) {
+	rec := &recordingBackplane{}
+	SetBackplane(rec)
+	defer SetBackplane(newLocalBackplane())
+
+	broadcastToMeet("Meet A", []byte(`{"action":"judgeSubmitted"}`))
+
+	assert.Equal(t, []string{"Meet A"}, rec.meets)
+}
+
+func TestSubscribeBackplane_RetriesFailedSubscriptions(t *testing.T) {
+	origDelay, origMax := backplaneRetryDelay, maxBackplaneRetryDelay
+	backplaneRetryDelay, maxBackplaneRetryDelay = time.Millisecond, 4*time.Millisecond
+	defer func() { backplaneRetryDelay, maxBackplaneRetryDelay = origDelay, origMax }()
+
+	bp := &flakyBackplane{failures: 3}
+	done := make(chan struct{})
+	go func() {
+		subscribeBackplane(bp)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("subscribeBackplane should stop once the backplane is closed")
+	}
+	assert.Equal(t, 4, bp.calls, "each failed subscription should be retried")
+}
+
+// flakyBackplane fails to subscribe a number of times, then behaves as if closed.
+type flakyBackplane struct {
+	recordingBackplane
+	failures int
+	calls    int
+}
+
+func (f *flakyBackplane) Subscribe(func(string, []byte)) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// recordingBackplane remembers which meets it was asked to publish to.
+type recordingBackplane struct {
+	meets []string
+	msgs  [][]byte
+}
+
+func (r *recordingBackplane) Publish(meetName string, msg []byte) error {
+	r.meets = append(r.meets, meetName)
+	r.msgs = append(r.msgs, msg)
+	return nil
+}
+func (r *recordingBackplane) Subscribe(func(string, []byte)) error { return nil }
+func (r *recordingBackplane) Close() error                         { return nil }
@@ -8,11 +8,9 @@ import (
 	"time"
 
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 )
 
-// Allow tests to override the sleep behaviour.
-var sleepFunc = time.Sleep
-
 // StartNextAttemptTimer is an exported wrapper that triggers the next attempt timer for the given meet.
 func StartNextAttemptTimer(meetState *MeetState) {
 	if defaultTimerManager == nil {
@@ -22,41 +20,57 @@ func StartNextAttemptTimer(meetState *MeetState) {
 	defaultTimerManager.startNextAttemptTimer(meetState)
 }
 
-// HandleMessages listens for messages on the broadcast channel and distributes them to connections.
+// Backoff between attempts to subscribe to the backplane after a subscription fails.
+var (
+	backplaneRetryDelay    = time.Second
+	maxBackplaneRetryDelay = 30 * time.Second
+)
+
+// HandleMessages subscribes this instance to the backplane, then forwards every message
+// queued on the broadcast channel to it. Each message only reaches the connections of
+// the meet it is addressed to; unaddressed messages are dropped.
 func HandleMessages() {
+	bp := currentBackplane()
+	go subscribeBackplane(bp)
+
 	for {
 		msg := <-broadcast // Read incoming message from the broadcast channel
-
-		var msgMap map[string]interface{}
-		var meetFilter string
-
-		// attempt to parse the message as JSON
-		if err := json.Unmarshal(msg, &msgMap); err == nil {
-			if m, ok := msgMap["meetName"].(string); ok {
-				meetFilter = m
-			}
+		if msg.MeetName == "" {
+			logger.Warn.Printf("[HandleMessages] Dropping message with no meet: %s", string(msg.Data))
+			continue
 		}
+		if err := bp.Publish(msg.MeetName, stampAttempt(msg.MeetName, msg.Data)); err != nil {
+			logger.Error.Printf("[HandleMessages] Failed to publish broadcast: %v", err)
+		}
+	}
+}
 
-		// Acquire the read lock before iterating the `connections` map
-		connectionsMu.RLock()
-		for c := range connections {
-			// if a meet filter is set, only send to matching connections
-			if meetFilter != "" && c.meetName != meetFilter {
-				continue
-			}
-			select {
-			case c.send <- msg:
-			default:
-				logger.Warn.Printf("[HandleMessages] Dropping broadcast message for connection %v", c.conn.RemoteAddr())
-			}
+// subscribeBackplane keeps this instance subscribed to the backplane until it is
+// closed. A failed subscription is retried with a growing delay, which starts again
+// from backplaneRetryDelay once a subscription has lasted the longest delay.
+func subscribeBackplane(bp Backplane) {
+	delay := backplaneRetryDelay
+	for {
+		started := time.Now()
+		err := bp.Subscribe(deliverLocal)
+		if err == nil {
+			return
+		}
+		if time.Since(started) >= maxBackplaneRetryDelay {
+			delay = backplaneRetryDelay
+		}
+		logger.Error.Printf("[HandleMessages] Backplane subscription failed; retrying in %v: %v", delay, err)
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxBackplaneRetryDelay {
+			delay = maxBackplaneRetryDelay
 		}
-		// Release the read lock
-		connectionsMu.RUnlock()
 	}
 }
 
-// BroadcastMessage sends a message to all WebSocket clients associated with the given meet.
-func BroadcastMessage(meetName string, message map[string]interface{}) {
+// BroadcastMessage sends a message, usually one of the protocol messages, to all
+// WebSocket clients associated with the given meet.
+func BroadcastMessage(meetName string, message interface{}) {
 	logger.Debug.Printf("[BroadcastMessage] Broadcasting next attempt timers for meet=%s", meetName)
 
 	// convert message to JSON
@@ -67,43 +81,131 @@ func BroadcastMessage(meetName string, message map[string]interface{}) {
 	}
 
 	// send the marshalled message to the broadcast channel
-	broadcast <- msg
+	broadcast <- outboundMessage{MeetName: meetName, Data: msg}
+}
+
+// revealedResult is an attempt's result, taken from its platform's state under the
+// decision lock so it can be broadcast, recorded and published without holding it.
+type revealedResult struct {
+	attempt     *Attempt                // attempt the result is for
+	display     protocol.DisplayResults // message sent to the lights
+	cards       map[string][]string     // reason cards as submitted
+	submittedAt map[string]time.Time    // when each decision arrived
 }
 
-// broadcastFinalResults sends the final decisions to all connections in a meet.
-// It then starts the next attempt timer and, after a timeout, broadcasts a "clearResults" message.
+// broadcastFinalResults reveals the decisions held for a meet's current attempt. It
+// then starts the next attempt timer and, after a timeout, broadcasts a "clearResults" message.
 func broadcastFinalResults(meetName string) {
 	meetState := DefaultStateProvider.GetMeetState(meetName) // fetch the current meet state
+	meetState.decisionMu.Lock()
+	res := takeResult(meetState)
+	meetState.decisionMu.Unlock()
+	showResult(meetState, res)
+}
 
-	// prepare the decision submission message
-	submission := map[string]string{
-		"action":         "displayResults",
-		"leftDecision":   meetState.JudgeDecisions["left"],
-		"centerDecision": meetState.JudgeDecisions["center"],
-		"rightDecision":  meetState.JudgeDecisions["right"],
+// takeResult locks the current attempt and moves its decisions and lifter into a
+// result, leaving the platform ready for the next attempt. Callers hold decisionMu.
+func takeResult(meetState *MeetState) *revealedResult {
+	attempt := meetState.attempt()
+	attempt.Phase = PhaseLocked
+
+	// compute the verdict once so every client and integration applies the same rule
+	panel := panelFor(meetState.MeetName)
+	verdict := computeVerdict(meetState.JudgeDecisions, len(panel.Referees))
+
+	// prepare the decision submission message with each referee's decision and reason
+	// cards, in panel order
+	decisions := make(map[string]string, len(panel.Referees))
+	cards := make(map[string][]string, len(panel.Referees))
+	for _, pos := range panel.Referees {
+		decisions[pos] = meetState.JudgeDecisions[pos]
+		cards[pos] = cardsFor(meetState, pos)
 	}
+	header := protocol.NewHeader(protocol.ActionDisplayResults, meetState.MeetName)
+	header.AttemptID = attempt.ID
+	res := &revealedResult{
+		attempt: attempt,
+		display: protocol.DisplayResults{
+			Header:         header,
+			Referees:       panel.Referees,
+			Decisions:      decisions,
+			Cards:          cards,
+			CurrentAttempt: meetState.CurrentAttempt,
+			Verdict:        verdict.Verdict,
+			WhiteCount:     verdict.WhiteCount,
+			RedCount:       verdict.RedCount,
+		},
+		cards:       meetState.JudgeCards,
+		submittedAt: meetState.JudgeSubmittedAt,
+	}
+
+	// the lifter context is consumed by this attempt; late decisions are refused until
+	// the lights clear
+	if meetState.CurrentAttempt != nil {
+		meetState.PreviousLifterID = meetState.CurrentAttempt.LifterID
+	}
+	meetState.CurrentAttempt = nil
+	meetState.resetDecisions()
+	return res
+}
+
+// showResult broadcasts a result taken by takeResult, records it and starts the next
+// attempt timer. The result stays on the lights for the results display time unless
+// the clock or an attempt feed has already moved the platform on.
+func showResult(meetState *MeetState, res *revealedResult) {
+	meetName := meetState.MeetName
+	attemptID := res.attempt.ID
 
 	// convert submission to JSON
-	resultMsg, err := json.Marshal(submission)
+	resultMsg, err := json.Marshal(res.display)
 	if err != nil {
 		logger.Error.Printf("[broadcastFinalResults] Error marshalling final results message: %v", err)
 		return
 	}
-	logger.Info.Printf("[broadcastFinalResults] meet=%s -> 'displayResults' with Left=%s, center=%s, Right=%s",
-		meetName, meetState.JudgeDecisions["left"], meetState.JudgeDecisions["center"], meetState.JudgeDecisions["right"])
+	logger.Info.Printf("[broadcastFinalResults] meet=%s -> 'displayResults' with %v (%s)",
+		meetName, res.display.Decisions, res.display.Verdict)
+
+	// broadcast the results to the meet's clients
+	broadcast <- outboundMessage{MeetName: meetName, Data: resultMsg}
 
-	// broadcast the results to all clients
-	broadcast <- resultMsg
+	// keep a permanent record of the attempt and push the result to OpenLifter
+	recordDecision(meetState, res)
+	publishResult(meetState, res)
+	saveMeetState(meetState)
 
 	// start the next attempt timer
 	StartNextAttemptTimer(meetState)
 
-	// after a timeout, send a message to clear results
-	go func() {
-		sleepFunc(time.Duration(resultsDisplayDuration) * time.Second)
+	meetState.decisionMu.Lock()
+	current := meetState.Attempt == res.attempt && res.attempt.Phase == PhaseLocked
+	if current {
+		res.attempt.Phase = PhaseRevealed
+		meetState.resultsOnLights = attemptID
+		meetState.lastResult = &res.display
+	}
+	meetState.decisionMu.Unlock()
+	if !current {
+		logger.Debug.Printf("[broadcastFinalResults] meet=%s attempt=%d moved on before its result showed", meetName, attemptID)
+		return
+	}
+
+	// after a timeout, send a message to clear results and open the next attempt
+	meetState.after(resultsDisplayFor(meetName), func() {
+		// the clock may already have cleared the lights for a later attempt
+		meetState.decisionMu.Lock()
+		showing := meetState.resultsOnLights == attemptID
+		if showing {
+			meetState.resultsOnLights = 0
+		}
+		meetState.decisionMu.Unlock()
+		if !showing {
+			logger.Debug.Printf("[broadcastFinalResults] meet=%s attempt=%d already cleared", meetName, attemptID)
+			return
+		}
 
-		// prepare a clear message
-		clearMsg := map[string]string{"action": "clearResults"}
+		// prepare a clear message; it goes out under whichever attempt is current, since a
+		// feed may have opened the next one while the result was showing
+		clearMsg := protocol.ClearResults{Header: protocol.NewHeader(protocol.ActionClearResults, meetName)}
 		clearJSON, err := json.Marshal(clearMsg)
 		if err != nil {
 			logger.Error.Printf("[broadcastFinalResults] Error marshalling clearResults: %v", err)
@@ -111,21 +213,26 @@ func broadcastFinalResults(meetName string) {
 		}
 
 		// send the clear message to the broadcast channel
-		broadcast <- clearJSON
-	}()
+		broadcast <- outboundMessage{MeetName: meetName, Data: clearJSON}
+		reopenDecisions(meetState, attemptID)
+	})
+}
 
-	// reset judge decisions for the next round
-	meetState.JudgeDecisions = make(map[string]string)
+// cardsFor returns the reason cards recorded for a judge, never nil so the JSON is always an array.
+func cardsFor(meetState *MeetState, judgeID string) []string {
+	if cards, ok := meetState.JudgeCards[judgeID]; ok && cards != nil {
+		return cards
+	}
+	return []string{}
 }
 
 // broadcastTimeUpdateWithIndex sends a time update message with an index to all clients in the meet.
 func broadcastTimeUpdateWithIndex(action string, timeLeft int, index int, meetName string) {
 	// prepare the time update message
-	msg, err := json.Marshal(map[string]interface{}{
-		"action":   action,
-		"timeLeft": timeLeft,
-		"index":    index,
-		"meetName": meetName,
+	msg, err := json.Marshal(protocol.UpdatePlatformReadyTime{
+		Header:   protocol.NewHeader(action, meetName),
+		TimeLeft: timeLeft,
+		Index:    index,
 	})
 	if err != nil {
 		logger.Error.Printf("[broadcastTimeUpdateWithIndex] Error marshalling time update: %v", err)
@@ -133,10 +240,10 @@ func broadcastTimeUpdateWithIndex(action string, timeLeft int, index int, meetNa
 	}
 
 	// send the time update message to the broadcast channel
-	broadcast <- msg
+	broadcast <- outboundMessage{MeetName: meetName, Data: msg}
 }
 
-// SendBroadcastMessage allows raw byte data to be sent over the broadcast channel
-func SendBroadcastMessage(data []byte) {
-	broadcast <- data
+// SendBroadcastMessage sends raw JSON to every connection in the given meet.
+func SendBroadcastMessage(meetName string, data []byte) {
+	broadcast <- outboundMessage{MeetName: meetName, Data: data}
 }
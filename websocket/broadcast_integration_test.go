@@ -12,12 +12,13 @@ import (
 
 	gws "github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"go-ref-lights/config"
 )
 
 func TestBroadcastMessageDelivery(t *testing.T) {
 	// Step 1: Set up a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ServeWs(w, r)
+		ServeWs(w, r, Identity{MeetName: r.URL.Query().Get("meetName")}, config.Default().WebSocket)
 	}))
 	defer server.Close()
 
@@ -13,7 +13,7 @@ import (
 )
 
 // mockBroadcast is a buffered channel that we use to override the global broadcast.
-var mockBroadcast = make(chan []byte, 10)
+var mockBroadcast = make(chan outboundMessage, 10)
 
 // In init, override the global broadcast channel.
 func init() {
@@ -42,7 +42,7 @@ func TestBroadcastMessage_Success(t *testing.T) {
 	select {
 	case msg := <-mockBroadcast:
 		var decoded map[string]interface{}
-		err := json.Unmarshal(msg, &decoded)
+		err := json.Unmarshal(msg.Data, &decoded)
 		assert.NoError(t, err)
 		assert.Equal(t, "testAction", decoded["action"])
 		assert.Equal(t, "testData", decoded["data"])
@@ -63,16 +63,27 @@ func TestBroadcastFinalResults(t *testing.T) {
 		"center": "no lift",
 		"right":  "good",
 	}
+	mockMeetState.JudgeCards = map[string][]string{
+		"center": {CardRed, CardBlue},
+	}
 
 	broadcastFinalResults("APL Test Meet")
 
 	select {
 	case msg := <-mockBroadcast:
-		var decoded map[string]string
-		err := json.Unmarshal(msg, &decoded)
+		var decoded map[string]interface{}
+		err := json.Unmarshal(msg.Data, &decoded)
 		assert.NoError(t, err)
 		assert.Equal(t, "displayResults", decoded["action"])
-		assert.Equal(t, "good", decoded["leftDecision"])
+		assert.Equal(t, []interface{}{"left", "center", "right"}, decoded["referees"])
+		assert.Equal(t, "good", decoded["decisions"].(map[string]interface{})["left"])
+		cards := decoded["cards"].(map[string]interface{})
+		assert.Equal(t, []interface{}{}, cards["left"])
+		assert.Equal(t, []interface{}{"red", "blue"}, cards["center"])
+		// "good" and "no lift" are not white/red decisions, so they count as neither
+		assert.Equal(t, VerdictNoLift, decoded["verdict"])
+		assert.Equal(t, float64(0), decoded["whiteCount"])
+		assert.Equal(t, float64(0), decoded["redCount"])
 	default:
 		t.Fatal("Expected final results broadcast, but got none")
 	}
@@ -84,8 +95,8 @@ func TestBroadcastFinalResults_ClearsAfterTimeout(t *testing.T) {
 	InitTest()
 	flushBroadcastChannel()
 
-	// Set a short display duration.
-	resultsDisplayDuration = 1
+	// Clear the result straight away.
+	defaultTimerManager.Timers.ResultsDisplay = 0
 
 	// Create a controlled MeetState.
 	mockState := &MeetState{
@@ -100,18 +111,13 @@ func TestBroadcastFinalResults_ClearsAfterTimeout(t *testing.T) {
 	meets["APL Test Meet"] = mockState
 	meetsMutex.Unlock()
 
-	// Override sleepFunc to simulate an immediate timeout.
-	origSleep := sleepFunc
-	sleepFunc = func(d time.Duration) {}
-	defer func() { sleepFunc = origSleep }()
-
 	broadcastFinalResults("APL Test Meet")
 
 	// First message should be displayResults.
 	select {
 	case msg := <-mockBroadcast:
-		var decoded map[string]string
-		err := json.Unmarshal(msg, &decoded)
+		var decoded map[string]interface{}
+		err := json.Unmarshal(msg.Data, &decoded)
 		assert.NoError(t, err)
 		assert.Equal(t, "displayResults", decoded["action"])
 	case <-time.After(100 * time.Millisecond):
@@ -122,7 +128,7 @@ func TestBroadcastFinalResults_ClearsAfterTimeout(t *testing.T) {
 	select {
 	case msg := <-mockBroadcast:
 		var decoded map[string]string
-		err := json.Unmarshal(msg, &decoded)
+		err := json.Unmarshal(msg.Data, &decoded)
 		assert.NoError(t, err)
 		assert.Equal(t, "clearResults", decoded["action"])
 	case <-time.After(100 * time.Millisecond):
@@ -130,6 +136,41 @@ func TestBroadcastFinalResults_ClearsAfterTimeout(t *testing.T) {
 	}
 }
 
+// TestBroadcastFinalResults_ReleasesLockWhileBroadcasting verifies that the platform's
+// decision lock is free while the result waits to be broadcast.
+func TestBroadcastFinalResults_ReleasesLockWhileBroadcasting(t *testing.T) {
+	InitTest()
+	flushBroadcastChannel()
+	ClearMeetState("APL Test Meet")
+	defer ClearMeetState("APL Test Meet")
+	meetState := GetMeetState("APL Test Meet")
+	meetState.JudgeDecisions = map[string]string{"left": DecisionWhite, "center": DecisionWhite, "right": DecisionWhite}
+
+	// a full channel holds the result at the broadcast
+	for len(mockBroadcast) < cap(mockBroadcast) {
+		mockBroadcast <- outboundMessage{}
+	}
+	done := make(chan struct{})
+	go func() {
+		broadcastFinalResults("APL Test Meet")
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		meetState.decisionMu.Lock()
+		defer meetState.decisionMu.Unlock()
+		return meetState.Attempt.Phase == PhaseLocked
+	}, time.Second, 10*time.Millisecond, "the lock should be free while the result is broadcast")
+
+	flushBroadcastChannel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("broadcastFinalResults did not finish")
+	}
+	flushBroadcastChannel()
+}
+
 // TestBroadcastTimeUpdateWithIndex verifies that broadcastTimeUpdateWithIndex sends the correct message.
 func TestBroadcastTimeUpdateWithIndex(t *testing.T) {
 	InitTest()
@@ -140,7 +181,7 @@ func TestBroadcastTimeUpdateWithIndex(t *testing.T) {
 	select {
 	case msg := <-mockBroadcast:
 		var decoded map[string]interface{}
-		err := json.Unmarshal(msg, &decoded)
+		err := json.Unmarshal(msg.Data, &decoded)
 		assert.NoError(t, err)
 		assert.Equal(t, "updateTime", decoded["action"])
 		assert.Equal(t, float64(30), decoded["timeLeft"])
@@ -150,17 +191,18 @@ func TestBroadcastTimeUpdateWithIndex(t *testing.T) {
 	}
 }
 
-// TestSendBroadcastMessage verifies that SendBroadcastMessage sends raw data.
+// TestSendBroadcastMessage verifies that SendBroadcastMessage sends raw data to one meet.
 func TestSendBroadcastMessage(t *testing.T) {
 	InitTest()
 	flushBroadcastChannel()
 
 	rawData := []byte(`{"action":"rawMessage"}`)
-	SendBroadcastMessage(rawData)
+	SendBroadcastMessage("APL Test Meet", rawData)
 
 	select {
 	case msg := <-mockBroadcast:
-		assert.Equal(t, rawData, msg)
+		assert.Equal(t, rawData, msg.Data)
+		assert.Equal(t, "APL Test Meet", msg.MeetName)
 	default:
 		t.Fatal("Expected raw message in broadcast channel, but got none")
 	}
@@ -0,0 +1,66 @@
+// Package websocket - websocket/cards.go
+// file: websocket/cards.go
+
+package websocket
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Decision values a referee may submit.
+const (
+	DecisionWhite = "white" // good lift
+	DecisionRed   = "red"   // no lift
+)
+
+// Reason cards that may accompany a red decision (IPF technical rules).
+const (
+	CardRed    = "red"    // depth (squat) or lockout failure
+	CardBlue   = "blue"   // downward movement or double bounce
+	CardYellow = "yellow" // foot movement or bar movement
+)
+
+// cardOrder is the canonical display order of the reason cards.
+var cardOrder = []string{CardRed, CardBlue, CardYellow}
+
+// validCards is the set of reason cards the server accepts.
+var validCards = map[string]bool{
+	CardRed:    true,
+	CardBlue:   true,
+	CardYellow: true,
+}
+
+// ErrReasonCardRequired is returned for a red decision without a reason card.
+var ErrReasonCardRequired = errors.New("a red decision needs at least one reason card")
+
+// validateCards checks the reason cards submitted alongside a decision.
+// A red decision needs at least one card; unknown cards are rejected, as are cards
+// attached to a white decision. The returned slice is de-duplicated and in canonical order.
+func validateCards(decision string, cards []string) ([]string, error) {
+	if len(cards) == 0 {
+		if decision == DecisionRed {
+			return nil, ErrReasonCardRequired
+		}
+		return []string{}, nil
+	}
+	if decision != DecisionRed {
+		return nil, fmt.Errorf("reason cards are only allowed with a red decision, got decision=%q", decision)
+	}
+
+	seen := make(map[string]bool, len(cards))
+	for _, card := range cards {
+		if !validCards[card] {
+			return nil, fmt.Errorf("unknown reason card %q", card)
+		}
+		seen[card] = true
+	}
+
+	normalised := make([]string, 0, len(seen))
+	for _, card := range cardOrder {
+		if seen[card] {
+			normalised = append(normalised, card)
+		}
+	}
+	return normalised, nil
+}
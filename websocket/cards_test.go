@@ -0,0 +1,73 @@
+// file: websocket/cards_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCards_NoCards(t *testing.T) {
+	cards, err := validateCards(DecisionWhite, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, cards)
+
+}
+
+func TestValidateCards_RedNeedsACard(t *testing.T) {
+	_, err := validateCards(DecisionRed, nil)
+	assert.ErrorIs(t, err, ErrReasonCardRequired)
+
+	_, err = validateCards(DecisionRed, []string{})
+	assert.ErrorIs(t, err, ErrReasonCardRequired)
+}
+
+func TestValidateCards_NormalisesOrderAndDuplicates(t *testing.T) {
+	cards, err := validateCards(DecisionRed, []string{CardYellow, CardRed, CardYellow})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{CardRed, CardYellow}, cards)
+}
+
+func TestValidateCards_RejectsUnknownCard(t *testing.T) {
+	_, err := validateCards(DecisionRed, []string{CardRed, "green"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "green")
+}
+
+func TestValidateCards_RejectsCardsOnWhite(t *testing.T) {
+	_, err := validateCards(DecisionWhite, []string{CardBlue})
+	assert.Error(t, err)
+}
+
+func TestProcessDecision_RejectsUnknownCard(t *testing.T) {
+	InitTest()
+	ClearMeetState("CardsMeet")
+	meetState := GetMeetState("CardsMeet")
+
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	conn := &Connection{conn: &fakeConn{}, meetName: "CardsMeet"}
+	processDecision(conn, DecisionMessage{
+		Action:   "submitDecision",
+		MeetName: "CardsMeet",
+		JudgeID:  "left",
+		Decision: DecisionRed,
+		Cards:    []string{"purple"},
+	})
+	assert.Empty(t, meetState.JudgeDecisions, "Decision with an unknown card should be rejected")
+
+	processDecision(conn, DecisionMessage{
+		Action:   "submitDecision",
+		MeetName: "CardsMeet",
+		JudgeID:  "left",
+		Decision: DecisionRed,
+		Cards:    []string{CardBlue},
+	})
+	assert.Equal(t, DecisionRed, meetState.JudgeDecisions["left"])
+	assert.Equal(t, []string{CardBlue}, meetState.JudgeCards["left"])
+}
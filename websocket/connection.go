@@ -5,13 +5,17 @@ package websocket
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 )
 
 // ------------------------- websocket connection interface ------------------
@@ -32,49 +36,66 @@ type WSConn interface {
 
 // Connection represents an individual WebSocket connection.
 type Connection struct {
-	conn     WSConn      // The actual WebSocket connection interface
-	send     chan []byte // Outbound messages get queued here
-	meetName string      // The meet to which this connection belongs
-	judgeID  string      // Identifies which judge (e.g., "left", "center", etc.) is using it
+	conn     WSConn           // The actual WebSocket connection interface
+	send     chan []byte      // Outbound messages get queued here
+	meetName string           // Platform state key (see PlatformKey) of the platform this connection follows
+	judgeID  string           // Seat bound at upgrade time (a position on the meet's panel), empty for displays
+	user     string           // Session user who opened the connection
+	isAdmin  bool             // Session role may control the clock (control_timer)
+	isJury   bool             // Session role may amend decisions (amend_decision)
+	version  int              // Protocol version negotiated at upgrade time; zero means protocol.Version
+	settings config.WebSocket // Write and pong deadlines, ping period and inbound size limit
 }
 
 // Global map to store active WebSocket connections.
 var connections = make(map[*Connection]bool)
 var connectionsMu sync.RWMutex
 
-// ------------------------- Tunable package-level variables ------------------
-//
-// Changing these from `const` to `var` allows us to override them in tests.
-// By default, they have the same long durations as before.
-
-var (
-	writeWait      = 4 * time.Hour       // Max time to complete a write
-	pongWait       = 4 * time.Hour       // Max time between pongs from the client
-	pingPeriod     = (pongWait * 9) / 10 // When to send ping (90% of pongWait)
-	maxMessageSize = 2048                // Maximum inbound message size in bytes
-)
-
-// Upgrader config: allow any origin for now
+// Upgrader config: origins are checked against the configured OriginPolicy
 var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		// For local dev or a relaxed policy, we can allow all origins.
-		return true
-	},
+	CheckOrigin: checkOrigin,
 }
 
 // ------------------------- HTTP -> WebSocket upgrade ------------------
 
-// ServeWs upgrades an HTTP request to a WebSocket connection and starts pumps.
-func ServeWs(w http.ResponseWriter, r *http.Request) {
-	meetName := r.URL.Query().Get("meetName")
-	if meetName == "" {
+// ServeWs upgrades an HTTP request to a WebSocket connection bound to an already
+// authenticated identity and starts pumps that use the given connection settings.
+func ServeWs(w http.ResponseWriter, r *http.Request, id Identity, settings config.WebSocket) {
+	meetName := PlatformKey(id.MeetName, id.Platform)
+	if id.MeetName == "" {
 		logger.Error.Println("No meet selected; rejecting WebSocket connection")
 		http.Error(w, "No meet selected", http.StatusBadRequest)
 		return
 	}
 
-	logger.Info.Printf("[ServeWs] Upgrading to WS: remoteAddr=%v, meetName=%q", r.RemoteAddr, meetName)
-	wsConn, err := upgrader.Upgrade(w, r, nil)
+	// clients ask for a protocol version by offering it as a subprotocol
+	version, err := protocol.Negotiate(websocket.Subprotocols(r))
+	if err != nil {
+		logger.Warn.Printf("[ServeWs] Rejecting %v: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var responseHeader http.Header
+	if len(websocket.Subprotocols(r)) > 0 {
+		responseHeader = http.Header{"Sec-Websocket-Protocol": {protocol.Subprotocol(version)}}
+	}
+
+	// a platform is served by one instance at a time. Browsers cannot see why an
+	// upgrade failed, so a client that reached the wrong instance is upgraded and closed
+	// with CloseServedElsewhere, telling it to reconnect until it reaches the right one.
+	if err := claimPlatform(meetName); errors.Is(err, ErrPlatformElsewhere) {
+		logger.Warn.Printf("[ServeWs] Sending %v elsewhere: %v", r.RemoteAddr, err)
+		refuseServedElsewhere(w, r, responseHeader, settings.WriteWait)
+		return
+	} else if err != nil {
+		logger.Warn.Printf("[ServeWs] Rejecting %v: %v", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	logger.Info.Printf("[ServeWs] Upgrading to WS: remoteAddr=%v, meetName=%q, judgeId=%q, protocol=%d",
+		r.RemoteAddr, meetName, id.JudgeID, version)
+	wsConn, err := upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		logger.Error.Printf("[ServeWs] WebSocket upgrade error: %v", err)
 		http.Error(w, "Failed to upgrade WebSocket", http.StatusBadRequest)
@@ -86,16 +107,38 @@ func ServeWs(w http.ResponseWriter, r *http.Request) {
 		conn:     wsConn,
 		send:     make(chan []byte, 256), // buffered channel
 		meetName: meetName,
-		judgeID:  "", // set by "registerRef" message
+		judgeID:  id.JudgeID,
+		user:     id.User,
+		isAdmin:  id.Admin,
+		isJury:   id.Jury,
+		version:  version,
+		settings: settings,
 	}
 
 	registerConnection(conn)
 
+	// a client that connects mid-attempt draws the platform without waiting for the next event
+	sendStateSnapshot(conn)
+
 	// start pumps
 	go conn.readPump()
 	go conn.writePump()
 }
 
+// refuseServedElsewhere upgrades the request only to close it with CloseServedElsewhere.
+func refuseServedElsewhere(w http.ResponseWriter, r *http.Request, responseHeader http.Header, writeWait time.Duration) {
+	wsConn, err := upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		logger.Error.Printf("[refuseServedElsewhere] WebSocket upgrade error: %v", err)
+		return
+	}
+	defer wsConn.Close()
+	msg := websocket.FormatCloseMessage(protocol.CloseServedElsewhere, ErrPlatformElsewhere.Error())
+	if err := wsConn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait)); err != nil {
+		logger.Warn.Printf("[refuseServedElsewhere] Failed to close %v: %v", r.RemoteAddr, err)
+	}
+}
+
 // ------------------------ read/write pumps -----------------------
 
 // readPump listens for messages from the WebSocket client.
@@ -106,14 +149,14 @@ func (c *Connection) readPump() {
 	}()
 
 	// Limit message size
-	c.conn.SetReadLimit(int64(maxMessageSize))
+	c.conn.SetReadLimit(c.settings.MaxMessageSize)
 
 	// Initial read deadline
-	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.settings.PongWait))
 
 	// Whenever we get a Pong frame, reset the read deadline
 	c.conn.SetPongHandler(func(string) error {
-		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return c.conn.SetReadDeadline(time.Now().Add(c.settings.PongWait))
 	})
 
 	for {
@@ -128,9 +171,9 @@ func (c *Connection) readPump() {
 			continue
 		}
 
-		var dm DecisionMessage
-		if err := json.Unmarshal(message, &dm); err != nil {
-			logger.Warn.Printf("[readPump] Invalid JSON from %v: %v", c.conn.RemoteAddr(), err)
+		dm, err := protocol.Decode(c.protocolVersion(), message)
+		if err != nil {
+			rejectMessage(c, err)
 			continue
 		}
 		handleIncoming(c, dm)
@@ -139,7 +182,7 @@ func (c *Connection) readPump() {
 
 // writePump handles outgoing messages to the WebSocket client.
 func (c *Connection) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.settings.PingPeriod)
 	defer func() {
 		ticker.Stop()
 		_ = c.conn.Close()
@@ -149,7 +192,7 @@ func (c *Connection) writePump() {
 		select {
 		case message, ok := <-c.send:
 			// For each write, update write deadline
-			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+			if err := c.conn.SetWriteDeadline(time.Now().Add(c.settings.WriteWait)); err != nil {
 				return
 			}
 			if !ok {
@@ -165,7 +208,7 @@ func (c *Connection) writePump() {
 
 		case <-ticker.C:
 			// Time to send a Ping
-			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+			if err := c.conn.SetWriteDeadline(time.Now().Add(c.settings.WriteWait)); err != nil {
 				return
 			}
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
@@ -176,6 +219,36 @@ func (c *Connection) writePump() {
 	}
 }
 
+// protocolVersion returns the protocol version the connection speaks.
+func (c *Connection) protocolVersion() int {
+	if c.version == 0 {
+		return protocol.Version
+	}
+	return c.version
+}
+
+// rejectMessage tells the sender why its message could not be decoded or applied.
+func rejectMessage(c *Connection, err error) {
+	logger.Warn.Printf("Rejected message from %v (meet=%s): %v", c.conn.RemoteAddr(), c.meetName, err)
+	var e *protocol.Error
+	if !errors.As(err, &e) {
+		e = &protocol.Error{Header: protocol.Header{Action: protocol.ActionError}, Code: protocol.CodeInvalidMessage, Message: err.Error()}
+	}
+	e.MeetName = c.meetName
+	sendToConnection(c, e)
+}
+
+// acknowledge tells the sender its message was applied. Messages without a requestId
+// are not acknowledged.
+func acknowledge(c *Connection, dm DecisionMessage) {
+	if dm.RequestID == "" {
+		return
+	}
+	ack := protocol.Ack{Header: protocol.NewHeader(protocol.ActionAck, c.meetName), Requested: dm.Action}
+	ack.RequestID = dm.RequestID
+	sendToConnection(c, ack)
+}
+
 // ------------------------ connection management -----------------------
 
 // registerConnection adds a new WebSocket connection to the global map.
@@ -194,39 +267,62 @@ func unregisterConnection(c *Connection) {
 
 // ------------------------ message handling -----------------------
 
-// DecisionMessage is the JSON structure from clients.
-type DecisionMessage struct {
-	Action         string `json:"action"`
-	MeetName       string `json:"meetName"`
-	JudgeID        string `json:"judgeId"`
-	Decision       string `json:"decision"`
-	LeftDecision   string `json:"leftDecision"`
-	CenterDecision string `json:"centerDecision"`
-	RightDecision  string `json:"rightDecision"`
-}
+// DecisionMessage is the JSON structure from clients, every action flattened into one
+// struct (see the protocol package for each action's own fields).
+type DecisionMessage = protocol.ClientMessage
 
-// handleIncoming processes inbound JSON messages.
+// handleIncoming processes inbound JSON messages. Every message is answered to its
+// sender: refused ones with the reason, applied ones with an ack when they carry a
+// requestId.
 func handleIncoming(c *Connection, dm DecisionMessage) {
 	logger.Debug.Printf("[handleIncoming] Action=%s, JudgeID=%s, Meet=%s",
 		dm.Action, dm.JudgeID, dm.MeetName)
 
+	// a connection may only act on the platform it was authenticated for; clients
+	// name the meet by ID and may add the platform
+	meetID, platformID := SplitPlatformKey(c.meetName)
+	if (dm.MeetName != "" && dm.MeetName != meetID && dm.MeetName != c.meetName) ||
+		(dm.Platform != "" && dm.Platform != platformID) {
+		rejectMessage(c, protocol.Rejection(dm, fmt.Errorf("connection is bound to meet %q", c.meetName)))
+		return
+	}
+	dm.MeetName = c.meetName
+
 	switch dm.Action {
 	case "registerRef":
-		c.judgeID = dm.JudgeID
-		logger.Info.Printf("Referee %s registered on meet %s (conn=%v)",
-			dm.JudgeID, dm.MeetName, c.conn.RemoteAddr())
+		// the seat comes from the session; the client's judgeId is only checked against it
+		if panelFor(c.meetName).IsSeat(dm.JudgeID) && dm.JudgeID != c.judgeID {
+			logger.Warn.Printf("registerRef for %q from %v does not match bound seat %q",
+				dm.JudgeID, c.conn.RemoteAddr(), c.judgeID)
+		}
+		logger.Info.Printf("Client %s registered on meet %s as judgeId=%q (conn=%v)",
+			dm.JudgeID, dm.MeetName, c.judgeID, c.conn.RemoteAddr())
 		broadcastRefereeHealth(dm.MeetName)
+		sendStateSnapshot(c)
 
 	case "startTimer":
 		logger.Info.Printf("Received startTimer from %v", c.conn.RemoteAddr())
+		if err := authorizeTimerControl(c); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+		if err := checkAttempt(dm.MeetName, dm.AttemptID); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
 		defaultTimerManager.HandleTimerAction("startTimer", dm.MeetName)
 
 	case "resetLights":
 		logger.Info.Printf("Received resetLights from %v", c.conn.RemoteAddr())
-		msg := map[string]string{
-			"action":   "resetLights",
-			"meetName": dm.MeetName,
+		if err := authorizeTimerControl(c); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+		if err := checkAttempt(dm.MeetName, dm.AttemptID); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
 		}
+		msg := protocol.ResetLights{Header: protocol.NewHeader(protocol.ActionResetLights, dm.MeetName)}
 		out, err := json.Marshal(msg)
 		if err != nil {
 			logger.Error.Printf("Error marshaling resetLights: %v", err)
@@ -236,10 +332,15 @@ func handleIncoming(c *Connection, dm DecisionMessage) {
 
 	case "resetTimer":
 		logger.Info.Printf("Received resetTimer from %v", c.conn.RemoteAddr())
-		msg := map[string]string{
-			"action":   "resetTimer",
-			"meetName": dm.MeetName,
+		if err := authorizeTimerControl(c); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
 		}
+		if err := checkAttempt(dm.MeetName, dm.AttemptID); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+		msg := protocol.ResetTimer{Header: protocol.NewHeader(protocol.ActionResetTimer, dm.MeetName)}
 		out, err := json.Marshal(msg)
 		if err != nil {
 			logger.Error.Printf("Error marshaling resetTimer: %v", err)
@@ -247,76 +348,145 @@ func handleIncoming(c *Connection, dm DecisionMessage) {
 			broadcastToMeet(dm.MeetName, out)
 		}
 
+	case "pauseTimer", "resumeTimer", "adjustTimer":
+		if err := authorizeTimerControl(c); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+		if err := checkAttempt(dm.MeetName, dm.AttemptID); err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+		var err error
+		switch dm.Action {
+		case "pauseTimer":
+			err = defaultTimerManager.PauseTimer(dm.MeetName)
+		case "resumeTimer":
+			err = defaultTimerManager.ResumeTimer(dm.MeetName)
+		default:
+			err = defaultTimerManager.AdjustTimer(dm.MeetName, dm.Seconds)
+		}
+		if err != nil {
+			rejectTimerAction(c, dm, err)
+			return
+		}
+
 	case "submitDecision":
-		processDecision(c, dm)
+		if err := authorizeDecision(c, dm); err != nil {
+			rejectDecision(c, dm, err)
+			return
+		}
+		if err := processDecision(c, dm); err != nil {
+			rejectDecision(c, dm, err)
+			return
+		}
+
+	case "amendDecision":
+		if err := authorizeAmendment(c); err != nil {
+			rejectAmendment(c, dm, err)
+			return
+		}
+		if _, err := AmendLastDecision(c.meetName, dm.Verdict, c.user, dm.Reason); err != nil {
+			rejectAmendment(c, dm, err)
+			return
+		}
+
+	case "setCurrentAttempt":
+		if err := authorizeCurrentAttempt(c); err != nil {
+			rejectMessage(c, protocol.Rejection(dm, err))
+			return
+		}
+		if dm.CurrentAttempt == nil {
+			rejectMessage(c, protocol.Rejection(dm, errors.New("currentAttempt is required")))
+			return
+		}
+		if err := SetCurrentAttempt(dm.MeetName, *dm.CurrentAttempt); err != nil {
+			rejectMessage(c, protocol.Rejection(dm, err))
+			return
+		}
 
 	default:
-		logger.Debug.Printf("Unhandled action: %s", dm.Action)
+		rejectMessage(c, protocol.Rejection(dm, fmt.Errorf("unhandled action %q", dm.Action)))
+		return
 	}
+	acknowledge(c, dm)
 }
 
-// processDecision checks if all judge decisions have arrived, then broadcasts final results if so.
-func processDecision(c *Connection, dm DecisionMessage) {
+// processDecision records a referee's decision under the decision lifecycle and
+// reveals the result once every referee has decided and the change windows have closed.
+// Decisions the lifecycle refuses are returned as errors for the sender.
+func processDecision(c *Connection, dm DecisionMessage) error {
 	if dm.JudgeID == "" || dm.Decision == "" {
 		logger.Warn.Printf("Incomplete decision from %v; ignoring", c.conn.RemoteAddr())
-		return
+		return ErrIncompleteDecision
 	}
-	logger.Info.Printf("Processing decision from %s: %s (meet: %s)",
-		dm.JudgeID, dm.Decision, dm.MeetName)
+	cards, err := validateCards(dm.Decision, dm.Cards)
+	if err != nil {
+		logger.Warn.Printf("Rejected decision from %s (meet: %s): %v", dm.JudgeID, dm.MeetName, err)
+		return err
+	}
+	logger.Info.Printf("Processing decision from %s: %s cards=%v (meet: %s)",
+		dm.JudgeID, dm.Decision, cards, dm.MeetName)
 
 	meetState := DefaultStateProvider.GetMeetState(dm.MeetName)
-	meetState.JudgeDecisions[dm.JudgeID] = dm.Decision
-
-	// If all three decisions are in, broadcast final results.
-	if len(meetState.JudgeDecisions) >= 3 {
-		broadcastFinalResults(dm.MeetName)
+	meetState.decisionMu.Lock()
+	now := time.Now()
+	changed, err := acceptDecision(meetState, dm, cards, now)
+	if err != nil || !changed {
+		meetState.decisionMu.Unlock()
+		return err
 	}
 
+	// Once every referee on the panel has decided and can no longer change their
+	// mind, broadcast final results.
+	res := scheduleReveal(meetState, now)
+	meetState.decisionMu.Unlock()
+
 	// Also broadcast that this judge submitted a decision.
-	submission := map[string]string{
-		"action":  "judgeSubmitted",
-		"judgeId": dm.JudgeID,
+	submission := protocol.JudgeSubmitted{
+		Header:  protocol.Header{Action: protocol.ActionJudgeSubmitted},
+		JudgeID: dm.JudgeID,
 	}
 	out, err := json.Marshal(submission)
 	if err != nil {
 		logger.Error.Printf("Error marshaling judgeSubmitted: %v", err)
-		return
+		return nil
 	}
 	broadcastToMeet(dm.MeetName, out)
+	if res != nil {
+		showResult(meetState, res)
+	}
+	return nil
 }
 
-// broadcastToMeet sends a message to all connections in the given meet.
+// broadcastToMeet sends a message to all connections in the given meet, on every instance.
 var broadcastToMeet = func(meetName string, message []byte) {
-	connectionsMu.RLock()
-	defer connectionsMu.RUnlock()
-
-	for c := range connections {
-		if c.meetName == meetName {
-			select {
-			case c.send <- message:
-			default:
-				logger.Warn.Printf("Dropping message for %v", c.conn.RemoteAddr())
-			}
-		}
-	}
+	publish(meetName, message)
 }
 
+// broadcastRefereeHealth tells a platform which seats are connected. Every seated
+// connection is listed; only the voting seats count towards the referees required.
 var broadcastRefereeHealth = func(meetName string) {
+	panel := panelFor(meetName)
 	var connectedIDs []string
+	connectedReferees := 0
 
 	connectionsMu.RLock()
 	for c := range connections {
 		if c.meetName == meetName && c.judgeID != "" {
 			connectedIDs = append(connectedIDs, c.judgeID)
+			if panel.IsReferee(c.judgeID) {
+				connectedReferees++
+			}
 		}
 	}
 	connectionsMu.RUnlock()
 
-	msg := map[string]interface{}{
-		"action":            "refereeHealth",
-		"connectedRefIDs":   connectedIDs,
-		"connectedReferees": len(connectedIDs),
-		"requiredReferees":  3,
+	msg := protocol.RefereeHealth{
+		Header:            protocol.Header{Action: protocol.ActionRefereeHealth},
+		ConnectedRefIDs:   connectedIDs,
+		ConnectedReferees: connectedReferees,
+		RequiredReferees:  len(panel.Referees),
 	}
 	out, _ := json.Marshal(msg)
 	broadcastToMeet(meetName, out)
@@ -14,21 +14,38 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"go-ref-lights/config"
+	"go-ref-lights/protocol"
+	"go-ref-lights/storage"
 )
 
 // Helper function to start a test WebSocket server
 func startTestServer(t *testing.T) (*httptest.Server, *websocket.Conn) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ServeWs(w, r)
+		ServeWs(w, r, Identity{MeetName: r.URL.Query().Get("meetName")}, config.Default().WebSocket)
 	}))
 
 	wsURL := "ws" + server.URL[4:] + "?meetName=TestMeet"
 	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
 	assert.NoError(t, err, "WebSocket connection should succeed")
+	readSnapshot(t, conn)
 
 	return server, conn
 }
 
+// readSnapshot reads the state snapshot every connection is sent first.
+func readSnapshot(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	_, msg, err := conn.ReadMessage()
+	assert.NoError(t, err, "Should receive a state snapshot on connecting")
+	var snapshot map[string]interface{}
+	_ = json.Unmarshal(msg, &snapshot)
+	assert.Equal(t, "stateSnapshot", snapshot["action"])
+	return snapshot
+}
+
 // `TestWritePump` should match expected response
 func TestWritePump(t *testing.T) {
 	server, conn := startTestServer(t)
@@ -40,8 +57,9 @@ func TestWritePump(t *testing.T) {
 	}()
 
 	testConn := &Connection{
-		conn: conn,
-		send: make(chan []byte, 100), // Increase buffer size
+		conn:     conn,
+		send:     make(chan []byte, 100), // Increase buffer size
+		settings: config.Default().WebSocket,
 	}
 
 	registerConnection(testConn)
@@ -174,7 +192,86 @@ func TestBroadcastToMeet(t *testing.T) {
 
 	_, msg, err := conn.ReadMessage()
 	assert.NoError(t, err, "Should receive broadcast message")
-	assert.JSONEq(t, string(testMessage), string(msg), "Broadcasted message should be received correctly")
+	var received map[string]interface{}
+	assert.NoError(t, json.Unmarshal(msg, &received))
+	assert.Equal(t, "judgeSubmitted", received["action"], "Broadcasted message should be received correctly")
+	assert.Equal(t, "left", received["judgeId"])
+	assert.EqualValues(t, GetMeetState("TestMeet").attempt().ID, received["attemptId"], "broadcasts are stamped with the attempt")
 
 	unregisterConnection(mockConn)
 }
+
+// The protocol version is negotiated from the subprotocols the client offers.
+func TestServeWs_NegotiatesProtocolVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(w, r, Identity{MeetName: "TestMeet"}, config.Default().WebSocket)
+	}))
+	defer server.Close()
+	wsURL := "ws" + server.URL[4:]
+
+	dialer := websocket.Dialer{Subprotocols: []string{"reflights.v99", "reflights.v1"}}
+	conn, _, err := dialer.Dial(wsURL, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "reflights.v1", conn.Subprotocol())
+		_ = conn.Close()
+	}
+
+	// clients that offer nothing get the current version without a subprotocol
+	conn, _, err = websocket.DefaultDialer.Dial(wsURL, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", conn.Subprotocol())
+		_ = conn.Close()
+	}
+
+	dialer = websocket.Dialer{Subprotocols: []string{"reflights.v99"}}
+	_, resp, err := dialer.Dial(wsURL, nil)
+	assert.Error(t, err, "a client offering only unknown versions is refused")
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+// A client connecting mid-attempt is sent the platform as it stands.
+func TestServeWs_SendsStateSnapshot(t *testing.T) {
+	ClearMeetState("SnapshotMeet")
+	defer ClearMeetState("SnapshotMeet")
+	state := GetMeetState("SnapshotMeet")
+	state.JudgeDecisions = map[string]string{"left": "white"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(w, r, Identity{MeetName: "SnapshotMeet"}, config.Default().WebSocket)
+	}))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:], nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	snapshot := readSnapshot(t, conn)
+	assert.Equal(t, "SnapshotMeet", snapshot["meetName"])
+	assert.Equal(t, []interface{}{"left"}, snapshot["submitted"])
+	assert.NotZero(t, snapshot["attemptId"])
+}
+
+// A client that reaches an instance not serving its platform is told to reconnect.
+func TestServeWs_ClosesPlatformServedElsewhere(t *testing.T) {
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+	ok, err := store.Lease(platformLeaseKey("ElsewhereMeet"), "other-instance", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(w, r, Identity{MeetName: "ElsewhereMeet"}, config.Default().WebSocket)
+	}))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+server.URL[4:], nil)
+	if !assert.NoError(t, err, "the upgrade succeeds so browsers can see the close code") {
+		return
+	}
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, protocol.CloseServedElsewhere), "got %v", err)
+}
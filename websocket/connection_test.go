@@ -5,12 +5,18 @@
 package websocket
 
 import (
+	"encoding/json"
+	"io"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-ref-lights/config"
+	"go-ref-lights/protocol"
 )
 
 // ----------------- FAKE WSConn IMPLEMENTATION -----------------
@@ -44,25 +50,17 @@ func (fc *fakeConn) SetPongHandler(h func(string) error) {}
 // ----------------- TESTS -----------------
 
 func TestWritePump_Ping(t *testing.T) {
-	// Save original pingPeriod/pongWait
-	origPing := pingPeriod
-	origPong := pongWait
-
-	// Shorten them for test
-	pongWait = 50 * time.Millisecond
-	pingPeriod = (pongWait * 9) / 10 // e.g. 45ms
-
-	// Restore after test
-	defer func() {
-		pingPeriod = origPing
-		pongWait = origPong
-	}()
+	// Shorten the ping period for the test
+	settings := config.Default().WebSocket
+	settings.PongWait = 50 * time.Millisecond
+	settings.PingPeriod = (settings.PongWait * 9) / 10 // e.g. 45ms
 
 	fc := &fakeConn{}
 	conn := &Connection{
 		conn:     fc,
 		send:     make(chan []byte, 10),
 		meetName: "UnitTestMeet",
+		settings: settings,
 	}
 
 	done := make(chan struct{})
@@ -72,7 +70,7 @@ func TestWritePump_Ping(t *testing.T) {
 	}()
 
 	// Wait enough time to see a ping
-	time.Sleep(2 * pingPeriod)
+	time.Sleep(2 * settings.PingPeriod)
 
 	// Close the send channel to stop the writePump
 	close(conn.send)
@@ -100,6 +98,52 @@ func TestRegisterUnregisterConnection(t *testing.T) {
 	assert.Equal(t, 0, len(connections), "Should have 0 after unregistering")
 }
 
+// scriptedConn is a fakeConn that reads the given messages, then fails as if closed.
+type scriptedConn struct {
+	fakeConn
+	messages []string
+}
+
+func (sc *scriptedConn) ReadMessage() (int, []byte, error) {
+	if len(sc.messages) == 0 {
+		return 0, nil, io.EOF
+	}
+	msg := sc.messages[0]
+	sc.messages = sc.messages[1:]
+	return websocket.TextMessage, []byte(msg), nil
+}
+
+// Test: messages the protocol refuses are answered with an error frame, not acted on.
+func TestReadPump_RejectsInvalidMessages(t *testing.T) {
+	InitTest()
+	sc := &scriptedConn{messages: []string{
+		`not json`,
+		`{"action":"fly"}`,
+		`{"action":"submitDecision","judgeId":"left","decision":"green"}`,
+		`{"action":"adjustTimer","seconds":"ten"}`,
+	}}
+	conn := &Connection{conn: sc, send: make(chan []byte, 4), meetName: "ProtocolMeet"}
+	conn.readPump()
+
+	want := []struct{ code, field, requested string }{
+		{protocol.CodeInvalidJSON, "", ""},
+		{protocol.CodeUnknownAction, "action", "fly"},
+		{protocol.CodeInvalidMessage, "decision", "submitDecision"},
+		{protocol.CodeInvalidMessage, "seconds", "adjustTimer"},
+	}
+	require.Len(t, conn.send, len(want))
+	for _, w := range want {
+		var frame protocol.Error
+		require.NoError(t, json.Unmarshal(<-conn.send, &frame))
+		assert.Equal(t, protocol.ActionError, frame.Action)
+		assert.Equal(t, "ProtocolMeet", frame.MeetName)
+		assert.Equal(t, w.code, frame.Code)
+		assert.Equal(t, w.field, frame.Field)
+		assert.Equal(t, w.requested, frame.Requested)
+		assert.NotEmpty(t, frame.Message)
+	}
+}
+
 // ----------------- ADDITIONAL tests -----------------
 
 //func TestRegisterAndUnregisterConnection(t *testing.T) {
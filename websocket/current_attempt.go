@@ -0,0 +1,99 @@
+// Package websocket - websocket/current_attempt.go
+// file: websocket/current_attempt.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/openlifter"
+	"go-ref-lights/protocol"
+)
+
+// AttemptContext describes the lifter currently on the platform, as supplied by OpenLifter.
+type AttemptContext = protocol.AttemptContext
+
+// ResultPublisher forwards completed attempts to an external system such as OpenLifter.
+type ResultPublisher interface {
+	Publish(res openlifter.Result)
+}
+
+// resultPublisher receives every completed attempt that has a lifter context; nil disables it.
+var resultPublisher ResultPublisher
+
+// SetResultPublisher wires the outbound decision push (e.g. an openlifter.Notifier).
+func SetResultPublisher(p ResultPublisher) {
+	resultPublisher = p
+}
+
+// SetCurrentAttempt records the lifter on a platform, named by its state key (see
+// PlatformKey), and tells its clients. It fails with ErrPlatformElsewhere on an
+// instance that does not serve the platform.
+func SetCurrentAttempt(meetName string, attempt AttemptContext) error {
+	if meetName == "" {
+		return fmt.Errorf("meetName is required")
+	}
+	if err := attempt.Validate(); err != nil {
+		return err
+	}
+	if err := claimPlatform(meetName); err != nil {
+		return err
+	}
+
+	meetState := DefaultStateProvider.GetMeetState(meetName)
+	// the feed names the next lifter, opening their attempt, unless the referees are
+	// already deciding one (a correction to the lifter on the platform)
+	meetState.decisionMu.Lock()
+	deciding := meetState.attempt().Phase == PhaseSubmitted || meetState.attempt().Phase == PhaseLocked
+	meetState.decisionMu.Unlock()
+	if !deciding {
+		startAttempt(meetState, AttemptFromFeed)
+	}
+	meetState.decisionMu.Lock()
+	meetState.CurrentAttempt = &attempt
+	meetState.decisionMu.Unlock()
+	saveMeetState(meetState)
+	logger.Info.Printf("[SetCurrentAttempt] meet=%s lifter=%s %s #%d @ %.1fkg",
+		meetName, attempt.LifterID, attempt.Lift, attempt.AttemptNumber, attempt.WeightKg)
+
+	out, err := json.Marshal(protocol.CurrentAttempt{
+		Header:         protocol.NewHeader(protocol.ActionCurrentAttempt, meetName),
+		CurrentAttempt: attempt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal currentAttempt: %w", err)
+	}
+	broadcastToMeet(meetName, out)
+	return nil
+}
+
+// publishResult sends a revealed result to the result publisher, if one is configured.
+func publishResult(meetState *MeetState, res *revealedResult) {
+	if resultPublisher == nil {
+		return
+	}
+	attempt := res.display.CurrentAttempt
+	if attempt == nil {
+		logger.Info.Printf("[publishResult] meet=%s has no current lifter; skipping result push", meetState.MeetName)
+		return
+	}
+
+	resultPublisher.Publish(openlifter.Result{
+		MeetName:      meetState.MeetID,
+		Platform:      meetState.PlatformID,
+		LifterID:      attempt.LifterID,
+		LifterName:    attempt.LifterName,
+		Lift:          attempt.Lift,
+		AttemptNumber: attempt.AttemptNumber,
+		WeightKg:      attempt.WeightKg,
+		Verdict:       res.display.Verdict,
+		WhiteCount:    res.display.WhiteCount,
+		RedCount:      res.display.RedCount,
+		Decisions:     res.display.Decisions,
+		Cards:         res.display.Cards,
+		DecidedAt:     time.Now(),
+	})
+}
@@ -0,0 +1,131 @@
+// file: websocket/current_attempt_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/openlifter"
+)
+
+// capturePublisher records published results instead of POSTing them.
+type capturePublisher struct {
+	results []openlifter.Result
+}
+
+func (p *capturePublisher) Publish(res openlifter.Result) {
+	p.results = append(p.results, res)
+}
+
+func validAttempt() AttemptContext {
+	return AttemptContext{LifterID: "uuid-1", LifterName: "Jane Lifter", Lift: "bench", AttemptNumber: 3, WeightKg: 102.5}
+}
+
+func TestAttemptContext_Validate(t *testing.T) {
+	assert.NoError(t, validAttempt().Validate())
+
+	bad := validAttempt()
+	bad.Lift = "clean"
+	assert.Error(t, bad.Validate())
+
+	bad = validAttempt()
+	bad.AttemptNumber = 0
+	assert.Error(t, bad.Validate())
+
+	bad = validAttempt()
+	bad.LifterID = ""
+	assert.Error(t, bad.Validate())
+
+	bad = validAttempt()
+	bad.WeightKg = 0
+	assert.Error(t, bad.Validate())
+}
+
+func TestSetCurrentAttempt_StoresAndBroadcasts(t *testing.T) {
+	InitTest()
+	ClearMeetState("OpenLifter Meet")
+
+	var captured []byte
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) { captured = message }
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	require.NoError(t, SetCurrentAttempt("OpenLifter Meet", validAttempt()))
+	assert.Equal(t, "uuid-1", GetMeetState("OpenLifter Meet").CurrentAttempt.LifterID)
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(captured, &msg))
+	assert.Equal(t, "currentAttempt", msg["action"])
+	assert.Equal(t, "OpenLifter Meet", msg["meetName"])
+
+	assert.Error(t, SetCurrentAttempt("OpenLifter Meet", AttemptContext{}))
+}
+
+func TestBroadcastFinalResults_IncludesAttemptAndPublishes(t *testing.T) {
+	InitTest()
+	flushBroadcastChannel()
+
+	publisher := &capturePublisher{}
+	SetResultPublisher(publisher)
+	defer SetResultPublisher(nil)
+
+	ClearMeetState("OpenLifter Meet")
+	meetState := GetMeetState("OpenLifter Meet")
+	attempt := validAttempt()
+	meetState.CurrentAttempt = &attempt
+	meetState.JudgeDecisions = map[string]string{"left": "white", "center": "white", "right": "red"}
+	meetState.JudgeCards = map[string][]string{"right": {CardBlue}}
+
+	broadcastFinalResults("OpenLifter Meet")
+
+	msg := <-mockBroadcast
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(msg.Data, &decoded))
+	assert.Equal(t, "displayResults", decoded["action"])
+	ctx, ok := decoded["currentAttempt"].(map[string]interface{})
+	require.True(t, ok, "displayResults should carry the current attempt")
+	assert.Equal(t, "uuid-1", ctx["lifterId"])
+	flushBroadcastChannel()
+
+	require.Len(t, publisher.results, 1)
+	res := publisher.results[0]
+	assert.Equal(t, VerdictGoodLift, res.Verdict)
+	assert.Equal(t, 2, res.WhiteCount)
+	assert.Equal(t, 1, res.RedCount)
+	assert.Equal(t, 3, res.AttemptNumber)
+	assert.Equal(t, []string{CardBlue}, res.Cards["right"])
+	assert.Nil(t, meetState.CurrentAttempt, "Lifter context should be consumed by the decision")
+}
+
+func TestHandleIncoming_SetCurrentAttemptNeedsAdmin(t *testing.T) {
+	InitTest()
+	ClearMeetState("OpenLifter Meet")
+	defer ClearMeetState("OpenLifter Meet")
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	meetState := GetMeetState("OpenLifter Meet")
+	meetState.JudgeDecisions["left"] = DecisionWhite
+	attempt := validAttempt()
+
+	viewer := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "OpenLifter Meet"}
+	handleIncoming(viewer, DecisionMessage{Action: "setCurrentAttempt", CurrentAttempt: &attempt})
+	assert.Nil(t, meetState.CurrentAttempt, "a viewer cannot name the lifter")
+	assert.Equal(t, DecisionWhite, meetState.JudgeDecisions["left"], "a viewer cannot clear the decisions in progress")
+	require.Len(t, viewer.send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-viewer.send, &frame))
+	assert.Equal(t, "error", frame["action"])
+	assert.Equal(t, "rejected", frame["code"])
+
+	admin := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "OpenLifter Meet", isAdmin: true}
+	handleIncoming(admin, DecisionMessage{Action: "setCurrentAttempt", CurrentAttempt: &attempt})
+	require.NotNil(t, meetState.CurrentAttempt)
+	assert.Equal(t, "uuid-1", meetState.CurrentAttempt.LifterID)
+}
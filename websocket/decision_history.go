@@ -0,0 +1,54 @@
+// Package websocket - websocket/decision_history.go
+// file: websocket/decision_history.go
+
+package websocket
+
+import (
+	"time"
+
+	"go-ref-lights/history"
+	"go-ref-lights/logger"
+)
+
+// decisionStore receives every completed attempt; nil disables the decision log.
+var decisionStore history.Store
+
+// SetDecisionHistory wires the decision log store. Occupants are resolved with the seat lookup.
+func SetDecisionHistory(store history.Store) {
+	decisionStore = store
+}
+
+// recordDecision appends an attempt's revealed result to the decision log.
+func recordDecision(meetState *MeetState, res *revealedResult) {
+	if decisionStore == nil {
+		return
+	}
+
+	rec := &history.DecisionRecord{
+		MeetName:    meetState.MeetID,
+		Platform:    meetState.PlatformID,
+		AttemptID:   res.attempt.ID,
+		Verdict:     res.display.Verdict,
+		WhiteCount:  res.display.WhiteCount,
+		RedCount:    res.display.RedCount,
+		CompletedAt: time.Now(),
+	}
+	for _, pos := range res.display.Referees {
+		vote := history.JudgeVote{
+			Position:    pos,
+			Decision:    res.display.Decisions[pos],
+			Cards:       res.cards[pos],
+			SubmittedAt: res.submittedAt[pos],
+		}
+		if seatLookup != nil {
+			vote.Occupant = seatLookup(meetState.MeetName, pos)
+		}
+		rec.Judges = append(rec.Judges, vote)
+	}
+
+	if err := decisionStore.Append(rec); err != nil {
+		logger.Error.Printf("[recordDecision] Failed to record attempt for meet=%s: %v", meetState.MeetName, err)
+		return
+	}
+	logger.Info.Printf("[recordDecision] meet=%s attempt=%d recorded (%s)", rec.MeetName, rec.Attempt, rec.Verdict)
+}
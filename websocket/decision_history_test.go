@@ -0,0 +1,76 @@
+// file: websocket/decision_history_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/history"
+)
+
+func TestBroadcastFinalResults_RecordsDecision(t *testing.T) {
+	InitTest()
+	flushBroadcastChannel()
+
+	store, err := history.NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	SetDecisionHistory(store)
+	SetSeatLookup(func(meetName, position string) string {
+		return "occupant-" + position
+	})
+	defer SetDecisionHistory(nil)
+	defer SetSeatLookup(nil)
+
+	ClearMeetState("History Meet")
+	meetState := GetMeetState("History Meet")
+	submitted := time.Now()
+	meetState.JudgeDecisions = map[string]string{"left": "white", "center": "red", "right": "red"}
+	meetState.JudgeCards = map[string][]string{"center": {CardRed}, "right": {CardRed, CardYellow}}
+	meetState.JudgeSubmittedAt = map[string]time.Time{"left": submitted, "center": submitted, "right": submitted}
+
+	broadcastFinalResults("History Meet")
+	flushBroadcastChannel()
+
+	records, total, err := store.List("History Meet", 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	rec := records[0]
+	assert.Equal(t, 1, rec.Attempt)
+	assert.Equal(t, VerdictNoLift, rec.Verdict)
+	assert.Equal(t, 1, rec.WhiteCount)
+	assert.Equal(t, 2, rec.RedCount)
+	require.Len(t, rec.Judges, 3)
+	assert.Equal(t, "occupant-left", rec.Judges[0].Occupant)
+	assert.Equal(t, []string{CardRed, CardYellow}, rec.Judges[2].Cards)
+	assert.True(t, rec.Judges[1].SubmittedAt.Equal(submitted))
+	assert.Empty(t, meetState.JudgeSubmittedAt, "Timestamps should be reset for the next attempt")
+}
+
+func TestBroadcastFinalResults_RecordsPlatform(t *testing.T) {
+	InitTest()
+	flushBroadcastChannel()
+
+	store, err := history.NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	SetDecisionHistory(store)
+	defer SetDecisionHistory(nil)
+
+	key := PlatformKey("history-meet", "b")
+	ClearMeetState(key)
+	meetState := GetMeetState(key)
+	meetState.JudgeDecisions = map[string]string{"left": "white", "center": "white", "right": "white"}
+
+	broadcastFinalResults(key)
+	flushBroadcastChannel()
+
+	// every platform's attempts go in the meet's history, tagged with the platform
+	records, total, err := store.List("history-meet", 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "b", records[0].Platform)
+}
@@ -0,0 +1,196 @@
+// Package websocket - websocket/decision_lifecycle.go
+// file: websocket/decision_lifecycle.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
+)
+
+// DecisionPhase is where an attempt's decisions stand. Every attempt moves from open
+// through submitted and locked to revealed; the next attempt opens once the lights clear,
+// the clock is started or an attempt feed names the next lifter.
+type DecisionPhase string
+
+const (
+	PhaseOpen      DecisionPhase = "open"      // no referee has decided yet
+	PhaseSubmitted DecisionPhase = "submitted" // decisions are arriving; each can change until its window closes
+	PhaseLocked    DecisionPhase = "locked"    // every referee has decided and no decision can change
+	PhaseRevealed  DecisionPhase = "revealed"  // the lights show the result; late decisions are refused
+)
+
+// decisionChangeWindow is how long a referee may change a submitted decision, counted
+// from their first submission for the attempt. Zero locks decisions as they arrive.
+func decisionChangeWindow() time.Duration {
+	return defaultTimerManager.Timers.DecisionChange
+}
+
+// Reasons a decision is refused by the lifecycle.
+var (
+	ErrIncompleteDecision = errors.New("decision needs a judgeId and a decision")
+	ErrStaleAttempt       = errors.New("message is for an attempt that is no longer current")
+	ErrAttemptRevealed    = errors.New("decisions for this attempt have already been revealed")
+	ErrDecisionLocked     = errors.New("your decision is locked and can no longer be changed")
+)
+
+// acceptDecision applies the decision lifecycle to a referee's submission and records
+// it. A repeat of the decision already held is accepted without change, so retries are
+// harmless. It reports whether the decision changed the platform's state.
+func acceptDecision(meetState *MeetState, dm DecisionMessage, cards []string, now time.Time) (bool, error) {
+	attempt := meetState.attempt()
+	if dm.AttemptID != 0 && dm.AttemptID != attempt.ID {
+		return false, fmt.Errorf("%w (sent for attempt %d, the platform is on attempt %d)", ErrStaleAttempt, dm.AttemptID, attempt.ID)
+	}
+	if attempt.Phase == PhaseRevealed || attempt.Phase == PhaseLocked {
+		return false, ErrAttemptRevealed
+	}
+
+	previous, decided := meetState.JudgeDecisions[dm.JudgeID]
+	if decided && previous == dm.Decision && equalCards(meetState.JudgeCards[dm.JudgeID], cards) {
+		return false, nil
+	}
+	if decided && !now.Before(meetState.JudgeLockAt[dm.JudgeID]) {
+		return false, ErrDecisionLocked
+	}
+
+	if meetState.JudgeCards == nil {
+		meetState.JudgeCards = make(map[string][]string)
+	}
+	if meetState.JudgeSubmittedAt == nil {
+		meetState.JudgeSubmittedAt = make(map[string]time.Time)
+	}
+	if meetState.JudgeLockAt == nil {
+		meetState.JudgeLockAt = make(map[string]time.Time)
+	}
+	meetState.JudgeDecisions[dm.JudgeID] = dm.Decision
+	meetState.JudgeCards[dm.JudgeID] = cards
+	meetState.JudgeSubmittedAt[dm.JudgeID] = now
+	if !decided {
+		meetState.JudgeLockAt[dm.JudgeID] = now.Add(decisionChangeWindow())
+	}
+	attempt.Phase = PhaseSubmitted
+	return true, nil
+}
+
+// revealAt returns when every referee's decision is locked, or false while a referee
+// on the panel has yet to decide.
+func revealAt(meetState *MeetState) (time.Time, bool) {
+	var at time.Time
+	for _, pos := range panelFor(meetState.MeetName).Referees {
+		if _, ok := meetState.JudgeDecisions[pos]; !ok {
+			return time.Time{}, false
+		}
+		if lockAt := meetState.JudgeLockAt[pos]; lockAt.After(at) {
+			at = lockAt
+		}
+	}
+	return at, true
+}
+
+// scheduleReveal shows the result once every referee has decided and the last change
+// window has closed. A result due now is returned for the caller to show with
+// showResult once it has released decisionMu. Callers hold decisionMu.
+func scheduleReveal(meetState *MeetState, now time.Time) *revealedResult {
+	at, ready := revealAt(meetState)
+	if !ready {
+		return nil
+	}
+	if !at.After(now) {
+		return revealDecisions(meetState)
+	}
+	if meetState.revealPending {
+		return nil
+	}
+	meetState.revealPending = true
+	attempt := meetState.attempt()
+	meetState.after(at.Sub(now), func() {
+		meetState.decisionMu.Lock()
+		meetState.revealPending = false
+		// the clock may have been reset, or a new attempt opened, while this one waited
+		if meetState.Attempt != attempt || attempt.Phase != PhaseSubmitted {
+			meetState.decisionMu.Unlock()
+			return
+		}
+		res := revealDecisions(meetState)
+		meetState.decisionMu.Unlock()
+		showResult(meetState, res)
+	})
+	return nil
+}
+
+// revealDecisions locks the attempt and takes its result. Callers hold decisionMu.
+func revealDecisions(meetState *MeetState) *revealedResult {
+	logger.Info.Printf("[revealDecisions] meet=%s attempt=%d locked", meetState.MeetName, meetState.attempt().ID)
+	return takeResult(meetState)
+}
+
+// reopenDecisions opens the next attempt once the revealed result has been cleared,
+// unless the clock or an attempt feed has already opened it.
+func reopenDecisions(meetState *MeetState, attemptID int64) {
+	meetState.decisionMu.Lock()
+	over := meetState.attempt().ID == attemptID && meetState.attempt().Phase == PhaseRevealed
+	meetState.decisionMu.Unlock()
+	if over {
+		startAttempt(meetState, AttemptFromResults)
+	}
+}
+
+// clearLights forgets the result on the lights, so a pending clear for it does not
+// reopen decisions the clock has already moved past.
+func clearLights(meetState *MeetState) {
+	meetState.decisionMu.Lock()
+	meetState.resultsOnLights = 0
+	meetState.decisionMu.Unlock()
+}
+
+// clearAttempt throws away the decisions of the attempt under way so its referees
+// decide again. A revealed attempt is over, so the next one opens instead.
+func clearAttempt(meetState *MeetState) {
+	meetState.decisionMu.Lock()
+	attempt := meetState.attempt()
+	if attempt.Phase == PhaseRevealed {
+		meetState.decisionMu.Unlock()
+		startAttempt(meetState, AttemptFromResults)
+		return
+	}
+	meetState.resetDecisions()
+	attempt.Phase = PhaseOpen
+	meetState.decisionMu.Unlock()
+	announceAttempt(meetState, attempt)
+}
+
+// announceAttempt tells a platform's referees which attempt is open; they send its
+// ID back with their decisions.
+func announceAttempt(meetState *MeetState, attempt *Attempt) {
+	header := protocol.NewHeader(protocol.ActionDecisionsOpen, meetState.MeetName)
+	header.AttemptID = attempt.ID
+	out, err := json.Marshal(protocol.DecisionsOpen{
+		Header:   header,
+		Source:   string(attempt.Source),
+		ChangeMs: decisionChangeWindow().Milliseconds(),
+	})
+	if err != nil {
+		logger.Error.Printf("[announceAttempt] Error marshalling decisionsOpen: %v", err)
+		return
+	}
+	broadcastToMeet(meetState.MeetName, out)
+}
+
+// equalCards reports whether two canonical card lists match.
+func equalCards(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
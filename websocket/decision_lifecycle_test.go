@@ -0,0 +1,145 @@
+// file: websocket/decision_lifecycle_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleState returns a fresh platform state for the lifecycle tests.
+func lifecycleState(t *testing.T, meetName string) *MeetState {
+	InitTest()
+	flushBroadcastChannel()
+	ClearMeetState(meetName)
+	t.Cleanup(func() { ClearMeetState(meetName) })
+	return GetMeetState(meetName)
+}
+
+// queuedActions drains the broadcast channel and returns the actions queued on it.
+func queuedActions(t *testing.T) []string {
+	var actions []string
+	for len(mockBroadcast) > 0 {
+		msg := <-mockBroadcast
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(msg.Data, &decoded))
+		action, _ := decoded["action"].(string)
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+func TestAcceptDecision_ChangeableUntilLocked(t *testing.T) {
+	meetState := lifecycleState(t, "LifecycleMeet")
+	assert.Equal(t, PhaseOpen, meetState.Attempt.Phase)
+
+	start := time.Now()
+	white := DecisionMessage{JudgeID: "left", Decision: DecisionWhite, AttemptID: meetState.Attempt.ID}
+	changed, err := acceptDecision(meetState, white, []string{}, start)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, PhaseSubmitted, meetState.Attempt.Phase)
+
+	changed, err = acceptDecision(meetState, white, []string{}, start.Add(time.Hour))
+	assert.NoError(t, err, "a repeat of the same decision is accepted even once locked")
+	assert.False(t, changed)
+
+	red := DecisionMessage{JudgeID: "left", Decision: DecisionRed, Cards: []string{CardRed}}
+	changed, err = acceptDecision(meetState, red, []string{CardBlue}, start.Add(decisionChangeWindow()/2))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, DecisionRed, meetState.JudgeDecisions["left"])
+	assert.Equal(t, start.Add(decisionChangeWindow()), meetState.JudgeLockAt["left"], "changing does not extend the window")
+
+	_, err = acceptDecision(meetState, white, []string{}, start.Add(decisionChangeWindow()))
+	assert.ErrorIs(t, err, ErrDecisionLocked)
+	assert.Equal(t, DecisionRed, meetState.JudgeDecisions["left"])
+}
+
+func TestAcceptDecision_RefusesOtherAttempts(t *testing.T) {
+	meetState := lifecycleState(t, "LifecycleMeet")
+
+	_, err := acceptDecision(meetState, DecisionMessage{JudgeID: "left", Decision: DecisionWhite, AttemptID: meetState.Attempt.ID - 1}, []string{}, time.Now())
+	assert.ErrorIs(t, err, ErrStaleAttempt)
+
+	meetState.Attempt.Phase = PhaseRevealed
+	_, err = acceptDecision(meetState, DecisionMessage{JudgeID: "left", Decision: DecisionWhite}, []string{}, time.Now())
+	assert.ErrorIs(t, err, ErrAttemptRevealed)
+	assert.Empty(t, meetState.JudgeDecisions)
+}
+
+func TestProcessDecision_RevealsAfterChangeWindow(t *testing.T) {
+	meetState := lifecycleState(t, "LifecycleMeet")
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+	origWindow := defaultTimerManager.Timers.DecisionChange
+	defaultTimerManager.Timers.DecisionChange = 50 * time.Millisecond
+	defer func() { defaultTimerManager.Timers.DecisionChange = origWindow }()
+
+	conn := &Connection{conn: &fakeConn{}, meetName: "LifecycleMeet"}
+	for _, pos := range []string{"left", "center", "right"} {
+		require.NoError(t, processDecision(conn, DecisionMessage{MeetName: "LifecycleMeet", JudgeID: pos, Decision: DecisionWhite}))
+	}
+	assert.NotContains(t, queuedActions(t), "displayResults", "the lights wait for the change window")
+	require.NoError(t, processDecision(conn, DecisionMessage{MeetName: "LifecycleMeet", JudgeID: "right", Decision: DecisionRed, Cards: []string{CardRed}}))
+
+	assert.Eventually(t, func() bool {
+		meetState.decisionMu.Lock()
+		defer meetState.decisionMu.Unlock()
+		return meetState.Attempt.Phase == PhaseRevealed
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, queuedActions(t), "displayResults")
+}
+
+func TestHandleIncoming_LateDecisionIsReported(t *testing.T) {
+	meetState := lifecycleState(t, "LifecycleMeet")
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+	origWindow := defaultTimerManager.Timers.DecisionChange
+	defaultTimerManager.Timers.DecisionChange = 0
+	defer func() { defaultTimerManager.Timers.DecisionChange = origWindow }()
+
+	id := meetState.Attempt.ID
+	seats := map[string]*Connection{}
+	for _, pos := range []string{"left", "center", "right"} {
+		seats[pos] = &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "LifecycleMeet", judgeID: pos}
+		handleIncoming(seats[pos], DecisionMessage{Action: "submitDecision", JudgeID: pos, Decision: DecisionWhite, AttemptID: id})
+	}
+	assert.Contains(t, queuedActions(t), "displayResults")
+	assert.Equal(t, PhaseRevealed, meetState.Attempt.Phase)
+	assert.Equal(t, id, meetState.Attempt.ID, "the attempt stays current until its result clears")
+
+	// a repeat for the revealed attempt must not count towards the next one
+	handleIncoming(seats["left"], DecisionMessage{Action: "submitDecision", JudgeID: "left", Decision: DecisionRed, Cards: []string{CardRed}, AttemptID: id})
+	require.Len(t, seats["left"].send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-seats["left"].send, &frame))
+	assert.Equal(t, "decisionRejected", frame["action"])
+	assert.Equal(t, ErrAttemptRevealed.Error(), frame["message"])
+
+	reopenDecisions(meetState, id)
+	assert.Equal(t, id+1, meetState.Attempt.ID)
+	assert.Equal(t, AttemptFromResults, meetState.Attempt.Source)
+	assert.Equal(t, PhaseOpen, meetState.Attempt.Phase)
+
+	// a late duplicate still naming it is reported rather than counted
+	handleIncoming(seats["left"], DecisionMessage{Action: "submitDecision", JudgeID: "left", Decision: DecisionRed, Cards: []string{CardRed}, AttemptID: id})
+	require.Len(t, seats["left"].send, 1)
+	require.NoError(t, json.Unmarshal(<-seats["left"].send, &frame))
+	assert.Equal(t, "decisionRejected", frame["action"])
+	assert.Contains(t, frame["message"], ErrStaleAttempt.Error())
+	assert.EqualValues(t, id+1, frame["attemptId"], "the rejection names the current attempt")
+	assert.Empty(t, meetState.JudgeDecisions)
+
+	handleIncoming(seats["left"], DecisionMessage{Action: "submitDecision", JudgeID: "left", Decision: DecisionRed, Cards: []string{CardRed}, AttemptID: id + 1})
+	assert.Empty(t, seats["left"].send)
+	assert.Equal(t, DecisionRed, meetState.JudgeDecisions["left"])
+}
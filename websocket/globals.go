@@ -8,11 +8,14 @@ import (
 // clients track of all connected clients (for broadcast usage)
 //var clients = make(map[*websocket.Conn]bool)
 
-// broadcast is a channel for sending messages to all clients
-var broadcast = make(chan []byte)
+// outboundMessage is a frame addressed to every connection in one meet.
+type outboundMessage struct {
+	MeetName string // the only meet whose clients receive Data
+	Data     []byte // JSON frame sent as-is
+}
 
-// resultsDisplayDuration controls how long final decisions remain displayed
-var resultsDisplayDuration = 15
+// broadcast is a channel for sending messages to the clients of a meet
+var broadcast = make(chan outboundMessage)
 
 // track an incrementing ID so each new timer gets a unique ID
 var nextAttemptIDCounter int
@@ -0,0 +1,131 @@
+// Package websocket - websocket/jury.go
+// file: websocket/jury.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ref-lights/history"
+	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
+)
+
+var (
+	// ErrHistoryDisabled is returned when there is no decision log to amend.
+	ErrHistoryDisabled = errors.New("decision history is not enabled")
+	// ErrNoDecisionToAmend is returned when the platform has no completed attempt yet.
+	ErrNoDecisionToAmend = errors.New("no completed attempt to amend on this platform")
+	// ErrInvalidAmendment wraps rulings that cannot be applied as asked.
+	ErrInvalidAmendment = errors.New("invalid amendment")
+)
+
+// amendMu serialises amendments so two jury rulings cannot race on the same attempt.
+var amendMu sync.Mutex
+
+// amendSearchPage is how many records are read at a time looking for a platform's last attempt.
+const amendSearchPage = 50
+
+// AmendLastDecision lets the jury overturn the last completed attempt on a platform.
+// The referees' votes and the original verdict stay in the decision log alongside
+// the ruling, and every client on the platform is sent a "decisionAmended" message.
+func AmendLastDecision(meetName, verdict, juryMember, reason string) (*history.DecisionRecord, error) {
+	if verdict != VerdictGoodLift && verdict != VerdictNoLift {
+		return nil, fmt.Errorf("%w: verdict must be %q or %q", ErrInvalidAmendment, VerdictGoodLift, VerdictNoLift)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required", ErrInvalidAmendment)
+	}
+	if decisionStore == nil {
+		return nil, ErrHistoryDisabled
+	}
+
+	amendMu.Lock()
+	defer amendMu.Unlock()
+
+	meetID, platformID := SplitPlatformKey(meetName)
+	last, err := lastDecision(meetID, platformID)
+	if err != nil {
+		return nil, err
+	}
+	if last.Verdict == verdict {
+		return nil, fmt.Errorf("%w: attempt %d is already a %s", ErrInvalidAmendment, last.Attempt, verdict)
+	}
+
+	rec, err := decisionStore.Amend(meetID, last.Attempt, history.Amendment{
+		Verdict:    verdict,
+		JuryMember: juryMember,
+		Reason:     reason,
+		AmendedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	amendment := rec.Amendments[len(rec.Amendments)-1]
+	logger.Info.Printf("[AmendLastDecision] meet=%s attempt=%d amended %s -> %s by %s: %s",
+		meetName, rec.Attempt, amendment.OriginalVerdict, amendment.Verdict, juryMember, reason)
+
+	out, err := json.Marshal(protocol.DecisionAmended{
+		Header:          protocol.NewHeader(protocol.ActionDecisionAmended, meetName),
+		Attempt:         rec.Attempt,
+		OriginalVerdict: amendment.OriginalVerdict,
+		Verdict:         amendment.Verdict,
+		JuryMember:      amendment.JuryMember,
+		Reason:          amendment.Reason,
+	})
+	if err != nil {
+		logger.Error.Printf("[AmendLastDecision] Error marshalling decisionAmended: %v", err)
+		return rec, nil
+	}
+	broadcastToMeet(meetName, out)
+	return rec, nil
+}
+
+// lastDecision finds the most recent attempt recorded for a platform. Records written
+// before platforms existed belong to the main platform.
+func lastDecision(meetID, platformID string) (*history.DecisionRecord, error) {
+	for offset := 0; ; offset += amendSearchPage {
+		records, total, err := decisionStore.List(meetID, offset, amendSearchPage)
+		if err != nil {
+			return nil, err
+		}
+		for i := range records {
+			platform := records[i].Platform
+			if platform == "" {
+				platform = DefaultPlatformID
+			}
+			if platform == platformID {
+				return &records[i], nil
+			}
+		}
+		if offset+amendSearchPage >= total {
+			return nil, ErrNoDecisionToAmend
+		}
+	}
+}
+
+// authorizeAmendment allows only jury members to overturn a decision.
+func authorizeAmendment(c *Connection) error {
+	if !c.isJury {
+		return errors.New("only the jury can amend a decision")
+	}
+	return nil
+}
+
+// rejectAmendment tells the sender why its amendment was not applied.
+func rejectAmendment(c *Connection, dm DecisionMessage, err error) {
+	logger.Warn.Printf("Rejected amendDecision from %v (user=%s, meet=%s): %v",
+		c.conn.RemoteAddr(), c.user, c.meetName, err)
+	msg := protocol.AmendRejected{
+		Header:  protocol.NewHeader(protocol.ActionAmendRejected, c.meetName),
+		Message: err.Error(),
+	}
+	msg.RequestID = dm.RequestID
+	sendToConnection(c, msg)
+}
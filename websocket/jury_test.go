@@ -0,0 +1,100 @@
+// file: websocket/jury_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-ref-lights/history"
+)
+
+// juryStore returns a decision log holding a good lift on platform b followed by a
+// good lift on the main platform.
+func juryStore(t *testing.T) history.Store {
+	store, err := history.NewJSONLStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, store.Append(&history.DecisionRecord{MeetName: "JuryMeet", Platform: "b", Verdict: VerdictGoodLift}))
+	require.NoError(t, store.Append(&history.DecisionRecord{MeetName: "JuryMeet", Platform: DefaultPlatformID, Verdict: VerdictGoodLift}))
+	SetDecisionHistory(store)
+	t.Cleanup(func() { SetDecisionHistory(nil) })
+	return store
+}
+
+func TestAmendLastDecision_OverturnsPlatformAttempt(t *testing.T) {
+	store := juryStore(t)
+	var sent []map[string]interface{}
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(message, &msg))
+		assert.Equal(t, "JuryMeet/b", meetName)
+		sent = append(sent, msg)
+	}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	rec, err := AmendLastDecision(PlatformKey("JuryMeet", "b"), VerdictNoLift, "jury1", "  depth  ")
+	require.NoError(t, err)
+	assert.Equal(t, 1, rec.Attempt, "the last attempt on platform b, not the meet's last")
+	assert.Equal(t, VerdictNoLift, rec.Verdict)
+	assert.Equal(t, VerdictGoodLift, rec.OriginalVerdict())
+
+	require.Len(t, sent, 1)
+	assert.Equal(t, "decisionAmended", sent[0]["action"])
+	assert.Equal(t, VerdictGoodLift, sent[0]["originalVerdict"])
+	assert.Equal(t, VerdictNoLift, sent[0]["verdict"])
+	assert.Equal(t, "jury1", sent[0]["juryMember"])
+	assert.Equal(t, "depth", sent[0]["reason"])
+
+	records, _, err := store.List("JuryMeet", 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictGoodLift, records[0].Verdict, "the main platform's attempt is untouched")
+}
+
+func TestAmendLastDecision_RejectsBadRulings(t *testing.T) {
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	_, err := AmendLastDecision("JuryMeet", VerdictNoLift, "jury1", "depth")
+	assert.ErrorIs(t, err, ErrHistoryDisabled)
+
+	juryStore(t)
+	_, err = AmendLastDecision("JuryMeet", "maybe", "jury1", "depth")
+	assert.ErrorIs(t, err, ErrInvalidAmendment)
+	_, err = AmendLastDecision("JuryMeet", VerdictNoLift, "jury1", " ")
+	assert.ErrorIs(t, err, ErrInvalidAmendment, "a reason is required")
+	_, err = AmendLastDecision("JuryMeet", VerdictGoodLift, "jury1", "depth")
+	assert.ErrorIs(t, err, ErrInvalidAmendment, "the attempt is already a good lift")
+	_, err = AmendLastDecision(PlatformKey("JuryMeet", "c"), VerdictNoLift, "jury1", "depth")
+	assert.ErrorIs(t, err, ErrNoDecisionToAmend)
+}
+
+func TestHandleIncoming_AmendDecisionNeedsJury(t *testing.T) {
+	InitTest()
+	store := juryStore(t)
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	referee := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "JuryMeet", judgeID: "left", user: "ref1"}
+	handleIncoming(referee, DecisionMessage{Action: "amendDecision", Verdict: VerdictNoLift, Reason: "depth"})
+	require.Len(t, referee.send, 1)
+	var frame map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-referee.send, &frame))
+	assert.Equal(t, "amendRejected", frame["action"])
+
+	jury := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "JuryMeet", user: "jury1", isJury: true}
+	handleIncoming(jury, DecisionMessage{Action: "amendDecision", Verdict: VerdictNoLift, Reason: "depth"})
+	assert.Empty(t, jury.send)
+
+	records, _, err := store.List("JuryMeet", 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, VerdictNoLift, records[0].Verdict)
+	assert.Equal(t, "jury1", records[0].Amendments[0].JuryMember)
+}
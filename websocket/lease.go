@@ -0,0 +1,140 @@
+// Package websocket - websocket/lease.go
+// file: websocket/lease.go
+
+package websocket
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/storage"
+)
+
+// A platform's decisions, attempt and timers live in the memory of the instance serving
+// it. Instances sharing a state store each take a lease on the platforms they serve and
+// refuse the platforms another instance holds, so all of a platform's traffic stays on
+// one instance. The load balancer must route each meet to one instance; websocket
+// clients that reach another are closed with protocol.CloseServedElsewhere and retry.
+// The lease lapses platformLeaseTTL after its instance stops renewing it.
+
+// ErrPlatformElsewhere is returned for a platform whose lease another instance holds.
+var ErrPlatformElsewhere = errors.New("this platform is served by another instance")
+
+// platformLeaseTTL is how long a platform stays with an instance that stops renewing it.
+var platformLeaseTTL = 30 * time.Second
+
+// instanceID names this process in the platform leases it holds.
+var instanceID = newInstanceID()
+
+// leasedPlatforms maps the platforms this instance serves to the store holding each
+// lease, which is renewed in the same store it was taken from.
+var (
+	leasedPlatforms   = make(map[string]storage.Store)
+	leasedPlatformsMu sync.Mutex
+	renewLeasesOnce   sync.Once
+)
+
+// newInstanceID returns an ID unique to this process, even among containers that share
+// a host name.
+func newInstanceID() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%x", host, os.Getpid(), suffix)
+}
+
+// platformLeaseKey is the lease key for a platform state key (see PlatformKey).
+func platformLeaseKey(meetName string) string {
+	return "platform:" + meetName
+}
+
+// claimPlatform takes or renews this instance's lease on a platform. Without a state
+// store there is only one instance, so every platform is ours.
+func claimPlatform(meetName string) error {
+	store := stateStore
+	if store == nil {
+		return nil
+	}
+	ok, err := store.Lease(platformLeaseKey(meetName), instanceID, platformLeaseTTL)
+	if err != nil {
+		return fmt.Errorf("failed to claim platform %s: %w", meetName, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w (platform %s)", ErrPlatformElsewhere, meetName)
+	}
+
+	leasedPlatformsMu.Lock()
+	leasedPlatforms[meetName] = store
+	leasedPlatformsMu.Unlock()
+	renewLeasesOnce.Do(func() { go renewPlatformLeases() })
+	return nil
+}
+
+// releasePlatform gives up this instance's lease on a platform, if it holds one.
+func releasePlatform(meetName string) {
+	leasedPlatformsMu.Lock()
+	store, ok := leasedPlatforms[meetName]
+	delete(leasedPlatforms, meetName)
+	leasedPlatformsMu.Unlock()
+	if !ok {
+		return
+	}
+	if err := store.Release(platformLeaseKey(meetName), instanceID); err != nil {
+		logger.Warn.Printf("[releasePlatform] Failed to release platform=%s: %v", meetName, err)
+	}
+}
+
+// renewPlatformLeases renews this instance's leases three times per lease lifetime.
+func renewPlatformLeases() {
+	ticker := time.NewTicker(platformLeaseTTL / 3)
+	defer ticker.Stop()
+	for range ticker.C {
+		renewLeases()
+	}
+}
+
+// renewLeases renews every lease this instance holds. A lease another instance has
+// taken is gone for good, so the platform's connections are closed and its clients
+// reconnect to the instance now serving it. A store error is retried at the next
+// renewal, while the lease may still be live.
+func renewLeases() {
+	leasedPlatformsMu.Lock()
+	held := make(map[string]storage.Store, len(leasedPlatforms))
+	for meetName, store := range leasedPlatforms {
+		held[meetName] = store
+	}
+	leasedPlatformsMu.Unlock()
+
+	for meetName, store := range held {
+		ok, err := store.Lease(platformLeaseKey(meetName), instanceID, platformLeaseTTL)
+		if err != nil {
+			logger.Warn.Printf("[renewLeases] Failed to renew platform=%s: %v", meetName, err)
+			continue
+		}
+		if ok {
+			continue
+		}
+		logger.Error.Printf("[renewLeases] Lost platform=%s to another instance; closing its connections", meetName)
+		leasedPlatformsMu.Lock()
+		delete(leasedPlatforms, meetName)
+		leasedPlatformsMu.Unlock()
+		closePlatformConnections(meetName)
+	}
+}
+
+// closePlatformConnections closes this instance's connections to a platform. Each
+// connection's read pump then unregisters it.
+func closePlatformConnections(meetName string) {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+	for c := range connections {
+		if c.meetName == meetName {
+			_ = c.conn.Close()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// file: websocket/lease_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/storage"
+)
+
+// closeRecorder is a connection that remembers being closed.
+type closeRecorder struct {
+	fakeConn
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// leaseElsewhere gives a platform's lease to another instance.
+func leaseElsewhere(t *testing.T, store storage.Store, meetName string) {
+	t.Helper()
+	ok, err := store.Lease(platformLeaseKey(meetName), "other-instance", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestClaimPlatform_WithoutStore(t *testing.T) {
+	InitTest()
+	SetStateStore(nil)
+	assert.NoError(t, claimPlatform("LeaseMeet"), "a single instance serves every platform")
+}
+
+func TestClaimPlatform_RefusesPlatformServedElsewhere(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+	leaseElsewhere(t, store, "LeaseMeet")
+
+	assert.ErrorIs(t, claimPlatform("LeaseMeet"), ErrPlatformElsewhere)
+	assert.ErrorIs(t, SetCurrentAttempt("LeaseMeet", validAttempt()), ErrPlatformElsewhere)
+	assert.NoError(t, claimPlatform("OtherMeet"))
+}
+
+func TestClearMeetState_ReleasesPlatform(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+
+	require.NoError(t, claimPlatform("LeaseMeet"))
+	GetMeetState("LeaseMeet")
+	ClearMeetState("LeaseMeet")
+
+	ok, err := store.Lease(platformLeaseKey("LeaseMeet"), "other-instance", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "a cleared platform can be served by another instance")
+}
+
+func TestRenewLeases_ClosesConnectionsOfLostPlatform(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+
+	require.NoError(t, claimPlatform("LeaseMeet"))
+	require.NoError(t, claimPlatform("KeptMeet"))
+	lost := &closeRecorder{}
+	kept := &closeRecorder{}
+	lostConn := &Connection{conn: lost, meetName: "LeaseMeet"}
+	keptConn := &Connection{conn: kept, meetName: "KeptMeet"}
+	registerConnection(lostConn)
+	registerConnection(keptConn)
+	defer unregisterConnection(lostConn)
+	defer unregisterConnection(keptConn)
+
+	// the lease lapsed and another instance took the platform
+	require.NoError(t, store.Release(platformLeaseKey("LeaseMeet"), instanceID))
+	leaseElsewhere(t, store, "LeaseMeet")
+	renewLeases()
+
+	assert.True(t, lost.closed, "clients of a lost platform should reconnect elsewhere")
+	assert.False(t, kept.closed)
+	leasedPlatformsMu.Lock()
+	assert.NotContains(t, leasedPlatforms, "LeaseMeet")
+	assert.Contains(t, leasedPlatforms, "KeptMeet")
+	leasedPlatformsMu.Unlock()
+}
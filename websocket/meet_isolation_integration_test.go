@@ -0,0 +1,104 @@
+//go:build integration
+// +build integration
+
+// file: websocket/meet_isolation_integration_test.go
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	gws "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/config"
+)
+
+var startMessageLoopOnce sync.Once
+
+// startMessageLoop runs HandleMessages once for the whole test binary so broadcasts are delivered.
+func startMessageLoop() {
+	startMessageLoopOnce.Do(func() { go HandleMessages() })
+}
+
+// dialMeet opens a client connection for the given meet and waits until the server has registered it.
+// The connection is closed, and its server side unregistered, when the test ends.
+func dialMeet(t *testing.T, serverURL, meetName string) *gws.Conn {
+	t.Helper()
+	before := connectionCount(meetName)
+	conn, _, err := gws.DefaultDialer.Dial("ws"+serverURL[4:]+"?meetName="+url.QueryEscape(meetName), nil)
+	require.NoError(t, err)
+	readSnapshot(t, conn)
+	require.Eventually(t, func() bool { return connectionCount(meetName) > before }, 2*time.Second, 10*time.Millisecond)
+	t.Cleanup(func() {
+		_ = conn.Close()
+		require.Eventually(t, func() bool { return connectionCount(meetName) == before }, 2*time.Second, 10*time.Millisecond)
+	})
+	return conn
+}
+
+func connectionCount(meetName string) int {
+	connectionsMu.RLock()
+	defer connectionsMu.RUnlock()
+	n := 0
+	for c := range connections {
+		if c.meetName == meetName {
+			n++
+		}
+	}
+	return n
+}
+
+// readActions collects the actions a client receives until nothing arrives for the given quiet period.
+func readActions(t *testing.T, conn *gws.Conn, quiet time.Duration) []string {
+	t.Helper()
+	var actions []string
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(quiet))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return actions
+		}
+		var msg map[string]interface{}
+		if json.Unmarshal(data, &msg) == nil {
+			if action, ok := msg["action"].(string); ok {
+				actions = append(actions, action)
+			}
+		}
+	}
+}
+
+func TestMultiMeetIsolation(t *testing.T) {
+	startMessageLoop()
+	origDisplay := defaultTimerManager.Timers.ResultsDisplay
+	defaultTimerManager.Timers.ResultsDisplay = 0
+	defer func() { defaultTimerManager.Timers.ResultsDisplay = origDisplay }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWs(w, r, Identity{MeetName: r.URL.Query().Get("meetName")}, config.Default().WebSocket)
+	}))
+	t.Cleanup(server.Close)
+
+	meetA := dialMeet(t, server.URL, "Isolation Meet A")
+	meetB := dialMeet(t, server.URL, "Isolation Meet B")
+
+	// Meet A completes an attempt, then its timer is started and reset.
+	stateA := GetMeetState("Isolation Meet A")
+	stateA.JudgeDecisions = map[string]string{"left": "white", "center": "white", "right": "red"}
+	broadcastFinalResults("Isolation Meet A")
+	defaultTimerManager.HandleTimerAction("startTimer", "Isolation Meet A")
+	defaultTimerManager.HandleTimerAction("resetTimer", "Isolation Meet A")
+	defer CancelPlatformReadyTimer("Isolation Meet A")
+
+	gotA := readActions(t, meetA, 500*time.Millisecond)
+	require.Contains(t, gotA, "displayResults")
+	require.Contains(t, gotA, "clearResults")
+	require.Contains(t, gotA, "startTimer")
+
+	// Meet B must not have seen any of it.
+	require.Empty(t, readActions(t, meetB, 500*time.Millisecond), "meet B received meet A's broadcasts")
+}
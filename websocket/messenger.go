@@ -7,50 +7,49 @@ package websocket
 import (
 	"encoding/json"
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 )
 
 var defaultMessenger Messenger = &realMessenger{}
 
-// Messenger is an interface for broadcasting messages.
+// Messenger is an interface for broadcasting messages. Every message is addressed to a single meet.
 type Messenger interface {
-	BroadcastMessage(meetName string, msg map[string]interface{})
+	BroadcastMessage(meetName string, msg interface{}) // msg is usually one of the protocol messages
 	BroadcastTimeUpdate(action string, timeLeft int, index int, meetName string)
-	BroadcastRaw(msg []byte)
+	BroadcastToMeet(meetName string, msg []byte)
 }
 
 // realMessenger is a concrete Messenger that writes messages to the global 'broadcast' channel.
 type realMessenger struct{}
 
 // BroadcastMessage marshals the message and sends it to all connections in the given meet.
-func (r *realMessenger) BroadcastMessage(meetName string, msg map[string]interface{}) {
+func (r *realMessenger) BroadcastMessage(meetName string, msg interface{}) {
 	m, err := json.Marshal(msg)
 	if err != nil {
 		logger.Error.Printf("[realMessenger.BroadcastMessage] Error marshalling message: %v", err)
 		return
 	}
-	broadcast <- m
+	broadcast <- outboundMessage{MeetName: meetName, Data: m}
 	logger.Info.Printf("[realMessenger.BroadcastMessage] Sent to meet=%s", meetName)
 }
 
 // BroadcastTimeUpdate sends a time update message (with index) to all connections.
 func (r *realMessenger) BroadcastTimeUpdate(action string, timeLeft int, index int, meetName string) {
-	msg := map[string]interface{}{
-		"action":   action,
-		"index":    index,
-		"timeLeft": timeLeft,
-		"meetName": meetName,
-	}
-	m, err := json.Marshal(msg)
+	m, err := json.Marshal(protocol.UpdatePlatformReadyTime{
+		Header:   protocol.NewHeader(action, meetName),
+		TimeLeft: timeLeft,
+		Index:    index,
+	})
 	if err != nil {
 		logger.Error.Printf("[realMessenger.BroadcastTimeUpdate] Error marshalling time update: %v", err)
 		return
 	}
-	broadcast <- m
+	broadcast <- outboundMessage{MeetName: meetName, Data: m}
 	logger.Info.Printf("[realMessenger.BroadcastTimeUpdate] meet=%s action=%s timeLeft=%d", meetName, action, timeLeft)
 }
 
-// BroadcastRaw sends a raw JSON message.
-func (r *realMessenger) BroadcastRaw(msg []byte) {
-	broadcast <- msg
-	logger.Info.Printf("[realMessenger.BroadcastRaw] Sent: %s", string(msg))
+// BroadcastToMeet sends an already-marshalled JSON message to all connections in the given meet.
+func (r *realMessenger) BroadcastToMeet(meetName string, msg []byte) {
+	broadcast <- outboundMessage{MeetName: meetName, Data: msg}
+	logger.Info.Printf("[realMessenger.BroadcastToMeet] meet=%s sent: %s", meetName, string(msg))
 }
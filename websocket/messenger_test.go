@@ -12,12 +12,12 @@ import (
 
 func TestRealMessenger_BroadcastMessage(t *testing.T) {
 	// Set up a dummy broadcast collector.
-	var captured []byte
+	var captured outboundMessage
 	originalBroadcast := broadcast
 	defer func() { broadcast = originalBroadcast }()
 
 	// Override broadcast with a buffered channel.
-	broadcast = make(chan []byte, 1)
+	broadcast = make(chan outboundMessage, 1)
 
 	rm := &realMessenger{}
 	testMsg := map[string]interface{}{"action": "testAction"}
@@ -26,18 +26,19 @@ func TestRealMessenger_BroadcastMessage(t *testing.T) {
 	// Read from the channel.
 	captured = <-broadcast
 	var result map[string]interface{}
-	err := json.Unmarshal(captured, &result)
+	err := json.Unmarshal(captured.Data, &result)
 	assert.NoError(t, err)
 	assert.Equal(t, "testAction", result["action"])
+	assert.Equal(t, "TestMeet", captured.MeetName)
 }
 
 func TestRealMessenger_BroadcastTimeUpdate(t *testing.T) {
 	// Set up a dummy broadcast collector.
-	var captured []byte
+	var captured outboundMessage
 	originalBroadcast := broadcast
 	defer func() { broadcast = originalBroadcast }()
 
-	broadcast = make(chan []byte, 1)
+	broadcast = make(chan outboundMessage, 1)
 
 	rm := &realMessenger{}
 	action := "updateTime"
@@ -50,7 +51,7 @@ func TestRealMessenger_BroadcastTimeUpdate(t *testing.T) {
 	// Read from the channel.
 	captured = <-broadcast
 	var result map[string]interface{}
-	err := json.Unmarshal(captured, &result)
+	err := json.Unmarshal(captured.Data, &result)
 	assert.NoError(t, err)
 	// JSON numbers become float64 by default.
 	assert.Equal(t, action, result["action"])
@@ -59,19 +60,20 @@ func TestRealMessenger_BroadcastTimeUpdate(t *testing.T) {
 	assert.Equal(t, meetName, result["meetName"])
 }
 
-func TestRealMessenger_BroadcastRaw(t *testing.T) {
+func TestRealMessenger_BroadcastToMeet(t *testing.T) {
 	// Set up a dummy broadcast collector.
-	var captured []byte
+	var captured outboundMessage
 	originalBroadcast := broadcast
 	defer func() { broadcast = originalBroadcast }()
 
-	broadcast = make(chan []byte, 1)
+	broadcast = make(chan outboundMessage, 1)
 
 	rm := &realMessenger{}
 	rawMsg := []byte(`{"action":"rawTest"}`)
-	rm.BroadcastRaw(rawMsg)
+	rm.BroadcastToMeet("TestMeet", rawMsg)
 
 	// Read from the channel.
 	captured = <-broadcast
-	assert.Equal(t, rawMsg, captured)
+	assert.Equal(t, rawMsg, captured.Data)
+	assert.Equal(t, "TestMeet", captured.MeetName)
 }
@@ -33,6 +33,14 @@ func PublishBroadcastBacklog(depth int, meetName string) {
 	putMetric("BroadcastQueueDepth", float64(depth), "Count", meetName)
 }
 
+// PublishRejectedOrigins pushes how many WebSocket upgrades were refused because of their Origin header
+func PublishRejectedOrigins(count int, meetName string) {
+	publishMetric("RejectedOrigins", float64(count), "Count", meetName)
+}
+
+// publishMetric is overridable so tests don't call CloudWatch
+var publishMetric = putMetric
+
 // -----------------------------------------------------------
 // internal helper function to package up CloudWatch calls
 // -----------------------------------------------------------
@@ -0,0 +1,197 @@
+// Package websocket - websocket/origin.go
+// file: websocket/origin.go
+
+package websocket
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// OriginPolicy decides which browser origins may open a WebSocket.
+//
+// Entries are host names, optionally with a port ("lights.example.com",
+// "localhost:8080"), and optionally with a scheme ("https://lights.example.com"),
+// in which case the origin's scheme must match too. A leading "*." matches any
+// subdomain but not the bare domain ("*.example.com" allows "a.example.com",
+// not "example.com"). The application URL always carries its scheme.
+// A permissive policy allows every origin; it exists for local development
+// and must not be used in production.
+type OriginPolicy struct {
+	permissive bool
+	hosts      map[string]bool // exact host or host:port entries, "scheme://" prefixed when scoped
+	suffixes   []originSuffix  // ".example.com" for "*.example.com"
+}
+
+// originSuffix is a "*." entry; an empty scheme matches any.
+type originSuffix struct {
+	scheme string
+	suffix string
+}
+
+// NewOriginPolicy builds an allowlist from the application URL plus any extra hosts.
+func NewOriginPolicy(applicationURL string, extraHosts []string, permissive bool) (*OriginPolicy, error) {
+	p := &OriginPolicy{permissive: permissive, hosts: make(map[string]bool)}
+
+	if applicationURL != "" {
+		u, err := url.Parse(applicationURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid application URL %q", applicationURL)
+		}
+		p.hosts[strings.ToLower(u.Scheme)+"://"+strings.ToLower(u.Host)] = true
+	}
+
+	for _, entry := range extraHosts {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		scheme, h, scoped := strings.Cut(entry, "://")
+		if !scoped {
+			scheme, h = "", entry
+		}
+		h = strings.TrimSuffix(h, "/")
+		switch {
+		case h == "" && !scoped:
+			continue
+		case h == "" || (scoped && scheme == ""):
+			return nil, fmt.Errorf("invalid allowed origin %q", entry)
+		case strings.HasPrefix(h, "*."):
+			p.suffixes = append(p.suffixes, originSuffix{scheme: scheme, suffix: h[1:]})
+		case strings.Contains(h, "*"):
+			return nil, fmt.Errorf("invalid allowed origin %q: only a leading \"*.\" wildcard is supported", entry)
+		case scoped:
+			p.hosts[scheme+"://"+h] = true
+		default:
+			p.hosts[h] = true
+		}
+	}
+	return p, nil
+}
+
+// Allowed reports whether a browser Origin header value passes the policy.
+func (p *OriginPolicy) Allowed(origin string) bool {
+	if p.permissive {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme) + "://"
+	host := strings.ToLower(u.Host)
+	name := strings.ToLower(u.Hostname())
+	for _, h := range []string{host, name, scheme + host, scheme + name} {
+		if p.hosts[h] {
+			return true
+		}
+	}
+	for _, s := range p.suffixes {
+		if s.scheme != "" && s.scheme+"://" != scheme {
+			continue
+		}
+		if strings.HasSuffix(name, s.suffix) && len(name) > len(s.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// originPolicy is the policy used by the upgrader; nil means same-origin only.
+// Same-origin requests are always allowed.
+var originPolicy *OriginPolicy
+
+// SetOriginPolicy configures which origins may connect.
+func SetOriginPolicy(p *OriginPolicy) {
+	originPolicy = p
+}
+
+// rejectedOrigins counts upgrades refused because of their Origin header.
+var rejectedOrigins atomic.Int64
+
+// RejectedOriginCount returns how many upgrades have been refused for their origin.
+func RejectedOriginCount() int64 {
+	return rejectedOrigins.Load()
+}
+
+// rejectedOriginFlushInterval is how often refused origins are sent to CloudWatch, so
+// a flood of refused handshakes costs one metric call per meet and interval.
+const rejectedOriginFlushInterval = time.Minute
+
+// maxRejectedOriginMeets bounds how many meets refused origins are tallied for between
+// flushes. The meet comes from the query string, so a client can make up any number;
+// the rest are counted under unknownOriginMeet.
+const maxRejectedOriginMeets = 64
+
+// unknownOriginMeet is the meet refusals are reported under when the request named no
+// meet or the bound was reached. CloudWatch rejects an empty dimension value.
+const unknownOriginMeet = "unknown"
+
+var (
+	rejectedOriginsMu      sync.Mutex
+	rejectedOriginsPending = map[string]int{} // meet ID -> refusals since the last flush
+	rejectedOriginsFlusher sync.Once
+)
+
+// countRejectedOrigin tallies a refused upgrade for the next flush.
+func countRejectedOrigin(meetID string) {
+	rejectedOriginsFlusher.Do(func() { go flushRejectedOriginsEvery(rejectedOriginFlushInterval) })
+
+	rejectedOriginsMu.Lock()
+	defer rejectedOriginsMu.Unlock()
+	if meetID == "" {
+		meetID = unknownOriginMeet
+	}
+	if _, ok := rejectedOriginsPending[meetID]; !ok && len(rejectedOriginsPending) >= maxRejectedOriginMeets {
+		meetID = unknownOriginMeet
+	}
+	rejectedOriginsPending[meetID]++
+}
+
+// flushRejectedOriginsEvery publishes the tallied refusals on every tick.
+func flushRejectedOriginsEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		flushRejectedOrigins()
+	}
+}
+
+// flushRejectedOrigins publishes and resets the refusals tallied since the last flush.
+func flushRejectedOrigins() {
+	rejectedOriginsMu.Lock()
+	pending := rejectedOriginsPending
+	rejectedOriginsPending = map[string]int{}
+	rejectedOriginsMu.Unlock()
+
+	for meetID, count := range pending {
+		PublishRejectedOrigins(count, meetID)
+	}
+}
+
+// checkOrigin is the upgrader's CheckOrigin. Requests without an Origin header
+// (non-browser clients) and same-origin requests are allowed, as the gorilla
+// default does; anything else must pass the configured policy.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if originPolicy != nil && originPolicy.Allowed(origin) {
+		return true
+	}
+
+	rejectedOrigins.Add(1)
+	meetID := r.URL.Query().Get("meet")
+	logger.Warn.Printf("[checkOrigin] Rejected WebSocket origin=%q remoteAddr=%v meet=%q",
+		origin, r.RemoteAddr, meetID)
+	countRejectedOrigin(meetID)
+	return false
+}
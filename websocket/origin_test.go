@@ -0,0 +1,168 @@
+// file: websocket/origin_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginPolicy_Allowed(t *testing.T) {
+	p, err := NewOriginPolicy("https://referee-lights.example.com.au",
+		[]string{"localhost:3000", "*.openlifter.com", " Display.Example.org "}, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://referee-lights.example.com.au", true},
+		{"http://localhost:3000", true},
+		{"http://localhost:4000", false},
+		{"https://meet.openlifter.com", true},
+		{"https://a.b.openlifter.com", true},
+		{"https://openlifter.com", false},
+		{"https://evilopenlifter.com", false},
+		{"https://display.example.org", true},
+		{"https://display.example.org:8443", true},
+		{"https://attacker.example", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Allowed(tt.origin), tt.origin)
+	}
+}
+
+func TestOriginPolicy_Scheme(t *testing.T) {
+	p, err := NewOriginPolicy("https://referee-lights.example.com.au",
+		[]string{"https://display.example.org", "https://*.openlifter.com", "localhost:3000"}, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://referee-lights.example.com.au", true},
+		{"http://referee-lights.example.com.au", false},
+		{"https://display.example.org", true},
+		{"http://display.example.org", false},
+		{"https://meet.openlifter.com", true},
+		{"http://meet.openlifter.com", false},
+		{"http://localhost:3000", true},
+		{"https://localhost:3000", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Allowed(tt.origin), tt.origin)
+	}
+
+	_, err = NewOriginPolicy("", []string{"https://"}, false)
+	assert.Error(t, err)
+}
+
+func TestOriginPolicy_Permissive(t *testing.T) {
+	p, err := NewOriginPolicy("https://referee-lights.example.com.au", nil, true)
+	require.NoError(t, err)
+	assert.True(t, p.Allowed("https://anything.example"))
+}
+
+func TestNewOriginPolicy_InvalidEntries(t *testing.T) {
+	_, err := NewOriginPolicy("not a url", nil, false)
+	assert.Error(t, err)
+
+	_, err = NewOriginPolicy("", []string{"meet.*.example.com"}, false)
+	assert.Error(t, err)
+}
+
+func TestCheckOrigin(t *testing.T) {
+	// keep rejected-origin metrics away from CloudWatch
+	origPublish := publishMetric
+	publishMetric = func(name string, value float64, unit string, meetName string) {}
+	defer func() { publishMetric = origPublish }()
+
+	p, err := NewOriginPolicy("https://referee-lights.example.com.au", nil, false)
+	require.NoError(t, err)
+	SetOriginPolicy(p)
+	defer SetOriginPolicy(nil)
+
+	req := httptest.NewRequest("GET", "http://localhost:8080/referee-updates?meet=cairns-cup", nil)
+	assert.True(t, checkOrigin(req), "requests without an Origin header are allowed")
+
+	req.Header.Set("Origin", "http://localhost:8080")
+	assert.True(t, checkOrigin(req), "same-origin requests are allowed")
+
+	req.Header.Set("Origin", "https://referee-lights.example.com.au")
+	assert.True(t, checkOrigin(req))
+
+	before := RejectedOriginCount()
+	req.Header.Set("Origin", "https://attacker.example")
+	assert.False(t, checkOrigin(req))
+	assert.Equal(t, before+1, RejectedOriginCount())
+}
+
+func TestCheckOrigin_PublishesRejectionsInBatches(t *testing.T) {
+	published := map[string]float64{}
+	origPublish := publishMetric
+	publishMetric = func(name string, value float64, unit string, meetName string) {
+		assert.Equal(t, "RejectedOrigins", name)
+		published[meetName] += value
+	}
+	defer func() { publishMetric = origPublish }()
+	SetOriginPolicy(nil)
+	flushRejectedOrigins()
+	for k := range published {
+		delete(published, k)
+	}
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "http://localhost:8080/referee-updates?meet=cairns-cup", nil)
+		req.Header.Set("Origin", "https://attacker.example")
+		assert.False(t, checkOrigin(req))
+	}
+	assert.Empty(t, published, "refusals wait for the next flush")
+
+	flushRejectedOrigins()
+	assert.Equal(t, map[string]float64{"cairns-cup": 3}, published)
+}
+
+func TestCountRejectedOrigin_BoundsMeets(t *testing.T) {
+	published := map[string]float64{}
+	origPublish := publishMetric
+	publishMetric = func(name string, value float64, unit string, meetName string) { published[meetName] += value }
+	defer func() { publishMetric = origPublish }()
+	flushRejectedOrigins()
+	for k := range published {
+		delete(published, k)
+	}
+
+	for i := 0; i < maxRejectedOriginMeets+10; i++ {
+		countRejectedOrigin(fmt.Sprintf("made-up-%d", i))
+	}
+	flushRejectedOrigins()
+	assert.Len(t, published, maxRejectedOriginMeets+1, "meets past the bound are counted under unknown")
+	assert.Equal(t, float64(10), published[unknownOriginMeet])
+	assert.NotContains(t, published, "")
+}
+
+func TestCountRejectedOrigin_NoMeetIsUnknown(t *testing.T) {
+	published := map[string]float64{}
+	origPublish := publishMetric
+	publishMetric = func(name string, value float64, unit string, meetName string) { published[meetName] += value }
+	defer func() { publishMetric = origPublish }()
+	SetOriginPolicy(nil)
+	flushRejectedOrigins()
+	for k := range published {
+		delete(published, k)
+	}
+
+	req := httptest.NewRequest("GET", "http://localhost:8080/referee-updates", nil)
+	req.Header.Set("Origin", "https://attacker.example")
+	assert.False(t, checkOrigin(req))
+	flushRejectedOrigins()
+	assert.Equal(t, map[string]float64{"unknown": 1}, published, "CloudWatch rejects an empty meet dimension")
+}
@@ -0,0 +1,35 @@
+// Package websocket - websocket/panel.go
+// file: websocket/panel.go
+
+package websocket
+
+import "go-ref-lights/platform"
+
+// Panel is the set of seats on a meet's referee panel (see platform.Panel).
+type Panel = platform.Panel
+
+// DefaultPanel is the three-referee panel, with the center referee running the clock.
+var DefaultPanel = platform.DefaultPanel
+
+// panelLookup returns the panel of a meet. It is nil until SetPanelLookup is called,
+// in which case every meet uses DefaultPanel.
+var panelLookup func(meetID string) (Panel, bool)
+
+// SetPanelLookup wires where each meet's panel comes from (the meet configuration).
+// It is consulted on every decision, so edits apply to the next attempt.
+func SetPanelLookup(lookup func(meetID string) (Panel, bool)) {
+	panelLookup = lookup
+}
+
+// panelFor returns the panel of the meet a platform belongs to.
+func panelFor(meetName string) Panel {
+	if panelLookup == nil {
+		return DefaultPanel
+	}
+	meetID, _ := SplitPlatformKey(meetName) // every platform seats the meet's panel
+	panel, ok := panelLookup(meetID)
+	if !ok || len(panel.Referees) == 0 {
+		return DefaultPanel
+	}
+	return panel
+}
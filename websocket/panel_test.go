@@ -0,0 +1,59 @@
+// file: websocket/panel_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// singleReferee is a panel of one referee watched by a jury seat.
+var singleReferee = Panel{Referees: []string{"referee"}, Jury: []string{"jury"}, Chief: "referee"}
+
+func TestPanelFor_DefaultsToThreeReferees(t *testing.T) {
+	defer SetPanelLookup(nil)
+	assert.Equal(t, DefaultPanel, panelFor("PanelMeet"))
+
+	SetPanelLookup(func(meetID string) (Panel, bool) { return Panel{}, false })
+	assert.Equal(t, DefaultPanel, panelFor("PanelMeet"), "unknown meets keep the default panel")
+
+	SetPanelLookup(func(meetID string) (Panel, bool) {
+		assert.Equal(t, "PanelMeet", meetID, "platforms share their meet's panel")
+		return singleReferee, true
+	})
+	assert.Equal(t, singleReferee, panelFor(PlatformKey("PanelMeet", "b")))
+}
+
+func TestProcessDecision_SingleRefereeDecidesAlone(t *testing.T) {
+	InitTest()
+	flushBroadcastChannel()
+	defer SetPanelLookup(nil)
+	SetPanelLookup(func(meetID string) (Panel, bool) { return singleReferee, true })
+	origWindow := defaultTimerManager.Timers.DecisionChange
+	defaultTimerManager.Timers.DecisionChange = 0
+	defer func() { defaultTimerManager.Timers.DecisionChange = origWindow }()
+	ClearMeetState("PanelMeet")
+	defer ClearMeetState("PanelMeet")
+
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "PanelMeet", judgeID: "referee"}
+	processDecision(conn, DecisionMessage{MeetName: "PanelMeet", JudgeID: "referee", Decision: DecisionWhite})
+
+	select {
+	case msg := <-mockBroadcast:
+		var decoded map[string]interface{}
+		assert.NoError(t, json.Unmarshal(msg.Data, &decoded))
+		assert.Equal(t, "displayResults", decoded["action"])
+		assert.Equal(t, []interface{}{"referee"}, decoded["referees"])
+		assert.Equal(t, VerdictGoodLift, decoded["verdict"])
+	default:
+		t.Fatal("one referee's decision should complete the attempt")
+	}
+}
@@ -0,0 +1,20 @@
+// Package websocket - websocket/platform.go
+// file: websocket/platform.go
+
+package websocket
+
+import "go-ref-lights/platform"
+
+// DefaultPlatformID is the platform of a meet that does not list its own.
+const DefaultPlatformID = platform.DefaultID
+
+// PlatformKey is the key a platform's live state, connections and broadcasts are kept
+// under (see platform.Key).
+func PlatformKey(meetID, platformID string) string {
+	return platform.Key(meetID, platformID)
+}
+
+// SplitPlatformKey returns the meet and platform IDs of a PlatformKey.
+func SplitPlatformKey(key string) (meetID, platformID string) {
+	return platform.SplitKey(key)
+}
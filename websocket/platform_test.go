@@ -0,0 +1,18 @@
+// file: websocket/platform_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMeetState_RecordsMeetAndPlatform(t *testing.T) {
+	InitTest()
+	state := DefaultStateProvider.GetMeetState(PlatformKey("spring-open", "b"))
+	assert.Equal(t, "spring-open", state.MeetID)
+	assert.Equal(t, "b", state.PlatformID)
+}
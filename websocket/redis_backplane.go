@@ -0,0 +1,109 @@
+// Package websocket - websocket/redis_backplane.go
+// file: websocket/redis_backplane.go
+
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go-ref-lights/logger"
+)
+
+// DefaultRedisChannel is the pub/sub channel used when none is configured.
+const DefaultRedisChannel = "referee-lights:broadcast"
+
+// redisEnvelope is what travels over the Redis channel.
+type redisEnvelope struct {
+	MeetName string          `json:"meetName"`
+	Msg      json.RawMessage `json:"msg"`
+}
+
+// RedisBackplane fans messages out between instances over Redis pub/sub.
+type RedisBackplane struct {
+	client  *redis.Client
+	channel string
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// NewRedisBackplane connects to the Redis server at url (e.g. redis://localhost:6379/0)
+// and checks it is reachable.
+func NewRedisBackplane(url, channel string) (*RedisBackplane, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if channel == "" {
+		channel = DefaultRedisChannel
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := client.Ping(ctx).Err(); err != nil {
+		cancel()
+		client.Close()
+		return nil, fmt.Errorf("failed to reach Redis: %w", err)
+	}
+	return &RedisBackplane{client: client, channel: channel, ctx: ctx, cancel: cancel}, nil
+}
+
+// Publish implements Backplane.
+func (b *RedisBackplane) Publish(meetName string, msg []byte) error {
+	if meetName == "" {
+		return errNoMeet
+	}
+	if !json.Valid(msg) {
+		return errors.New("backplane messages must be JSON")
+	}
+	payload, err := json.Marshal(redisEnvelope{MeetName: meetName, Msg: msg})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(b.ctx, b.channel, payload).Err()
+}
+
+// Subscribe implements Backplane.
+func (b *RedisBackplane) Subscribe(deliver func(meetName string, msg []byte)) error {
+	sub := b.client.Subscribe(b.ctx, b.channel)
+	defer sub.Close()
+
+	// wait for the subscription to be confirmed so nothing published after we return is missed
+	if _, err := sub.Receive(b.ctx); err != nil {
+		if b.ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to subscribe to %s: %w", b.channel, err)
+	}
+	logger.Info.Printf("[RedisBackplane.Subscribe] Subscribed to channel=%s", b.channel)
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return nil
+		case m, ok := <-ch:
+			if !ok {
+				if b.ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("subscription to %s closed", b.channel)
+			}
+			var env redisEnvelope
+			if err := json.Unmarshal([]byte(m.Payload), &env); err != nil {
+				logger.Warn.Printf("[RedisBackplane.Subscribe] Ignoring malformed message: %v", err)
+				continue
+			}
+			deliver(env.MeetName, env.Msg)
+		}
+	}
+}
+
+// Close implements Backplane.
+func (b *RedisBackplane) Close() error {
+	b.cancel()
+	return b.client.Close()
+}
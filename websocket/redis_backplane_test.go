@@ -0,0 +1,100 @@
+// file: websocket/redis_backplane_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delivered struct {
+	meetName string
+	msg      string
+}
+
+// subscribe starts bp.Subscribe and waits until the subscription is live.
+func subscribe(t *testing.T, srv *miniredis.Miniredis, bp *RedisBackplane, want int) chan delivered {
+	t.Helper()
+	out := make(chan delivered, 10)
+	go func() {
+		_ = bp.Subscribe(func(meetName string, msg []byte) {
+			out <- delivered{meetName, string(msg)}
+		})
+	}()
+	require.Eventually(t, func() bool {
+		return len(srv.PubSubNumSub(DefaultRedisChannel)) > 0 &&
+			srv.PubSubNumSub(DefaultRedisChannel)[DefaultRedisChannel] >= want
+	}, 2*time.Second, 10*time.Millisecond)
+	return out
+}
+
+func TestRedisBackplane_FansOutAcrossInstances(t *testing.T) {
+	srv := miniredis.RunT(t)
+	url := "redis://" + srv.Addr()
+
+	instanceA, err := NewRedisBackplane(url, "")
+	require.NoError(t, err)
+	defer instanceA.Close()
+	instanceB, err := NewRedisBackplane(url, "")
+	require.NoError(t, err)
+	defer instanceB.Close()
+
+	gotA := subscribe(t, srv, instanceA, 1)
+	gotB := subscribe(t, srv, instanceB, 2)
+
+	// a referee on instance A submits; the display on instance B must hear about it
+	require.NoError(t, instanceA.Publish("Meet A", []byte(`{"action":"judgeSubmitted","judgeId":"left"}`)))
+
+	for _, got := range []chan delivered{gotA, gotB} {
+		select {
+		case d := <-got:
+			assert.Equal(t, "Meet A", d.meetName)
+			assert.JSONEq(t, `{"action":"judgeSubmitted","judgeId":"left"}`, d.msg)
+		case <-time.After(2 * time.Second):
+			t.Fatal("message was not delivered")
+		}
+	}
+}
+
+func TestRedisBackplane_RejectsNonJSON(t *testing.T) {
+	srv := miniredis.RunT(t)
+	bp, err := NewRedisBackplane("redis://"+srv.Addr(), "")
+	require.NoError(t, err)
+	defer bp.Close()
+
+	assert.Error(t, bp.Publish("Meet A", []byte("not json")))
+}
+
+func TestRedisBackplane_CloseEndsSubscribe(t *testing.T) {
+	srv := miniredis.RunT(t)
+	bp, err := NewRedisBackplane("redis://"+srv.Addr(), "")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- bp.Subscribe(func(string, []byte) {}) }()
+	require.Eventually(t, func() bool {
+		return srv.PubSubNumSub(DefaultRedisChannel)[DefaultRedisChannel] == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, bp.Close())
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscribe did not return after Close")
+	}
+}
+
+func TestNewRedisBackplane_Unreachable(t *testing.T) {
+	_, err := NewRedisBackplane("redis://127.0.0.1:1", "")
+	assert.Error(t, err)
+
+	_, err = NewRedisBackplane("::not a url::", "")
+	assert.Error(t, err)
+}
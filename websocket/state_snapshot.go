@@ -0,0 +1,74 @@
+// Package websocket - websocket/state_snapshot.go
+// file: websocket/state_snapshot.go
+
+package websocket
+
+import (
+	"time"
+
+	"go-ref-lights/protocol"
+)
+
+// sendStateSnapshot sends one connection the platform as it stands, so a client that
+// connects or reconnects mid-attempt need not wait for the next tick or event.
+func sendStateSnapshot(c *Connection) {
+	sendToConnection(c, buildStateSnapshot(c.meetName, time.Now()))
+}
+
+// buildStateSnapshot collects a platform's clocks, decisions in progress, result on the
+// lights and seat occupancy. Each part is read under the lock that guards it.
+func buildStateSnapshot(meetName string, now time.Time) protocol.StateSnapshot {
+	meetState := DefaultStateProvider.GetMeetState(meetName)
+	panel := panelFor(meetName)
+	snapshot := protocol.StateSnapshot{
+		Header:            protocol.NewHeader(protocol.ActionStateSnapshot, meetName),
+		Seats:             seatsFor(meetName, panel),
+		NextAttemptTimers: []NextAttemptTimer{},
+		Submitted:         []string{},
+	}
+
+	tm := defaultTimerManager
+	tm.platformReadyMutex.Lock()
+	if meetState.PlatformReadyActive {
+		snapshot.PlatformReady = protocol.PlatformReadyState{
+			Active:   true,
+			Paused:   meetState.platformReadyPaused(),
+			TimeLeft: meetState.platformReadySecondsLeft(now),
+		}
+	}
+	tm.platformReadyMutex.Unlock()
+
+	tm.nextAttemptMutex.Lock()
+	snapshot.NextAttemptTimers = append(snapshot.NextAttemptTimers, meetState.NextAttemptTimers...)
+	tm.nextAttemptMutex.Unlock()
+
+	meetState.decisionMu.Lock()
+	defer meetState.decisionMu.Unlock()
+	snapshot.AttemptID = meetState.attempt().ID
+	for _, pos := range panel.Referees {
+		if _, decided := meetState.JudgeDecisions[pos]; decided {
+			snapshot.Submitted = append(snapshot.Submitted, pos)
+		}
+	}
+	snapshot.CurrentAttempt = meetState.CurrentAttempt
+	if last := meetState.lastResult; last != nil && meetState.resultsOnLights == last.AttemptID {
+		snapshot.LastResult = last
+	}
+	return snapshot
+}
+
+// seatsFor returns who holds each seat of the panel; vacant seats are left out.
+func seatsFor(meetName string, panel Panel) map[string]string {
+	seats := map[string]string{}
+	if seatLookup == nil {
+		return seats
+	}
+	for _, positions := range [][]string{panel.Referees, panel.Jury} {
+		for _, pos := range positions {
+			if occupant := seatLookup(meetName, pos); occupant != "" {
+				seats[pos] = occupant
+			}
+		}
+	}
+	return seats
+}
@@ -0,0 +1,79 @@
+// file: websocket/state_snapshot_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-ref-lights/protocol"
+)
+
+func TestBuildStateSnapshot(t *testing.T) {
+	InitTest()
+	ClearMeetState("SnapshotMeet")
+	defer ClearMeetState("SnapshotMeet")
+	defer SetSeatLookup(nil)
+	SetSeatLookup(func(meetName, position string) string {
+		if position == "left" {
+			return "ref1"
+		}
+		return ""
+	})
+
+	now := time.Now()
+	state := GetMeetState("SnapshotMeet")
+	state.JudgeDecisions = map[string]string{"right": DecisionRed, "left": DecisionWhite}
+	state.PlatformReadyActive = true
+	state.PlatformReadyEnd = now.Add(42 * time.Second)
+	state.NextAttemptTimers = []NextAttemptTimer{{ID: 1, TimeLeft: 50, Active: true}}
+	state.CurrentAttempt = &AttemptContext{LifterID: "abc", Lift: "squat", AttemptNumber: 1, WeightKg: 200}
+
+	snapshot := buildStateSnapshot("SnapshotMeet", now)
+	assert.Equal(t, protocol.ActionStateSnapshot, snapshot.Action)
+	assert.Equal(t, state.attempt().ID, snapshot.AttemptID)
+	assert.Equal(t, map[string]string{"left": "ref1"}, snapshot.Seats, "vacant seats are left out")
+	assert.Equal(t, protocol.PlatformReadyState{Active: true, TimeLeft: 42}, snapshot.PlatformReady)
+	assert.Equal(t, state.NextAttemptTimers, snapshot.NextAttemptTimers)
+	assert.Equal(t, []string{"left", "right"}, snapshot.Submitted, "submitted seats are listed in panel order")
+	assert.Equal(t, state.CurrentAttempt, snapshot.CurrentAttempt)
+	assert.Nil(t, snapshot.LastResult, "no result is on the lights")
+
+	// a paused clock keeps its time; a result stays in the snapshot until the lights clear
+	state.PlatformReadyPausedAt = now.Add(-10 * time.Second)
+	result := &protocol.DisplayResults{Header: protocol.Header{Action: protocol.ActionDisplayResults, AttemptID: 3}, Verdict: VerdictGoodLift}
+	state.lastResult = result
+	state.resultsOnLights = 3
+	snapshot = buildStateSnapshot("SnapshotMeet", now)
+	assert.Equal(t, protocol.PlatformReadyState{Active: true, Paused: true, TimeLeft: 52}, snapshot.PlatformReady)
+	assert.Equal(t, result, snapshot.LastResult)
+
+	state.resultsOnLights = 0
+	assert.Nil(t, buildStateSnapshot("SnapshotMeet", now).LastResult)
+}
+
+func TestHandleIncoming_RegisterRefSendsStateSnapshot(t *testing.T) {
+	InitTest()
+	ClearMeetState("SnapshotMeet")
+	defer ClearMeetState("SnapshotMeet")
+	origHealth := broadcastRefereeHealth
+	broadcastRefereeHealth = func(meetName string) {}
+	defer func() { broadcastRefereeHealth = origHealth }()
+
+	conn := &Connection{conn: &fakeConn{}, send: make(chan []byte, 1), meetName: "SnapshotMeet", judgeID: "left"}
+	handleIncoming(conn, DecisionMessage{Action: "registerRef", JudgeID: "left"})
+
+	require.Len(t, conn.send, 1)
+	var snapshot protocol.StateSnapshot
+	require.NoError(t, json.Unmarshal(<-conn.send, &snapshot))
+	assert.Equal(t, protocol.ActionStateSnapshot, snapshot.Action)
+	assert.Equal(t, "SnapshotMeet", snapshot.MeetName)
+	assert.NotNil(t, snapshot.Seats)
+	assert.NotNil(t, snapshot.Submitted)
+}
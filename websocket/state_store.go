@@ -0,0 +1,159 @@
+// Package websocket - websocket/state_store.go
+// file: websocket/state_store.go
+
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-ref-lights/logger"
+	"go-ref-lights/storage"
+)
+
+// meetStateBucket is the storage bucket holding one persistedMeetState per meet.
+const meetStateBucket = "meetState"
+
+// stateStore receives a snapshot of each meet's timers whenever they change.
+// It is nil unless SetStateStore has been called, in which case nothing is persisted.
+var stateStore storage.Store
+
+// persistedMeetState is the part of MeetState that must survive a restart.
+// Deadlines are stored rather than seconds remaining, so time spent down is not given back.
+type persistedMeetState struct {
+	PlatformReadyActive   bool               `json:"platformReadyActive"`
+	PlatformReadyEnd      time.Time          `json:"platformReadyEnd"`
+	PlatformReadyPausedAt time.Time          `json:"platformReadyPausedAt"`
+	NextAttemptTimers     []NextAttemptTimer `json:"nextAttemptTimers"`
+	CurrentAttempt        *AttemptContext    `json:"currentAttempt,omitempty"`
+	PreviousLifterID      string             `json:"previousLifterId,omitempty"`
+	AttemptID             int64              `json:"attemptId,omitempty"`
+}
+
+// SetStateStore configures where meet state is persisted. Pass nil to disable persistence.
+func SetStateStore(store storage.Store) {
+	stateStore = store
+}
+
+// saveMeetState writes the persistent part of a meet's state to the store, taking the
+// default timer manager's locks. Callers must not hold them.
+func saveMeetState(meetState *MeetState) {
+	defaultTimerManager.saveMeetState(meetState)
+}
+
+// saveMeetState writes the persistent part of a meet's state to the store. Each field is
+// read under the lock that guards it, so callers must not hold the timer mutexes or the
+// platform's decision lock.
+func (tm *TimerManager) saveMeetState(meetState *MeetState) {
+	if stateStore == nil {
+		return
+	}
+	// saves are taken and written one at a time, so the last one written is the newest
+	meetState.saveMu.Lock()
+	defer meetState.saveMu.Unlock()
+
+	snapshot := persistedMeetState{NextAttemptTimers: []NextAttemptTimer{}}
+	tm.platformReadyMutex.Lock()
+	snapshot.PlatformReadyActive = meetState.PlatformReadyActive
+	snapshot.PlatformReadyEnd = meetState.PlatformReadyEnd
+	snapshot.PlatformReadyPausedAt = meetState.PlatformReadyPausedAt
+	tm.platformReadyMutex.Unlock()
+
+	tm.nextAttemptMutex.Lock()
+	for _, t := range meetState.NextAttemptTimers {
+		if t.Active {
+			snapshot.NextAttemptTimers = append(snapshot.NextAttemptTimers, t)
+		}
+	}
+	tm.nextAttemptMutex.Unlock()
+
+	meetState.decisionMu.Lock()
+	snapshot.CurrentAttempt = meetState.CurrentAttempt
+	snapshot.PreviousLifterID = meetState.PreviousLifterID
+	snapshot.AttemptID = meetState.attempt().ID
+	meetState.decisionMu.Unlock()
+
+	if err := stateStore.Put(meetStateBucket, meetState.MeetName, snapshot); err != nil {
+		logger.Error.Printf("[saveMeetState] Failed to save state for meet=%s: %v", meetState.MeetName, err)
+	}
+}
+
+// deleteMeetState removes a meet's persisted state.
+func deleteMeetState(meetName string) {
+	if stateStore == nil {
+		return
+	}
+	if err := stateStore.Delete(meetStateBucket, meetName); err != nil {
+		logger.Error.Printf("[deleteMeetState] Failed to delete state for meet=%s: %v", meetName, err)
+	}
+}
+
+// RestoreMeetStates reloads every persisted meet and restarts any platform-ready or
+// next-attempt timer whose deadline has not yet passed. Timers that expired while
+// the server was down are dropped. Platforms another instance serves are skipped.
+func RestoreMeetStates() error {
+	if stateStore == nil {
+		return nil
+	}
+	saved, err := stateStore.List(meetStateBucket)
+	if err != nil {
+		return fmt.Errorf("failed to load saved meet state: %w", err)
+	}
+
+	for meetName, raw := range saved {
+		var ps persistedMeetState
+		if err := json.Unmarshal(raw, &ps); err != nil {
+			logger.Warn.Printf("[RestoreMeetStates] Skipping unreadable state for meet=%s: %v", meetName, err)
+			continue
+		}
+		// only the instance serving a platform resumes its timers and moves its attempt on
+		if err := claimPlatform(meetName); err != nil {
+			logger.Info.Printf("[RestoreMeetStates] Leaving meet=%s alone: %v", meetName, err)
+			continue
+		}
+		defaultTimerManager.restoreMeetState(DefaultStateProvider.GetMeetState(meetName), ps)
+	}
+	return nil
+}
+
+// restoreMeetState applies a persisted snapshot to a meet and resumes its running timers.
+func (tm *TimerManager) restoreMeetState(meetState *MeetState, ps persistedMeetState) {
+	now := time.Now()
+	meetState.decisionMu.Lock()
+	meetState.CurrentAttempt = ps.CurrentAttempt
+	meetState.PreviousLifterID = ps.PreviousLifterID
+	if ps.AttemptID > 0 {
+		// decisions are not kept, so the attempt in progress is over; carry on after it
+		meetState.Attempt = &Attempt{ID: ps.AttemptID}
+		meetState.nextAttempt(AttemptFromRestart)
+	}
+	meetState.decisionMu.Unlock()
+
+	if ps.PlatformReadyActive && !ps.PlatformReadyPausedAt.IsZero() {
+		// a paused clock stays paused with the time it had left, however long we were down
+		logger.Info.Printf("[restoreMeetState] Restoring paused platform ready timer for meet=%s", meetState.MeetName)
+		tm.platformReadyMutex.Lock()
+		meetState.PlatformReadyActive = true
+		meetState.PlatformReadyEnd = ps.PlatformReadyEnd
+		meetState.PlatformReadyPausedAt = ps.PlatformReadyPausedAt
+		meetState.PlatformReadyTimeLeft = meetState.platformReadySecondsLeft(now)
+		tm.platformReadyMutex.Unlock()
+	} else if ps.PlatformReadyActive && ps.PlatformReadyEnd.After(now) {
+		logger.Info.Printf("[restoreMeetState] Resuming platform ready timer for meet=%s, endTime=%v",
+			meetState.MeetName, ps.PlatformReadyEnd)
+		tm.runPlatformReadyTimer(meetState, ps.PlatformReadyEnd, false)
+	}
+
+	for _, t := range ps.NextAttemptTimers {
+		if !t.Active || !t.EndTime.After(now) {
+			continue
+		}
+		logger.Info.Printf("[restoreMeetState] Resuming next attempt timer %d for meet=%s, endTime=%v",
+			t.ID, meetState.MeetName, t.EndTime)
+		tm.resumeNextAttemptTimer(meetState, t)
+	}
+
+	// rewrite the snapshot so timers that expired during the outage are forgotten
+	tm.saveMeetState(meetState)
+}
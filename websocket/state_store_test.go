@@ -0,0 +1,208 @@
+// file: websocket/state_store_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go-ref-lights/storage"
+)
+
+func loadPersisted(t *testing.T, store storage.Store, meetName string) persistedMeetState {
+	t.Helper()
+	all, err := store.List(meetStateBucket)
+	require.NoError(t, err)
+	raw, ok := all[meetName]
+	require.True(t, ok, "expected persisted state for %s", meetName)
+	var ps persistedMeetState
+	require.NoError(t, json.Unmarshal(raw, &ps))
+	return ps
+}
+
+func TestSaveMeetState_PersistsActiveTimersOnly(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+
+	end := time.Now().Add(30 * time.Second).Round(time.Second)
+	meetState := &MeetState{
+		MeetName:            "Save Meet",
+		PlatformReadyActive: true,
+		PlatformReadyEnd:    end,
+		NextAttemptTimers: []NextAttemptTimer{
+			{ID: 1, Active: false, EndTime: end},
+			{ID: 2, Active: true, TimeLeft: 30, EndTime: end},
+		},
+	}
+	saveMeetState(meetState)
+
+	ps := loadPersisted(t, store, "Save Meet")
+	assert.True(t, ps.PlatformReadyActive)
+	assert.True(t, end.Equal(ps.PlatformReadyEnd))
+	require.Len(t, ps.NextAttemptTimers, 1)
+	assert.Equal(t, 2, ps.NextAttemptTimers[0].ID)
+
+	deleteMeetState("Save Meet")
+	all, err := store.List(meetStateBucket)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestSaveMeetState_KeepsTheLatestAttempt(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+	origBroadcast := broadcastToMeet
+	broadcastToMeet = func(meetName string, message []byte) {}
+	defer func() { broadcastToMeet = origBroadcast }()
+
+	ClearMeetState("Save Meet")
+	defer ClearMeetState("Save Meet")
+	meetState := GetMeetState("Save Meet")
+
+	// attempts opening while the state is saved elsewhere race to write it
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			startAttempt(meetState, AttemptFromClock)
+		}()
+		go func() {
+			defer wg.Done()
+			saveMeetState(meetState)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, meetState.attemptID(), loadPersisted(t, store, "Save Meet").AttemptID)
+}
+
+func TestRestoreMeetState_ResumesRunningTimers(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+
+	oldBroadcast := broadcastAllNextAttemptTimersFunc
+	broadcastAllNextAttemptTimersFunc = func(timers []NextAttemptTimer, meetName string) {}
+	defer func() { broadcastAllNextAttemptTimersFunc = oldBroadcast }()
+
+	mockMessenger := new(MockMessenger)
+	mockMessenger.On("BroadcastTimeUpdate", "updatePlatformReadyTime", mock.Anything, 0, "Restored Meet").Maybe()
+	mockMessenger.On("BroadcastToMeet", "Restored Meet", mock.Anything).Maybe()
+
+	tm := &TimerManager{Messenger: mockMessenger, TickerInterval: time.Hour}
+	meetState := &MeetState{MeetName: "Restored Meet", NextAttemptTimers: []NextAttemptTimer{}}
+
+	attempt := validAttempt()
+	prEnd := time.Now().Add(40 * time.Second)
+	ps := persistedMeetState{
+		PlatformReadyActive: true,
+		PlatformReadyEnd:    prEnd,
+		NextAttemptTimers: []NextAttemptTimer{
+			{ID: 7, Active: true, EndTime: time.Now().Add(50 * time.Second)},
+			{ID: 8, Active: true, EndTime: time.Now().Add(-5 * time.Second)}, // expired while down
+		},
+		CurrentAttempt: &attempt,
+		AttemptID:      41,
+	}
+
+	tm.restoreMeetState(meetState, ps)
+	defer meetState.PlatformReadyCancel()
+
+	assert.True(t, meetState.PlatformReadyActive)
+	assert.True(t, prEnd.Equal(meetState.PlatformReadyEnd), "deadline must be kept, not restarted")
+	require.Len(t, meetState.NextAttemptTimers, 1)
+	assert.Equal(t, 7, meetState.NextAttemptTimers[0].ID)
+	assert.InDelta(t, 50, meetState.NextAttemptTimers[0].TimeLeft, 1)
+	assert.Equal(t, 7, tm.nextAttemptIDCounter, "new timers must not reuse restored IDs")
+	assert.Equal(t, &attempt, meetState.CurrentAttempt)
+	assert.Equal(t, int64(42), meetState.Attempt.ID, "decisions in progress were lost, so the next attempt opens")
+	assert.Equal(t, AttemptFromRestart, meetState.Attempt.Source)
+
+	// the expired timer is dropped from the persisted snapshot too
+	saved := loadPersisted(t, store, "Restored Meet")
+	require.Len(t, saved.NextAttemptTimers, 1)
+	assert.Equal(t, 7, saved.NextAttemptTimers[0].ID)
+	assert.Equal(t, int64(42), saved.AttemptID)
+}
+
+func TestRestoreMeetState_DropsExpiredPlatformReady(t *testing.T) {
+	InitTest()
+	SetStateStore(storage.NewMemoryStore())
+	defer SetStateStore(nil)
+
+	mockMessenger := new(MockMessenger)
+	tm := &TimerManager{Messenger: mockMessenger}
+	meetState := &MeetState{MeetName: "Expired Meet"}
+
+	tm.restoreMeetState(meetState, persistedMeetState{
+		PlatformReadyActive: true,
+		PlatformReadyEnd:    time.Now().Add(-time.Second),
+	})
+
+	assert.False(t, meetState.PlatformReadyActive)
+	mockMessenger.AssertNotCalled(t, "BroadcastTimeUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreMeetState_KeepsPausedPlatformReady(t *testing.T) {
+	InitTest()
+	SetStateStore(storage.NewMemoryStore())
+	defer SetStateStore(nil)
+
+	mockMessenger := new(MockMessenger)
+	tm := &TimerManager{Messenger: mockMessenger}
+	meetState := &MeetState{MeetName: "Paused Meet"}
+
+	// paused an hour ago with 25 seconds left; the outage must not eat into them
+	pausedAt := time.Now().Add(-time.Hour)
+	tm.restoreMeetState(meetState, persistedMeetState{
+		PlatformReadyActive:   true,
+		PlatformReadyEnd:      pausedAt.Add(25 * time.Second),
+		PlatformReadyPausedAt: pausedAt,
+	})
+
+	assert.True(t, meetState.PlatformReadyActive)
+	assert.True(t, meetState.platformReadyPaused())
+	assert.Equal(t, 25, meetState.PlatformReadyTimeLeft)
+	mockMessenger.AssertNotCalled(t, "BroadcastTimeUpdate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestoreMeetStates_NoStore(t *testing.T) {
+	SetStateStore(nil)
+	assert.NoError(t, RestoreMeetStates())
+}
+
+func TestRestoreMeetStates_SkipsPlatformsServedElsewhere(t *testing.T) {
+	InitTest()
+	store := storage.NewMemoryStore()
+	SetStateStore(store)
+	defer SetStateStore(nil)
+	ClearMeetState("Elsewhere Meet")
+	defer ClearMeetState("Elsewhere Meet")
+
+	require.NoError(t, store.Put(meetStateBucket, "Elsewhere Meet", persistedMeetState{
+		PlatformReadyActive: true,
+		PlatformReadyEnd:    time.Now().Add(time.Minute),
+		AttemptID:           4,
+	}))
+	leaseElsewhere(t, store, "Elsewhere Meet")
+	require.NoError(t, RestoreMeetStates())
+
+	meetsMutex.Lock()
+	_, restored := meets["Elsewhere Meet"]
+	meetsMutex.Unlock()
+	assert.False(t, restored, "only the instance serving a platform restores it")
+	assert.Equal(t, int64(4), loadPersisted(t, store, "Elsewhere Meet").AttemptID, "the saved attempt is left alone")
+}
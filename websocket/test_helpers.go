@@ -1,7 +1,10 @@
 // Package websocket test_helpers.go
 package websocket
 
-import "time"
+import (
+	"go-ref-lights/config"
+	"go-ref-lights/storage"
+)
 
 // InitTest sets up the test environment for WebSocket-based meet state handling.
 func InitTest() {
@@ -9,12 +12,23 @@ func InitTest() {
 	for len(broadcast) > 0 {
 		<-broadcast
 	}
-	resultsDisplayDuration = 15 // Reset the results display duration if needed.
-	sleepFunc = time.Sleep      // Reset the sleep function to the standard one.
+	// Stop the timers, reveals and result clears left running by earlier tests.
+	meetsMutex.Lock()
+	for _, state := range meets {
+		state.stopTimers()
+	}
+	meetsMutex.Unlock()
+	leasedPlatformsMu.Lock()
+	leasedPlatforms = make(map[string]storage.Store)
+	leasedPlatformsMu.Unlock()
 	// No need to reset getMeetStateFunc since we now use DefaultStateProvider.GetMeetState.
 
-	// Reset the next attempt timer counter if the default timer manager is initialized.
+	// Reset the timer defaults tests may have shortened and the next attempt timer
+	// counter if the default timer manager is initialized.
 	if defaultTimerManager != nil {
+		defaultTimerManager.Timers = config.Default().Timers
+		defaultTimerManager.nextAttemptMutex.Lock()
 		defaultTimerManager.nextAttemptIDCounter = 0
+		defaultTimerManager.nextAttemptMutex.Unlock()
 	}
 }
@@ -6,7 +6,10 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"go-ref-lights/config"
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 	"sync"
 	"time"
 )
@@ -26,21 +29,35 @@ type TimerManager struct {
 	Messenger             Messenger     // Handles message broadcasting
 	TickerInterval        time.Duration // Interval between timer updates
 	NextAttemptStartValue int           // Default start value for next attempt timers
+	Timers                config.Timers // Default durations for meets without a timer profile
 	nextAttemptMutex      sync.Mutex    // Mutex for next attempt timers
 	platformReadyMutex    sync.Mutex    // Mutex for platform readiness timer
 	nextAttemptIDCounter  int           // Counter for next attempt timers
 }
 
-// init sets up the default timer manager.
+// init sets up the default timer manager with the development timer defaults.
 func init() {
-	defaultTimerManager = &TimerManager{
+	defaultTimerManager = NewTimerManager(config.Default().Timers)
+}
+
+// NewTimerManager creates a TimerManager for the shared meet states that falls back
+// to the given durations for meets without a timer profile.
+func NewTimerManager(timers config.Timers) *TimerManager {
+	return &TimerManager{
 		Provider:              DefaultStateProvider,
 		Messenger:             defaultMessenger,
 		TickerInterval:        1 * time.Second, // default 1s interval
-		NextAttemptStartValue: 60,              // default 60s for next attempt
+		NextAttemptStartValue: int(timers.NextAttempt / time.Second),
+		Timers:                timers,
 	}
 }
 
+// SetTimerManager replaces the TimerManager that runs every meet's clocks. Call it
+// once at startup, before any connection is accepted or timer started.
+func SetTimerManager(tm *TimerManager) {
+	defaultTimerManager = tm
+}
+
 // --------------------- timer action handler ---------------------
 
 // HandleTimerAction processes different timer actions like "startTimer", "resetTimer", etc.
@@ -53,27 +70,35 @@ func (tm *TimerManager) HandleTimerAction(action, meetName string) {
 	switch action {
 	case "startTimer":
 		// Clear previous decisions and notify clients to clear results
-		logger.Info.Printf("[HandleTimerAction] Clearing old decisions, sending 'clearResults'")
-		meetState.JudgeDecisions = make(map[string]string)
-		clearMsg := map[string]string{"action": "clearResults"}
-		clearJSON, _ := json.Marshal(clearMsg)
-		tm.Messenger.BroadcastRaw(clearJSON)
+		logger.Info.Printf("[HandleTimerAction] Opening the next attempt, sending 'clearResults'")
+		clearLights(meetState)
+		startAttempt(meetState, AttemptFromClock)
+		tm.Messenger.BroadcastToMeet(meetName, meetAction(protocol.ActionClearResults, meetName))
 
 		// Explicitly cancel any active platform ready timer
 		CancelPlatformReadyTimer(meetName)
 
 		// Start the platform ready timer
-		tm.Messenger.BroadcastMessage(meetName, map[string]interface{}{"action": "startTimer"})
+		tm.Messenger.BroadcastMessage(meetName, protocol.StartTimer{Header: protocol.Header{Action: protocol.ActionStartTimer}})
 		logger.Info.Printf("[HandleTimerAction] Now calling startPlatformReadyTimer for meet='%s'", meetName)
 		tm.startPlatformReadyTimer(meetState)
 
 	case "resetTimer":
 		logger.Info.Printf("[HandleTimerAction] 🔄 Processing resetTimer action for meet='%s'", meetName)
 		tm.resetPlatformReadyTimer(meetState)
-		meetState.JudgeDecisions = make(map[string]string)
-		clearMsg := map[string]string{"action": "clearResults"}
-		clearJSON, _ := json.Marshal(clearMsg)
-		tm.Messenger.BroadcastRaw(clearJSON)
+		clearLights(meetState)
+		clearAttempt(meetState)
+		tm.Messenger.BroadcastToMeet(meetName, meetAction(protocol.ActionClearResults, meetName))
+
+	case "pauseTimer":
+		if err := tm.PauseTimer(meetName); err != nil {
+			logger.Warn.Printf("[HandleTimerAction] pauseTimer for meet='%s': %v", meetName, err)
+		}
+
+	case "resumeTimer":
+		if err := tm.ResumeTimer(meetName); err != nil {
+			logger.Warn.Printf("[HandleTimerAction] resumeTimer for meet='%s': %v", meetName, err)
+		}
 
 	case "startNextAttemptTimer":
 		logger.Info.Printf("[HandleTimerAction] Now calling startNextAttemptTimer for meet='%s'", meetName)
@@ -93,38 +118,58 @@ func (tm *TimerManager) HandleTimerAction(action, meetName string) {
 
 // -------------------- platform ready timer management --------------------
 
-// startPlatformReadyTimer starts a 60-second platform readiness timer
+// startPlatformReadyTimer starts a platform readiness timer using the meet's timer profile
 func (tm *TimerManager) startPlatformReadyTimer(meetState *MeetState) {
 	logger.Info.Printf("[startPlatformReadyTimer] Called for meet='%s'", meetState.MeetName)
+	tm.runPlatformReadyTimer(meetState, time.Now().Add(platformReadyFor(meetState)), true)
+}
 
+// runPlatformReadyTimer counts the platform ready timer down to endTime. Lights are
+// cleared for a fresh start but left alone when resuming a timer after a restart.
+func (tm *TimerManager) runPlatformReadyTimer(meetState *MeetState, endTime time.Time, clearLights bool) {
 	tm.platformReadyMutex.Lock()
+	ctx, timerID := tm.armPlatformReadyTimer(meetState, endTime)
+	tm.platformReadyMutex.Unlock()
+	tm.countDownPlatformReady(ctx, meetState, timerID, endTime, clearLights)
+}
+
+// armPlatformReadyTimer makes endTime the deadline of the platform ready timer,
+// replacing any timer already running. It returns the context and ID the countdown
+// started by countDownPlatformReady runs under. Callers hold platformReadyMutex.
+func (tm *TimerManager) armPlatformReadyTimer(meetState *MeetState, endTime time.Time) (context.Context, int) {
 	// Cancel existing timer if running
 	if meetState.PlatformReadyCancel != nil {
 		meetState.PlatformReadyCancel()
 	}
 
-	// Create a new cancellable context
-	ctx, cancel := context.WithCancel(context.Background())
+	// Create a new cancellable context, ended too when the platform's timers stop
+	ctx, cancel := context.WithCancel(meetState.timersContext())
 	meetState.PlatformReadyCtx = ctx
 	meetState.PlatformReadyCancel = cancel
 
 	// Increment the timer ID for tracking
 	meetState.PlatformReadyTimerID++
-	localTimerID := meetState.PlatformReadyTimerID
 
 	// Set the single timer to active and store its end time
 	meetState.PlatformReadyActive = true
-	meetState.PlatformReadyEnd = time.Now().Add(60 * time.Second)
-	logger.Info.Printf("[startPlatformReadyTimer] Timer is set to 60s for meet='%s', endTime=%v",
-		meetState.MeetName, meetState.PlatformReadyEnd)
-	tm.platformReadyMutex.Unlock()
+	meetState.PlatformReadyEnd = endTime
+	meetState.PlatformReadyPausedAt = time.Time{}
+	logger.Info.Printf("[runPlatformReadyTimer] Timer is running for meet='%s', endTime=%v",
+		meetState.MeetName, endTime)
+	return ctx, meetState.PlatformReadyTimerID
+}
+
+// countDownPlatformReady saves and announces a timer armed by armPlatformReadyTimer,
+// then ticks it down until it expires, is replaced or is stopped.
+func (tm *TimerManager) countDownPlatformReady(ctx context.Context, meetState *MeetState, timerID int, endTime time.Time, clearLights bool) {
+	tm.saveMeetState(meetState)
 
 	// Clear lights and broadcast initial time left
-	clearMsg := map[string]string{"action": "clearResults"}
-	clearJSON, _ := json.Marshal(clearMsg)
-	tm.Messenger.BroadcastRaw(clearJSON)
+	if clearLights {
+		tm.Messenger.BroadcastToMeet(meetState.MeetName, meetAction(protocol.ActionClearResults, meetState.MeetName))
+	}
 
-	timeLeft := int(meetState.PlatformReadyEnd.Sub(time.Now()).Seconds())
+	timeLeft := int(time.Until(endTime).Seconds())
 	tm.Messenger.BroadcastTimeUpdate("updatePlatformReadyTime", timeLeft, 0, meetState.MeetName)
 
 	// Timer countdown using a ticker
@@ -163,10 +208,11 @@ func (tm *TimerManager) startPlatformReadyTimer(meetState *MeetState) {
 				if timeLeft <= 0 {
 					logger.Info.Printf("[startPlatformReadyTimer] Timer reached 0; marking expired for meet='%s'",
 						meetState.MeetName)
-					tm.Messenger.BroadcastRaw([]byte(`{"action":"platformReadyExpired"}`))
+					tm.Messenger.BroadcastToMeet(meetState.MeetName, meetAction(protocol.ActionPlatformReadyExpired, meetState.MeetName))
 					meetState.PlatformReadyActive = false
 					meetState.PlatformReadyEnd = time.Time{}
 					tm.platformReadyMutex.Unlock()
+					tm.saveMeetState(meetState)
 					return
 				}
 
@@ -179,56 +225,188 @@ func (tm *TimerManager) startPlatformReadyTimer(meetState *MeetState) {
 				return
 			}
 		}
-	}(ctx, localTimerID)
+	}(ctx, timerID)
 }
 
-// resetPlatformReadyTimer stops the platform ready timer.
+// resetPlatformReadyTimer stops the platform ready timer and puts the meet's
+// profile clock back on the lights.
 func (tm *TimerManager) resetPlatformReadyTimer(meetState *MeetState) {
+	resetTo := platformReadyFor(meetState)
 	tm.platformReadyMutex.Lock()
-	defer tm.platformReadyMutex.Unlock()
-
 	if !meetState.PlatformReadyActive {
+		tm.platformReadyMutex.Unlock()
 		logger.Warn.Println("[resetPlatformReadyTimer] ⚠️ No active platform ready timer to reset.")
 		return
 	}
 	meetState.PlatformReadyActive = false
-	// Optionally reset the time left to 60 if you want
-	meetState.PlatformReadyTimeLeft = 60
+	meetState.PlatformReadyPausedAt = time.Time{}
+	meetState.PlatformReadyTimeLeft = int(resetTo / time.Second)
+	tm.platformReadyMutex.Unlock()
+	tm.saveMeetState(meetState)
+}
+
+// PauseTimer stops the platform ready clock without losing the time remaining.
+// The lights show the frozen time until ResumeTimer is called.
+func (tm *TimerManager) PauseTimer(meetName string) error {
+	meetState := tm.Provider.GetMeetState(meetName)
+
+	tm.platformReadyMutex.Lock()
+	if !meetState.PlatformReadyActive {
+		tm.platformReadyMutex.Unlock()
+		return fmt.Errorf("no platform ready timer is running")
+	}
+	if meetState.platformReadyPaused() {
+		tm.platformReadyMutex.Unlock()
+		return fmt.Errorf("platform ready timer is already paused")
+	}
+
+	// stop the countdown goroutine; the deadline stays put until resume shifts it
+	now := time.Now()
+	if meetState.PlatformReadyCancel != nil {
+		meetState.PlatformReadyCancel()
+	}
+	meetState.PlatformReadyTimerID++
+	meetState.PlatformReadyPausedAt = now
+	timeLeft := meetState.platformReadySecondsLeft(now)
+	meetState.PlatformReadyTimeLeft = timeLeft
+	tm.platformReadyMutex.Unlock()
+	tm.saveMeetState(meetState)
+
+	logger.Info.Printf("[PauseTimer] Paused platform ready timer for meet='%s' with %ds left", meetName, timeLeft)
+	tm.Messenger.BroadcastMessage(meetName, protocol.PlatformReadyPaused{
+		Header:   protocol.NewHeader(protocol.ActionPlatformReadyPaused, meetName),
+		TimeLeft: timeLeft,
+	})
+	return nil
+}
+
+// ResumeTimer restarts a paused platform ready clock from the time it was paused at.
+func (tm *TimerManager) ResumeTimer(meetName string) error {
+	meetState := tm.Provider.GetMeetState(meetName)
+
+	tm.platformReadyMutex.Lock()
+	if !meetState.PlatformReadyActive || !meetState.platformReadyPaused() {
+		tm.platformReadyMutex.Unlock()
+		return fmt.Errorf("platform ready timer is not paused")
+	}
+	// push the deadline back by however long the clock was stopped; the clock restarts
+	// under the same lock, so a second resume or a reset cannot slip in between
+	endTime := meetState.PlatformReadyEnd.Add(time.Since(meetState.PlatformReadyPausedAt))
+	ctx, timerID := tm.armPlatformReadyTimer(meetState, endTime)
+	tm.platformReadyMutex.Unlock()
+
+	logger.Info.Printf("[ResumeTimer] Resuming platform ready timer for meet='%s', endTime=%v", meetName, endTime)
+	tm.Messenger.BroadcastMessage(meetName, protocol.PlatformReadyResumed{
+		Header:   protocol.NewHeader(protocol.ActionPlatformReadyResumed, meetName),
+		TimeLeft: int(time.Until(endTime).Seconds()),
+	})
+	tm.countDownPlatformReady(ctx, meetState, timerID, endTime, false)
+	return nil
+}
+
+// AdjustTimer adds (or, when negative, removes) seconds from the platform ready clock,
+// whether it is running or paused. The clock never goes below zero; a running clock
+// adjusted to zero expires on its next tick.
+func (tm *TimerManager) AdjustTimer(meetName string, seconds int) error {
+	if seconds == 0 {
+		return fmt.Errorf("adjustment must be a non-zero number of seconds")
+	}
+	meetState := tm.Provider.GetMeetState(meetName)
+
+	tm.platformReadyMutex.Lock()
+	if !meetState.PlatformReadyActive {
+		tm.platformReadyMutex.Unlock()
+		return fmt.Errorf("no platform ready timer is running")
+	}
+
+	now := time.Now()
+	if meetState.platformReadyPaused() {
+		now = meetState.PlatformReadyPausedAt
+	}
+	endTime := meetState.PlatformReadyEnd.Add(time.Duration(seconds) * time.Second)
+	if endTime.Before(now) {
+		endTime = now
+	}
+	meetState.PlatformReadyEnd = endTime
+	timeLeft := meetState.platformReadySecondsLeft(time.Now())
+	meetState.PlatformReadyTimeLeft = timeLeft
+	tm.platformReadyMutex.Unlock()
+	tm.saveMeetState(meetState)
+
+	logger.Info.Printf("[AdjustTimer] Adjusted platform ready timer for meet='%s' by %+ds; %ds left", meetName, seconds, timeLeft)
+	tm.Messenger.BroadcastTimeUpdate("updatePlatformReadyTime", timeLeft, 0, meetName)
+	return nil
 }
 
 // -------------------- next attempt timer management --------------------
 
 // startNextAttemptTimer starts a timer for the next attempt.
 func (tm *TimerManager) startNextAttemptTimer(meetState *MeetState) {
+	attemptID := meetState.attemptID()
 	tm.nextAttemptMutex.Lock()
 	tm.nextAttemptIDCounter++
 	timerID := tm.nextAttemptIDCounter
 
-	// Default the next attempt to 60 seconds (or whatever NextAttemptStartValue is)
+	// Default the next attempt to 60 seconds (or whatever NextAttemptStartValue is),
+	// unless the meet's timer profile says otherwise
 	startVal := 60
 	if tm.NextAttemptStartValue > 0 {
 		startVal = tm.NextAttemptStartValue
 	}
+	if d := activeTimerProfile(meetState.MeetName).NextAttempt; d > 0 {
+		startVal = int(d / time.Second)
+	}
 
 	// Create a new NextAttemptTimer
 	deadline := time.Now().Add(time.Duration(startVal) * time.Second)
 	newTimer := NextAttemptTimer{
-		ID:       timerID,
-		TimeLeft: startVal,
-		Active:   true,
-		EndTime:  deadline,
+		ID:        timerID,
+		AttemptID: attemptID,
+		TimeLeft:  startVal,
+		Active:    true,
+		EndTime:   deadline,
 	}
 	meetState.NextAttemptTimers = append(meetState.NextAttemptTimers, newTimer)
+	timers := append([]NextAttemptTimer(nil), meetState.NextAttemptTimers...)
 	tm.nextAttemptMutex.Unlock()
+	tm.saveMeetState(meetState)
 
 	// Broadcast the updated list of timers
-	broadcastAllNextAttemptTimersFunc(meetState.NextAttemptTimers, meetState.MeetName)
+	broadcastAllNextAttemptTimersFunc(timers, meetState.MeetName)
 
-	// Start the countdown in a separate goroutine
+	tm.runNextAttemptTimer(meetState, timerID)
+}
+
+// resumeNextAttemptTimer re-adds a next attempt timer restored after a restart and
+// counts it down to its original end time.
+func (tm *TimerManager) resumeNextAttemptTimer(meetState *MeetState, timer NextAttemptTimer) {
+	tm.nextAttemptMutex.Lock()
+	// keep new IDs clear of the restored ones
+	if timer.ID > tm.nextAttemptIDCounter {
+		tm.nextAttemptIDCounter = timer.ID
+	}
+	timer.TimeLeft = int(time.Until(timer.EndTime).Seconds())
+	meetState.NextAttemptTimers = append(meetState.NextAttemptTimers, timer)
+	timers := append([]NextAttemptTimer(nil), meetState.NextAttemptTimers...)
+	tm.nextAttemptMutex.Unlock()
+
+	broadcastAllNextAttemptTimersFunc(timers, meetState.MeetName)
+
+	tm.runNextAttemptTimer(meetState, timer.ID)
+}
+
+// runNextAttemptTimer starts the countdown for the next attempt timer with the given ID.
+func (tm *TimerManager) runNextAttemptTimer(meetState *MeetState, timerID int) {
+	ctx := meetState.timersContext()
 	ticker := time.NewTicker(tm.interval())
 	go func(id int) {
 		defer ticker.Stop()
-		for range ticker.C {
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
 			tm.nextAttemptMutex.Lock()
 			idx := findTimerIndex(meetState.NextAttemptTimers, id)
 			if idx == -1 {
@@ -256,6 +434,7 @@ func (tm *TimerManager) startNextAttemptTimer(meetState *MeetState) {
 				// Timer is done
 				meetState.NextAttemptTimers[idx].Active = false
 				tm.nextAttemptMutex.Unlock()
+				tm.saveMeetState(meetState)
 				return
 			}
 			tm.nextAttemptMutex.Unlock()
@@ -265,6 +444,13 @@ func (tm *TimerManager) startNextAttemptTimer(meetState *MeetState) {
 
 // -------------------- timer management utilities --------------------
 
+// meetAction builds a message that is only a header, such as protocol.ClearResults or
+// protocol.PlatformReadyExpired.
+func meetAction(action, meetName string) []byte {
+	msg, _ := json.Marshal(protocol.NewHeader(action, meetName))
+	return msg
+}
+
 // interval returns the ticker interval (defaults to 1 second if unset).
 func (tm *TimerManager) interval() time.Duration {
 	if tm.TickerInterval > 0 {
@@ -7,13 +7,43 @@ package websocket
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go-ref-lights/config"
+	"go-ref-lights/protocol"
 )
 
+// Test: the installed timer manager's durations apply to meets without a timer profile.
+func TestSetTimerManager_AppliesConfiguredTimers(t *testing.T) {
+	InitTest()
+	orig := defaultTimerManager
+	defer SetTimerManager(orig)
+
+	tm := NewTimerManager(config.Timers{
+		ResultsDisplay: 5 * time.Second,
+		PlatformReady:  90 * time.Second,
+		NextAttempt:    45 * time.Second,
+		DecisionChange: 3 * time.Second,
+	})
+	SetTimerManager(tm)
+
+	assert.Equal(t, 45, tm.NextAttemptStartValue)
+	assert.Equal(t, 5*time.Second, resultsDisplayFor("ConfiguredMeet"))
+	assert.Equal(t, 3*time.Second, decisionChangeWindow())
+
+	// New meets start with the configured platform ready length on the clock.
+	state := GetMeetState("ConfiguredMeet")
+	defer ClearMeetState("ConfiguredMeet")
+	assert.Equal(t, 90, state.PlatformReadyTimeLeft)
+	assert.Equal(t, 90*time.Second, platformReadyFor(state))
+}
+
 // Test: startTimer action should clear JudgeDecisions and start the platform timer.
 func TestTimerManager_HandleTimerAction_StartTimer(t *testing.T) {
 	InitTest()
@@ -27,14 +57,14 @@ func TestTimerManager_HandleTimerAction_StartTimer(t *testing.T) {
 	mockProvider.On("GetMeetState", "TestMeet").Return(meetState)
 
 	// Expect a clearResults broadcast.
-	mockMessenger.On("BroadcastRaw", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
-		fmt.Println("BroadcastRaw called with:", args)
+	mockMessenger.On("BroadcastToMeet", "TestMeet", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		fmt.Println("BroadcastToMeet called with:", args)
 	})
 
 	// Expect a BroadcastMessage with action "startTimer".
 	mockMessenger.
-		On("BroadcastMessage", "TestMeet", mock.MatchedBy(func(msg map[string]interface{}) bool {
-			return msg["action"] == "startTimer"
+		On("BroadcastMessage", "TestMeet", mock.MatchedBy(func(msg protocol.StartTimer) bool {
+			return msg.Action == "startTimer"
 		})).
 		Once()
 	// Optionally allow BroadcastTimeUpdate calls.
@@ -51,10 +81,12 @@ func TestTimerManager_HandleTimerAction_StartTimer(t *testing.T) {
 	tm.HandleTimerAction("startTimer", "TestMeet")
 
 	assert.Equal(t, 0, len(meetState.JudgeDecisions), "JudgeDecisions should be cleared")
-	assert.True(t, meetState.PlatformReadyActive, "PlatformReadyActive should be true")
 
 	// Force timer expiry.
+	tm.platformReadyMutex.Lock()
+	assert.True(t, meetState.PlatformReadyActive, "PlatformReadyActive should be true")
 	meetState.PlatformReadyEnd = time.Now().Add(-1 * time.Second)
+	tm.platformReadyMutex.Unlock()
 	time.Sleep(1100 * time.Millisecond)
 
 	mockProvider.AssertExpectations(t)
@@ -88,8 +120,10 @@ func TestTimerManager_HandleTimerAction_StartNextAttemptTimer(t *testing.T) {
 
 	tm.HandleTimerAction("startNextAttemptTimer", "TestMeet")
 
+	tm.nextAttemptMutex.Lock()
 	assert.Equal(t, 1, len(meetState.NextAttemptTimers), "Expected one NextAttemptTimer")
 	timer := meetState.NextAttemptTimers[0]
+	tm.nextAttemptMutex.Unlock()
 	assert.Equal(t, 1, timer.TimeLeft, "Timer should start at 1 second")
 	assert.True(t, timer.Active, "Timer should be active")
 
@@ -117,7 +151,7 @@ func TestTimerManager_HandleTimerAction_ResetTimer(t *testing.T) {
 	mockProvider.On("GetMeetState", "TestMeet").Return(meetState)
 
 	mockMessenger.
-		On("BroadcastRaw", mock.MatchedBy(func(msg []byte) bool {
+		On("BroadcastToMeet", "TestMeet", mock.MatchedBy(func(msg []byte) bool {
 			var m map[string]string
 			_ = json.Unmarshal(msg, &m)
 			return m["action"] == "clearResults"
@@ -179,3 +213,125 @@ func TestTimerManager_HandleTimerAction_InvalidAction(t *testing.T) {
 	assert.Equal(t, 1, len(meetState.JudgeDecisions), "Invalid action should not modify JudgeDecisions")
 	mockProvider.AssertExpectations(t)
 }
+
+// pausableTimer returns a timer manager with a running platform ready clock for "PauseMeet".
+func pausableTimer(t *testing.T) (*TimerManager, *MeetState, *MockMessenger) {
+	InitTest()
+	meetState := &MeetState{MeetName: "PauseMeet", JudgeDecisions: map[string]string{}}
+	mockProvider := new(MockStateProvider)
+	mockProvider.On("GetMeetState", "PauseMeet").Return(meetState)
+	mockMessenger := new(MockMessenger)
+	mockMessenger.On("BroadcastTimeUpdate", "updatePlatformReadyTime", mock.Anything, 0, "PauseMeet").Maybe()
+	mockMessenger.On("BroadcastToMeet", "PauseMeet", mock.Anything).Maybe()
+
+	tm := &TimerManager{Provider: mockProvider, Messenger: mockMessenger, TickerInterval: 10 * time.Millisecond}
+	tm.runPlatformReadyTimer(meetState, time.Now().Add(30*time.Second), false)
+	t.Cleanup(func() {
+		if meetState.PlatformReadyCancel != nil {
+			meetState.PlatformReadyCancel()
+		}
+	})
+	return tm, meetState, mockMessenger
+}
+
+// Test: pausing keeps the remaining time, and resuming shifts the deadline by the pause.
+func TestTimerManager_PauseAndResume(t *testing.T) {
+	tm, meetState, mockMessenger := pausableTimer(t)
+	mockMessenger.On("BroadcastMessage", "PauseMeet", mock.MatchedBy(func(msg protocol.PlatformReadyPaused) bool {
+		return msg.Action == "platformReadyPaused"
+	})).Once()
+	mockMessenger.On("BroadcastMessage", "PauseMeet", mock.MatchedBy(func(msg protocol.PlatformReadyResumed) bool {
+		return msg.Action == "platformReadyResumed"
+	})).Once()
+
+	assert.Error(t, tm.ResumeTimer("PauseMeet"), "a running clock cannot be resumed")
+	require.NoError(t, tm.PauseTimer("PauseMeet"))
+	assert.Error(t, tm.PauseTimer("PauseMeet"), "a paused clock cannot be paused again")
+
+	tm.platformReadyMutex.Lock()
+	assert.True(t, meetState.PlatformReadyActive, "a paused clock is still the active clock")
+	assert.True(t, meetState.platformReadyPaused())
+	assert.InDelta(t, 29, meetState.PlatformReadyTimeLeft, 1)
+	originalEnd := meetState.PlatformReadyEnd
+	tm.platformReadyMutex.Unlock()
+
+	// time spent paused is not taken off the clock
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, tm.ResumeTimer("PauseMeet"))
+
+	tm.platformReadyMutex.Lock()
+	assert.False(t, meetState.platformReadyPaused())
+	assert.True(t, meetState.PlatformReadyEnd.Sub(originalEnd) >= 100*time.Millisecond,
+		"deadline moves back by the paused time")
+	tm.platformReadyMutex.Unlock()
+	mockMessenger.AssertExpectations(t)
+}
+
+// Test: a paused clock resumed from two places at once is only resumed once.
+func TestTimerManager_ResumeOnce(t *testing.T) {
+	tm, meetState, mockMessenger := pausableTimer(t)
+	mockMessenger.On("BroadcastMessage", "PauseMeet", mock.Anything).Maybe()
+	require.NoError(t, tm.PauseTimer("PauseMeet"))
+	tm.platformReadyMutex.Lock()
+	timerID := meetState.PlatformReadyTimerID
+	tm.platformReadyMutex.Unlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	resumed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tm.ResumeTimer("PauseMeet") == nil {
+				mu.Lock()
+				resumed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, resumed)
+	tm.platformReadyMutex.Lock()
+	assert.Equal(t, timerID+1, meetState.PlatformReadyTimerID, "only one countdown should be started")
+	tm.platformReadyMutex.Unlock()
+}
+
+// Test: adjusting moves the deadline for running and paused clocks, never below zero.
+func TestTimerManager_AdjustTimer(t *testing.T) {
+	tm, meetState, mockMessenger := pausableTimer(t)
+	mockMessenger.On("BroadcastMessage", "PauseMeet", mock.Anything).Maybe()
+
+	assert.Error(t, tm.AdjustTimer("PauseMeet", 0))
+
+	require.NoError(t, tm.AdjustTimer("PauseMeet", 15))
+	tm.platformReadyMutex.Lock()
+	assert.InDelta(t, 44, meetState.PlatformReadyTimeLeft, 1)
+	tm.platformReadyMutex.Unlock()
+
+	require.NoError(t, tm.PauseTimer("PauseMeet"))
+	require.NoError(t, tm.AdjustTimer("PauseMeet", -20))
+	tm.platformReadyMutex.Lock()
+	assert.InDelta(t, 24, meetState.PlatformReadyTimeLeft, 1)
+	assert.True(t, meetState.platformReadyPaused(), "adjusting does not resume the clock")
+	tm.platformReadyMutex.Unlock()
+
+	require.NoError(t, tm.AdjustTimer("PauseMeet", -600))
+	tm.platformReadyMutex.Lock()
+	assert.Equal(t, 0, meetState.PlatformReadyTimeLeft)
+	tm.platformReadyMutex.Unlock()
+}
+
+// Test: clock controls need a clock.
+func TestTimerManager_ControlsRequireActiveTimer(t *testing.T) {
+	InitTest()
+	meetState := &MeetState{MeetName: "IdleMeet"}
+	mockProvider := new(MockStateProvider)
+	mockProvider.On("GetMeetState", "IdleMeet").Return(meetState)
+	tm := &TimerManager{Provider: mockProvider, Messenger: new(MockMessenger)}
+
+	assert.Error(t, tm.PauseTimer("IdleMeet"))
+	assert.Error(t, tm.ResumeTimer("IdleMeet"))
+	assert.Error(t, tm.AdjustTimer("IdleMeet", 10))
+}
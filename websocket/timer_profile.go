@@ -0,0 +1,65 @@
+// Package websocket - websocket/timer_profile.go
+package websocket
+
+import (
+	"time"
+
+	"go-ref-lights/logger"
+)
+
+// TimerProfile is the set of clock durations a meet is currently running with.
+// A zero field falls back to the configured default for that clock.
+type TimerProfile struct {
+	PlatformReady      time.Duration // platform ready clock
+	NextAttempt        time.Duration // window to submit the next attempt
+	ConsecutiveAttempt time.Duration // platform clock when a lifter follows themselves (0 = PlatformReady)
+	ResultsDisplay     time.Duration // how long decisions stay on the lights
+}
+
+// timerProfileLookup returns the active profile for a meet. It is nil until
+// SetTimerProfileLookup is called, in which case every meet uses the defaults.
+var timerProfileLookup func(meetName string) (TimerProfile, bool)
+
+// SetTimerProfileLookup wires where per-meet timer profiles come from (the meet
+// configuration). It is consulted every time a clock starts, so edits apply to the next one.
+func SetTimerProfileLookup(lookup func(meetName string) (TimerProfile, bool)) {
+	timerProfileLookup = lookup
+}
+
+// activeTimerProfile returns the profile of the meet a platform belongs to, or a zero
+// profile if it has none.
+func activeTimerProfile(meetName string) TimerProfile {
+	if timerProfileLookup == nil {
+		return TimerProfile{}
+	}
+	meetID, _ := SplitPlatformKey(meetName) // every platform runs the meet's clocks
+	profile, ok := timerProfileLookup(meetID)
+	if !ok {
+		return TimerProfile{}
+	}
+	return profile
+}
+
+// platformReadyFor picks the platform ready clock for the meet's next start. A lifter
+// following themselves gets the profile's consecutive-attempt clock when one is set.
+func platformReadyFor(meetState *MeetState) time.Duration {
+	profile := activeTimerProfile(meetState.MeetName)
+	if profile.ConsecutiveAttempt > 0 && meetState.CurrentAttempt != nil &&
+		meetState.CurrentAttempt.LifterID != "" && meetState.CurrentAttempt.LifterID == meetState.PreviousLifterID {
+		logger.Info.Printf("[platformReadyFor] Lifter %s follows themselves in meet=%s; using %s clock",
+			meetState.CurrentAttempt.LifterID, meetState.MeetName, profile.ConsecutiveAttempt)
+		return profile.ConsecutiveAttempt
+	}
+	if profile.PlatformReady > 0 {
+		return profile.PlatformReady
+	}
+	return defaultTimerManager.Timers.PlatformReady
+}
+
+// resultsDisplayFor returns how long the meet keeps decisions on the lights.
+func resultsDisplayFor(meetName string) time.Duration {
+	if d := activeTimerProfile(meetName).ResultsDisplay; d > 0 {
+		return d
+	}
+	return defaultTimerManager.Timers.ResultsDisplay
+}
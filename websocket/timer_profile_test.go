@@ -0,0 +1,107 @@
+// file: websocket/timer_profile_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// withTimerProfiles installs a lookup serving the given profiles for the duration of a test.
+func withTimerProfiles(t *testing.T, profiles map[string]TimerProfile) {
+	orig := timerProfileLookup
+	SetTimerProfileLookup(func(meetName string) (TimerProfile, bool) {
+		p, ok := profiles[meetName]
+		return p, ok
+	})
+	t.Cleanup(func() { timerProfileLookup = orig })
+}
+
+func TestPlatformReadyFor_UsesMeetProfile(t *testing.T) {
+	InitTest()
+	withTimerProfiles(t, map[string]TimerProfile{
+		"Profiled": {PlatformReady: 90 * time.Second, ConsecutiveAttempt: 3 * time.Minute},
+	})
+
+	assert.Equal(t, defaultTimerManager.Timers.PlatformReady, platformReadyFor(&MeetState{MeetName: "Unprofiled"}),
+		"meets without a profile keep the configured default")
+	assert.Equal(t, 90*time.Second, platformReadyFor(&MeetState{MeetName: "Profiled"}))
+
+	// the same lifter following themselves gets the consecutive-attempt clock
+	followsSelf := &MeetState{
+		MeetName:         "Profiled",
+		CurrentAttempt:   &AttemptContext{LifterID: "lifter-1"},
+		PreviousLifterID: "lifter-1",
+	}
+	assert.Equal(t, 3*time.Minute, platformReadyFor(followsSelf))
+
+	nextLifter := &MeetState{
+		MeetName:         "Profiled",
+		CurrentAttempt:   &AttemptContext{LifterID: "lifter-2"},
+		PreviousLifterID: "lifter-1",
+	}
+	assert.Equal(t, 90*time.Second, platformReadyFor(nextLifter))
+}
+
+func TestResultsDisplayFor_UsesMeetProfile(t *testing.T) {
+	InitTest()
+	withTimerProfiles(t, map[string]TimerProfile{"Profiled": {ResultsDisplay: 5 * time.Second}})
+
+	assert.Equal(t, 5*time.Second, resultsDisplayFor("Profiled"))
+	assert.Equal(t, defaultTimerManager.Timers.ResultsDisplay, resultsDisplayFor("Unprofiled"))
+}
+
+func TestStartNextAttemptTimer_UsesMeetProfile(t *testing.T) {
+	InitTest()
+	withTimerProfiles(t, map[string]TimerProfile{"Profiled": {NextAttempt: 2 * time.Minute}})
+
+	oldBroadcast := broadcastAllNextAttemptTimersFunc
+	broadcastAllNextAttemptTimersFunc = func(timers []NextAttemptTimer, meetName string) {}
+	defer func() { broadcastAllNextAttemptTimersFunc = oldBroadcast }()
+
+	meetState := &MeetState{MeetName: "Profiled", NextAttemptTimers: []NextAttemptTimer{}}
+	mockProvider := new(MockStateProvider)
+	mockProvider.On("GetMeetState", "Profiled").Return(meetState)
+	tm := &TimerManager{
+		Provider:              mockProvider,
+		Messenger:             new(MockMessenger),
+		NextAttemptStartValue: 60,
+		TickerInterval:        time.Hour, // never ticks during the test
+	}
+
+	tm.HandleTimerAction("startNextAttemptTimer", "Profiled")
+
+	tm.nextAttemptMutex.Lock()
+	defer tm.nextAttemptMutex.Unlock()
+	assert.Len(t, meetState.NextAttemptTimers, 1)
+	assert.Equal(t, 120, meetState.NextAttemptTimers[0].TimeLeft, "profile overrides the manager default")
+}
+
+func TestResetPlatformReadyTimer_UsesMeetProfile(t *testing.T) {
+	InitTest()
+	withTimerProfiles(t, map[string]TimerProfile{"Profiled": {PlatformReady: 90 * time.Second}})
+
+	meetState := &MeetState{
+		MeetName:              "Profiled",
+		JudgeDecisions:        map[string]string{},
+		PlatformReadyActive:   true,
+		PlatformReadyTimeLeft: 12,
+	}
+	mockProvider := new(MockStateProvider)
+	mockProvider.On("GetMeetState", "Profiled").Return(meetState)
+	mockMessenger := new(MockMessenger)
+	mockMessenger.On("BroadcastToMeet", "Profiled", mock.Anything).Maybe()
+	tm := &TimerManager{Provider: mockProvider, Messenger: mockMessenger}
+
+	tm.HandleTimerAction("resetTimer", "Profiled")
+
+	tm.platformReadyMutex.Lock()
+	defer tm.platformReadyMutex.Unlock()
+	assert.False(t, meetState.PlatformReadyActive)
+	assert.Equal(t, 90, meetState.PlatformReadyTimeLeft, "reset shows the profile clock, not the default")
+}
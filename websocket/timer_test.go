@@ -24,7 +24,7 @@ type MockMessenger struct {
 	mock.Mock
 }
 
-func (m *MockMessenger) BroadcastMessage(meetName string, msg map[string]interface{}) {
+func (m *MockMessenger) BroadcastMessage(meetName string, msg interface{}) {
 	m.Called(meetName, msg)
 }
 
@@ -32,6 +32,6 @@ func (m *MockMessenger) BroadcastTimeUpdate(action string, timeLeft int, index i
 	m.Called(action, timeLeft, index, meetName)
 }
 
-func (m *MockMessenger) BroadcastRaw(msg []byte) {
-	m.Called(msg)
+func (m *MockMessenger) BroadcastToMeet(meetName string, msg []byte) {
+	m.Called(meetName, msg)
 }
@@ -3,6 +3,7 @@ package websocket
 import (
 	"encoding/json"
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 )
 
 // --------------- utility functions -------------------------------------
@@ -19,12 +20,10 @@ func findTimerIndex(timers []NextAttemptTimer, id int) int {
 
 // broadcastAllNextAttemptTimers sends a message with the current next-attempt timers.
 func broadcastAllNextAttemptTimers(timers []NextAttemptTimer, meetName string) {
-	msg := map[string]interface{}{
-		"action":   "updateNextAttemptTime", // CHANGED: Use this action name for next-attempt updates.
-		"timers":   timers,
-		"meetName": meetName,
-	}
-	out, err := json.Marshal(msg)
+	out, err := json.Marshal(protocol.UpdateNextAttemptTime{
+		Header: protocol.NewHeader(protocol.ActionUpdateNextAttemptTime, meetName),
+		Timers: timers,
+	})
 	if err != nil {
 		logger.Error.Printf("[broadcastAllNextAttemptTimers] Error marshalling next attempt timers: %v", err)
 		return
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"go-ref-lights/logger"
+	"go-ref-lights/protocol"
 )
 
 // StateProvider is an interface for fetching MeetState objects.
@@ -15,35 +16,109 @@ type StateProvider interface {
 	GetMeetState(meetName string) *MeetState
 }
 
-// MeetState holds all state for a meet including timer information and judge decisions.
+// MeetState holds all state for one platform of a meet including timer information
+// and judge decisions.
 type MeetState struct {
-	MeetName              string                     // Name of the meet
+	MeetName              string                     // Platform state key (see PlatformKey)
+	MeetID                string                     // Meet the platform belongs to
+	PlatformID            string                     // Platform within the meet
 	RefereeSessions       map[string]*websocket.Conn // Active referee WebSocket connections
 	JudgeDecisions        map[string]string          // Judge decisions (e.g., left, center, right)
+	JudgeCards            map[string][]string        // Reason cards attached to each judge's red decision
+	JudgeSubmittedAt      map[string]time.Time       // When each judge's decision arrived
+	JudgeLockAt           map[string]time.Time       // When each judge's decision stops being changeable
+	Attempt               *Attempt                   // Attempt under way; never nil
 	PlatformReadyActive   bool                       // Is the Platform Ready timer active?
 	PlatformReadyTimeLeft int                        // Remaining seconds on the timer
 	PlatformReadyEnd      time.Time                  // Time when the timer expires
+	PlatformReadyPausedAt time.Time                  // When the timer was paused; zero while running
 	NextAttemptTimers     []NextAttemptTimer         // Next attempt timers
 	PlatformReadyCtx      context.Context            // Context for the Platform Ready timer
 	PlatformReadyCancel   context.CancelFunc         // Cancel function for the timer
 	PlatformReadyTimerID  int                        // Unique timer ID to help cancel stale timers
+	CurrentAttempt        *AttemptContext            // Lifter on the platform (from OpenLifter), nil if unknown
+	PreviousLifterID      string                     // Lifter of the last completed attempt, for consecutive-attempt clocks
+	decisionMu            sync.Mutex                 // Serialises the platform's decisions, attempts and reveals
+	saveMu                sync.Mutex                 // Serialises writes of the platform's persisted state
+	timersMu              sync.Mutex                 // Guards timersCtx and timersCancel
+	timersCtx             context.Context            // Ends when the platform's timers are stopped
+	timersCancel          context.CancelFunc         // Stops the platform's timers
+	revealPending         bool                       // A reveal is waiting for the last change window to close
+	resultsOnLights       int64                      // Attempt whose result the lights show; zero once cleared
+	lastResult            *protocol.DisplayResults   // Result last sent to the lights, for state snapshots
 }
 
-// NextAttemptTimer represents a timer for the next attempt.
-type NextAttemptTimer struct {
-	ID       int       // Unique ID for the timer
-	TimeLeft int       // Time remaining in seconds
-	Active   bool      // Is the timer active?
-	EndTime  time.Time // For convenience, we store the end time
+// platformReadyPaused reports whether the platform ready timer is stopped mid-countdown.
+func (m *MeetState) platformReadyPaused() bool {
+	return !m.PlatformReadyPausedAt.IsZero()
+}
+
+// platformReadySecondsLeft returns the whole seconds remaining on the platform ready
+// timer. A paused timer keeps the time it had when it was paused.
+func (m *MeetState) platformReadySecondsLeft(now time.Time) int {
+	if m.platformReadyPaused() {
+		now = m.PlatformReadyPausedAt
+	}
+	left := int(m.PlatformReadyEnd.Sub(now).Seconds())
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// timersContext returns the context every timer running for the platform watches:
+// the clocks, a pending reveal and the clear of a result on the lights.
+func (m *MeetState) timersContext() context.Context {
+	m.timersMu.Lock()
+	defer m.timersMu.Unlock()
+	if m.timersCtx == nil {
+		m.timersCtx, m.timersCancel = context.WithCancel(context.Background())
+	}
+	return m.timersCtx
+}
+
+// stopTimers stops every timer running for the platform. Timers started afterwards
+// run as usual.
+func (m *MeetState) stopTimers() {
+	m.timersMu.Lock()
+	defer m.timersMu.Unlock()
+	if m.timersCancel != nil {
+		m.timersCancel()
+	}
+	m.timersCtx, m.timersCancel = nil, nil
 }
 
+// attemptID returns the ID of the attempt under way.
+func (m *MeetState) attemptID() int64 {
+	m.decisionMu.Lock()
+	defer m.decisionMu.Unlock()
+	return m.attempt().ID
+}
+
+// after calls f once d has passed, unless the platform's timers are stopped first.
+func (m *MeetState) after(d time.Duration, f func()) {
+	ctx := m.timersContext()
+	go func() {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			f()
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// NextAttemptTimer represents a timer for the next attempt.
+type NextAttemptTimer = protocol.NextAttemptTimer
+
 // Global map and mutex to store MeetState instances.
 var (
 	meets      = make(map[string]*MeetState)
 	meetsMutex = &sync.Mutex{}
 )
 
-// GetMeetState returns the MeetState for a given meetName.
+// GetMeetState returns the MeetState for a given platform state key (see PlatformKey).
 // If none exists, it creates a new one.
 // (Note: We no longer cancel timers here; use CancelPlatformReadyTimer explicitly.)
 func GetMeetState(meetName string) *MeetState {
@@ -53,12 +128,19 @@ func GetMeetState(meetName string) *MeetState {
 	state, exists := meets[meetName]
 	if !exists {
 		logger.Info.Printf("[GetMeetState] Creating new MeetState for meet=%s", meetName)
+		meetID, platformID := SplitPlatformKey(meetName)
 		state = &MeetState{
 			MeetName:              meetName,
+			MeetID:                meetID,
+			PlatformID:            platformID,
 			RefereeSessions:       make(map[string]*websocket.Conn),
 			JudgeDecisions:        make(map[string]string),
+			JudgeCards:            make(map[string][]string),
+			JudgeSubmittedAt:      make(map[string]time.Time),
+			JudgeLockAt:           make(map[string]time.Time),
+			Attempt:               &Attempt{ID: retiredAttemptIDs[meetName] + 1, Source: AttemptFromPlatform, Phase: PhaseOpen, StartedAt: time.Now()},
 			NextAttemptTimers:     []NextAttemptTimer{},
-			PlatformReadyTimeLeft: 60, // Default (60 seconds)
+			PlatformReadyTimeLeft: int(defaultTimerManager.Timers.PlatformReady / time.Second),
 		}
 		meets[meetName] = state
 	} else {
@@ -68,27 +150,50 @@ func GetMeetState(meetName string) *MeetState {
 	return state
 }
 
+// resetDecisions clears the judge decisions, reason cards and timestamps.
+func (ms *MeetState) resetDecisions() {
+	ms.JudgeDecisions = make(map[string]string)
+	ms.JudgeCards = make(map[string][]string)
+	ms.JudgeSubmittedAt = make(map[string]time.Time)
+	ms.JudgeLockAt = make(map[string]time.Time)
+}
+
 // CancelPlatformReadyTimer explicitly cancels any active platform ready timer for the given meet.
 func CancelPlatformReadyTimer(meetName string) {
 	meetsMutex.Lock()
-	defer meetsMutex.Unlock()
+	state, exists := meets[meetName]
+	meetsMutex.Unlock()
+	if !exists {
+		return
+	}
 
-	if state, exists := meets[meetName]; exists {
-		if state.PlatformReadyCancel != nil {
-			logger.Info.Printf("[CancelPlatformReadyTimer] Cancelling existing platform ready timer for meet=%s", meetName)
-			state.PlatformReadyCancel()
-			state.PlatformReadyCancel = nil
-			state.PlatformReadyActive = false
-		}
+	tm := defaultTimerManager
+	tm.platformReadyMutex.Lock()
+	if state.PlatformReadyCancel == nil {
+		tm.platformReadyMutex.Unlock()
+		return
 	}
+	logger.Info.Printf("[CancelPlatformReadyTimer] Cancelling existing platform ready timer for meet=%s", meetName)
+	state.PlatformReadyCancel()
+	state.PlatformReadyCancel = nil
+	state.PlatformReadyActive = false
+	state.PlatformReadyPausedAt = time.Time{}
+	tm.platformReadyMutex.Unlock()
+	tm.saveMeetState(state)
 }
 
 // ClearMeetState removes a MeetState for a given meetName.
 func ClearMeetState(meetName string) {
+	defer releasePlatform(meetName)
 	meetsMutex.Lock()
 	defer meetsMutex.Unlock()
 
-	if _, exists := meets[meetName]; exists {
+	deleteMeetState(meetName)
+	if state, exists := meets[meetName]; exists {
+		state.stopTimers()
+		// a platform used again carries on from its last attempt ID, so clients still
+		// holding an old ID cannot mistake it for a new one
+		retiredAttemptIDs[meetName] = state.attemptID()
 		delete(meets, meetName)
 		logger.Info.Printf("[ClearMeetState] Cleared MeetState for meet=%s", meetName)
 	} else {
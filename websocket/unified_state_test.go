@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"sync"
 	"testing"
+	"time"
 )
 
 // TestGetMeetStateCreatesNewState verifies that a new MeetState is created with default values.
@@ -99,6 +100,28 @@ func TestGetMeetStateConcurrency(t *testing.T) {
 	}
 }
 
+// TestStopTimers_CancelsPendingTimers verifies that stopped timers never fire and that
+// timers started afterwards still do.
+func TestStopTimers_CancelsPendingTimers(t *testing.T) {
+	state := &MeetState{MeetName: "TimersMeet"}
+	stopped := make(chan struct{}, 1)
+	state.after(20*time.Millisecond, func() { stopped <- struct{}{} })
+	state.stopTimers()
+
+	fired := make(chan struct{}, 1)
+	state.after(0, func() { fired <- struct{}{} })
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("a timer started after stopTimers should fire")
+	}
+	select {
+	case <-stopped:
+		t.Fatal("a stopped timer should not fire")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 //// Optional: TestClearMeetStateForNonexistentMeet verifies that clearing a non-existent state does not panic.
 //func TestClearMeetStateForNonexistentMeet(t *testing.T) {
 //	ClearMeetState("NonexistentMeet")
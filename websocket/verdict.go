@@ -0,0 +1,40 @@
+// Package websocket - websocket/verdict.go
+// file: websocket/verdict.go
+
+package websocket
+
+// Verdicts shown on the lights and sent to every integration.
+const (
+	VerdictGoodLift = "good lift"
+	VerdictNoLift   = "no lift"
+)
+
+// Verdict is the server's ruling on an attempt, computed once so that the lights,
+// the decision log and external integrations all apply the same rule.
+type Verdict struct {
+	Verdict    string `json:"verdict"`
+	WhiteCount int    `json:"whiteCount"`
+	RedCount   int    `json:"redCount"`
+}
+
+// computeVerdict counts white and red decisions and applies the majority rule: a
+// good lift needs whites from more than half of the referees, two of three on the
+// usual panel. A missing judge counts as neither white nor red, so a 1-1 split with
+// an absent judge is a no lift.
+func computeVerdict(decisions map[string]string, referees int) Verdict {
+	var v Verdict
+	for _, d := range decisions {
+		switch d {
+		case DecisionWhite:
+			v.WhiteCount++
+		case DecisionRed:
+			v.RedCount++
+		}
+	}
+	if v.WhiteCount*2 > referees {
+		v.Verdict = VerdictGoodLift
+	} else {
+		v.Verdict = VerdictNoLift
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+// file: websocket/verdict_test.go
+//go:build unit
+// +build unit
+
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeVerdict_FullPanel(t *testing.T) {
+	tests := []struct {
+		name      string
+		decisions map[string]string
+		want      Verdict
+	}{
+		{"three whites", map[string]string{"left": "white", "center": "white", "right": "white"}, Verdict{VerdictGoodLift, 3, 0}},
+		{"two whites", map[string]string{"left": "white", "center": "red", "right": "white"}, Verdict{VerdictGoodLift, 2, 1}},
+		{"two reds", map[string]string{"left": "red", "center": "white", "right": "red"}, Verdict{VerdictNoLift, 1, 2}},
+		{"three reds", map[string]string{"left": "red", "center": "red", "right": "red"}, Verdict{VerdictNoLift, 0, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, computeVerdict(tt.decisions, 3))
+		})
+	}
+}
+
+func TestComputeVerdict_MissingJudges(t *testing.T) {
+	tests := []struct {
+		name      string
+		decisions map[string]string
+		want      Verdict
+	}{
+		{"tie with one judge missing", map[string]string{"left": "white", "right": "red"}, Verdict{VerdictNoLift, 1, 1}},
+		{"two whites with one judge missing", map[string]string{"left": "white", "center": "white"}, Verdict{VerdictGoodLift, 2, 0}},
+		{"one white only", map[string]string{"center": "white"}, Verdict{VerdictNoLift, 1, 0}},
+		{"empty decision counts as missing", map[string]string{"left": "white", "center": "", "right": "red"}, Verdict{VerdictNoLift, 1, 1}},
+		{"no judges", map[string]string{}, Verdict{VerdictNoLift, 0, 0}},
+		{"nil map", nil, Verdict{VerdictNoLift, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, computeVerdict(tt.decisions, 3))
+		})
+	}
+}
+
+func TestComputeVerdict_IgnoresUnknownValues(t *testing.T) {
+	v := computeVerdict(map[string]string{"left": "good", "center": "white", "right": "white"}, 3)
+	assert.Equal(t, Verdict{VerdictGoodLift, 2, 0}, v)
+}
+
+func TestComputeVerdict_PanelSize(t *testing.T) {
+	assert.Equal(t, Verdict{VerdictGoodLift, 1, 0}, computeVerdict(map[string]string{"referee": "white"}, 1))
+	assert.Equal(t, Verdict{VerdictNoLift, 0, 1}, computeVerdict(map[string]string{"referee": "red"}, 1))
+
+	five := map[string]string{"a": "white", "b": "white", "c": "red", "d": "red", "e": "white"}
+	assert.Equal(t, Verdict{VerdictGoodLift, 3, 2}, computeVerdict(five, 5))
+	five["e"] = "red"
+	assert.Equal(t, Verdict{VerdictNoLift, 2, 3}, computeVerdict(five, 5))
+}